@@ -3,38 +3,108 @@ package main
 import (
 	"AvitoPVZ/internal/handlers/pvz/close_last_reception"
 	"context"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"AvitoPVZ/internal/config"
+	"AvitoPVZ/internal/handlers/assignments"
+	"AvitoPVZ/internal/handlers/cities"
 	"AvitoPVZ/internal/handlers/dummy_login"
+	"AvitoPVZ/internal/handlers/health"
 	"AvitoPVZ/internal/handlers/login"
+	"AvitoPVZ/internal/handlers/password"
+	"AvitoPVZ/internal/handlers/product_types"
 	"AvitoPVZ/internal/handlers/products"
+	pvzDeactivate "AvitoPVZ/internal/handlers/pvz/deactivate"
 	deleteLastProduct "AvitoPVZ/internal/handlers/pvz/delete_last_product"
+	deleteProduct "AvitoPVZ/internal/handlers/pvz/delete_product"
 	pvzGet "AvitoPVZ/internal/handlers/pvz/get"
+	pvzGetByID "AvitoPVZ/internal/handlers/pvz/get_by_id"
 	pvzPost "AvitoPVZ/internal/handlers/pvz/post"
+	pvzUpdate "AvitoPVZ/internal/handlers/pvz/update"
 	"AvitoPVZ/internal/handlers/receptions"
 	"AvitoPVZ/internal/handlers/register"
+	"AvitoPVZ/internal/handlers/token"
+	"AvitoPVZ/internal/handlers/users"
+	"AvitoPVZ/internal/logging"
+	"AvitoPVZ/internal/metrics"
+	"AvitoPVZ/internal/middleware/errhandler"
 	"AvitoPVZ/internal/middleware/jwt"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/migrations"
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/notifier"
+	assignmentsRepository "AvitoPVZ/internal/repository/assignments"
+	attemptsRepository "AvitoPVZ/internal/repository/attempts"
 	authPool "AvitoPVZ/internal/repository/auth"
 	productsRepository "AvitoPVZ/internal/repository/products"
 	pvzRepository "AvitoPVZ/internal/repository/pvz"
 	receptionsRepository "AvitoPVZ/internal/repository/receptions"
+	referenceRepository "AvitoPVZ/internal/repository/reference"
+	tokensRepository "AvitoPVZ/internal/repository/tokens"
+	"AvitoPVZ/internal/repository/transaction"
+	"AvitoPVZ/internal/server"
+	"AvitoPVZ/internal/tracing"
+	assignmentsUseCase "AvitoPVZ/internal/usecase/assignments"
+	dummyUseCase "AvitoPVZ/internal/usecase/dummy"
 	loginUseCase "AvitoPVZ/internal/usecase/login"
+	passwordsUseCase "AvitoPVZ/internal/usecase/passwords"
 	productsUseCase "AvitoPVZ/internal/usecase/products"
 	pvzUseCase "AvitoPVZ/internal/usecase/pvz"
 	receptionsUseCase "AvitoPVZ/internal/usecase/receptions"
+	referenceUseCase "AvitoPVZ/internal/usecase/reference"
 	registerUseCase "AvitoPVZ/internal/usecase/register"
+	tokensUseCase "AvitoPVZ/internal/usecase/tokens"
+	usersUseCase "AvitoPVZ/internal/usecase/users"
+	"AvitoPVZ/internal/utils"
 )
 
+// bootstrapAdminPasswordEnv - пароль первого администратора передаётся через окружение, чтобы не светиться в списке процессов
+const bootstrapAdminPasswordEnv = "BOOTSTRAP_ADMIN_PASSWORD"
+
 func main() {
-	ctx := context.Background()
-	app := fiber.New()
-	app.Use(logger.New())
+	configPath := flag.String("config", "", "path to config (default $CONFIG_PATH or ./config.yml)")
+	bootstrapAdmin := flag.String("bootstrap-admin", "", "create the first admin with this email (password from "+bootstrapAdminPasswordEnv+") and exit")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	cfg := config.MustConfig(configPath)
+
+	logLevel, err := logging.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		panic(err)
+	}
+	logger := logging.New(os.Stdout, logLevel)
+	// Обработчик ошибок Fiber, сторонние библиотеки и пакет log пишут через этот же логгер
+	slog.SetDefault(logger)
+
+	appMetrics := metrics.New()
+	transaction.DefaultRetry.OnRetry = appMetrics.TransactionRetried
+	transaction.DefaultRetry.Logger = component(logger, "transaction")
+
+	shutdownTracing, err := setupTracing(cfg.Tracing, logger)
+	if err != nil {
+		panic(err)
+	}
+	defer shutdownTracing()
+
+	app := fiber.New(fiberConfig(cfg.App))
+	requests := server.NewRequests()
+	app.Use(requests.Middleware())
+	app.Use(logging.Middleware(component(logger, "http")))
 	app.Use(
 		cors.New(
 			cors.Config{
@@ -51,58 +121,304 @@ func main() {
 				}, ","),
 				AllowCredentials: false,
 				MaxAge:           0,
-				AllowHeaders:     "Authorization, Reset",
-				ExposeHeaders:    "Authorization, Reset",
+				AllowHeaders:     "Authorization, Reset, " + logging.HeaderRequestID,
+				ExposeHeaders:    "Authorization, Reset, Retry-After, " + logging.HeaderRequestID,
 			},
 		),
 	)
+	app.Use(tracing.Middleware())
+	app.Use(appMetrics.Middleware())
+	app.Use(errhandler.Middleware())
 
-	cfg := config.MustConfig(nil)
+	if err := utils.SetCost(cfg.Password.BcryptCost); err != nil {
+		panic(err)
+	}
 
 	if err := cfg.Postgres.MigrationsUp(); err != nil {
 		panic(err)
 	}
 
-	pool := config.NewPostgres(ctx, cfg.Postgres)
+	pool := config.NewPostgres(ctx, cfg.Postgres, tracing.NewQueryTracer())
 	defer pool.Close()
+	appMetrics.RegisterPool("main", pool)
 
 	registerPool := authPool.NewInsertRepo(pool)
 	pvzRepo := pvzRepository.NewPVZRepositoryPostgres(pool)
 	receptionsRepo := receptionsRepository.NewReceptionRepositoryPg(pool)
 	productsRepo := productsRepository.NewProductRepositoryPg(pool)
+	referenceRepo := referenceRepository.NewRepository(pool)
+	tokensRepo := tokensRepository.NewRepository(pool)
+	tokensRepo.Logger = component(logger, "tokens")
+	assignmentsRepo := assignmentsRepository.NewRepository(pool)
+
+	// роли и их права задаются в конфиге; проверка роли при регистрации и смене роли опирается на них
+	policy, err := rbac.NewPolicy(cfg.RBAC)
+	if err != nil {
+		panic(err)
+	}
 
 	registerUC := registerUseCase.NewUseCase(registerPool)
-	loginUC := loginUseCase.NewUseCase(registerPool)
+	registerUC.Logger = component(logger, "register")
+	// вход, смена пароля и запросы сброса ограничиваются одним хранилищем попыток
+	attempts := loginAttempts(cfg.Login, pool, logger)
+	lockout := loginUseCase.Lockout{
+		MaxFailures:   cfg.Login.MaxFailures,
+		MaxIPFailures: cfg.Login.MaxIPFailures,
+		BaseDelay:     cfg.Login.BaseLockout,
+		MaxDelay:      cfg.Login.MaxLockout,
+		Window:        cfg.Login.FailureWindow,
+	}
+	loginUC := loginUseCase.NewUseCase(registerPool, attempts, lockout)
+	loginUC.Metrics = appMetrics
+	loginUC.Logger = component(logger, "login")
 	pvzUC := pvzUseCase.NewPVZUseCase(pvzRepo)
+	pvzUC.Logger = component(logger, "pvz")
 	receptionsUC := receptionsUseCase.NewReceptionUseCase(receptionsRepo)
+	receptionsUC.Metrics = appMetrics
+	receptionsUC.Logger = component(logger, "receptions")
 	productsUC := productsUseCase.NewProductUseCase(productsRepo)
+	productsUC.Metrics = appMetrics
+	productsUC.Logger = component(logger, "products")
+	referenceUC := referenceUseCase.NewUseCase(referenceRepo)
+	referenceUC.Logger = component(logger, "reference")
+	tokensUC := tokensUseCase.NewUseCase(tokensRepo, cfg.JWT.RefreshTTL)
+	tokensUC.Logger = component(logger, "tokens")
+	assignmentsUC := assignmentsUseCase.NewUseCase(assignmentsRepo)
+	assignmentsUC.Logger = component(logger, "assignments")
+	usersUC := usersUseCase.NewUseCase(registerPool, policy)
+	usersUC.Logger = component(logger, "users")
 
-	registerHandler := register.NewHandler(registerUC)
+	resetNotifier, closeNotifier, err := passwordResetNotifier(cfg.Password, logger)
+	if err != nil {
+		panic(err)
+	}
+	defer closeNotifier()
+	passwordsUC := passwordsUseCase.NewUseCase(registerPool, resetNotifier, attempts, lockout, cfg.Password.ResetTTL)
+	passwordsUC.Logger = component(logger, "passwords")
+
+	if *bootstrapAdmin != "" {
+		id, err := usersUC.BootstrapAdmin(ctx, *bootstrapAdmin, os.Getenv(bootstrapAdminPasswordEnv))
+		if errors.Is(err, models.ErrAdminExists) {
+			logger.Info("bootstrap-admin: admin already exists, nothing to do")
+			return
+		}
+		if err != nil {
+			logger.Error("bootstrap-admin failed", slog.Any("error", err))
+			os.Exit(1)
+		}
+		logger.Info("bootstrap-admin: created admin", slog.String("email", *bootstrapAdmin), slog.String("user_id", id))
+		return
+	}
+
+	if err := referenceUC.Refresh(ctx); err != nil {
+		panic(err)
+	}
+	go referenceUC.Watch(ctx)
+
+	registerHandler := register.NewHandler(registerUC, policy)
 	loginHandler := login.NewHandler(loginUC)
-	pvzCreateHandler := pvzPost.NewCreatePVZHandler(pvzUC)
-	receptionsHandler := receptions.NewReceptionHandler(receptionsUC)
-	productsHandler := products.NewProductHandler(productsUC)
-	deleteLastProductHandler := deleteLastProduct.NewProductHandler(productsUC)
-	closeLastReceptionHandler := close_last_reception.NewReceptionHandler(receptionsUC)
+	pvzCreateHandler := pvzPost.NewCreatePVZHandler(pvzUC, referenceUC)
+	receptionsHandler := receptions.NewReceptionHandler(receptionsUC, assignmentsUC)
+	productsHandler := products.NewProductHandler(productsUC, referenceUC, assignmentsUC)
+	deleteLastProductHandler := deleteLastProduct.NewProductHandler(productsUC, assignmentsUC)
+	deleteProductHandler := deleteProduct.NewProductHandler(productsUC, assignmentsUC)
+	closeLastReceptionHandler := close_last_reception.NewReceptionHandler(receptionsUC, assignmentsUC)
 	pvzGetHandler := pvzGet.NewPVZDataHandler(pvzUC)
+	pvzGetByIDHandler := pvzGetByID.NewPVZHandler(pvzUC)
+	pvzUpdateHandler := pvzUpdate.NewUpdatePVZHandler(pvzUC, referenceUC)
+	citiesHandler := cities.NewHandler(referenceUC)
+	productTypesHandler := product_types.NewHandler(referenceUC)
+	pvzDeactivateHandler := pvzDeactivate.NewDeactivatePVZHandler(pvzUC)
 
-	jwtToken := jwt.NewMiddleware(cfg.JWT.Secret)
+	tokenHandler := token.NewHandler(tokensUC)
+	healthHandler := health.NewHandler(
+		health.Check{Name: "postgres", Check: pool.Ping},
+		health.Check{Name: "migrations", Check: func(ctx context.Context) error {
+			return migrations.Verify(ctx, pool)
+		}},
+	)
+	healthHandler.Logger = component(logger, "health")
+	assignmentsHandler := assignments.NewHandler(assignmentsUC)
+	usersHandler := users.NewHandler(usersUC)
+	passwordHandler := password.NewHandler(passwordsUC)
 
-	app.Post("/dummyLogin", dummy_login.DummyLoginHandler, jwtToken.SignedToken)
+	jwtKeys, err := loadJWTKeys(cfg.JWT, logger)
+	if err != nil {
+		panic(err)
+	}
+
+	jwtToken := jwt.NewMiddleware(cfg.JWT.Secret, jwtKeys, cfg.JWT.AccessTTL, jwt.Validation{
+		Issuer:      cfg.JWT.Issuer,
+		Audience:    cfg.JWT.Audience,
+		AllowLegacy: cfg.JWT.AllowLegacyClaims,
+	}, tokensUC)
+	jwtToken.SecretValidUntil = cfg.JWT.SecretValidUntil
+	if jwtKeys != nil && cfg.JWT.SecretValidUntil.After(time.Now()) {
+		logger.Warn("HS256 tokens signed with jwt.secret are still accepted", slog.Time("until", cfg.JWT.SecretValidUntil))
+	}
+
+	app.Get("/healthz", healthHandler.Live)
+	app.Get("/readyz", healthHandler.Ready)
+	app.Get("/metrics", appMetrics.Handler())
+	app.Get("/.well-known/jwks.json", jwtToken.JWKS)
+
+	if cfg.App.DummyLoginEnabled() {
+		logger.Warn("/dummyLogin is enabled: anyone can obtain a token for any role without a password. Never run this configuration in production.",
+			slog.String("env", cfg.App.Env))
+		dummyUC := dummyUseCase.NewUseCase(registerPool, assignmentsRepo)
+		dummyUC.Logger = component(logger, "dummy")
+		app.Post("/dummyLogin", dummy_login.NewHandler(dummyUC, policy).Login, jwtToken.SignedToken)
+	}
 	app.Post("/register", registerHandler.Register)
 	app.Post("/login", loginHandler.Register, jwtToken.SignedToken)
+	app.Post("/token/refresh", tokenHandler.Refresh, jwtToken.SignedToken)
+	app.Post("/logout", jwtToken.CompareToken, tokenHandler.Logout)
+	app.Post("/me/password", jwtToken.CompareToken, passwordHandler.Change)
+	app.Post("/password/reset", passwordHandler.RequestReset)
+	app.Post("/password/reset/confirm", passwordHandler.ConfirmReset)
+
+	app.Post("/pvz", jwtToken.CompareToken, policy.Require(rbac.PVZCreate), pvzCreateHandler.Handle)
+	app.Get("/pvz", jwtToken.CompareToken, policy.Require(rbac.PVZRead), pvzGetHandler.GetPVZData)
+	app.Get("/pvz/:pvzId", jwtToken.CompareToken, policy.Require(rbac.PVZRead), pvzGetByIDHandler.GetPVZ)
+	app.Patch("/pvz/:pvzId", jwtToken.CompareToken, policy.Require(rbac.PVZUpdate), pvzUpdateHandler.Handle)
+	app.Post("/pvz/:pvzId/deactivate", jwtToken.CompareToken, policy.Require(rbac.PVZDeactivate), pvzDeactivateHandler.Handle)
+	app.Post("/pvz/:pvzId/close_last_reception", jwtToken.CompareToken, policy.Require(rbac.ReceptionClose), closeLastReceptionHandler.CloseLastReception)
+	app.Post("/pvz/:pvzId/delete_last_product", jwtToken.CompareToken, policy.Require(rbac.ProductDelete), deleteLastProductHandler.DeleteLastProduct)
+	app.Delete("/pvz/:pvzId/products/:productId", jwtToken.CompareToken, policy.Require(rbac.ProductDelete), deleteProductHandler.DeleteProduct)
+
+	app.Post("/receptions", jwtToken.CompareToken, policy.Require(rbac.ReceptionCreate), receptionsHandler.CreateReception)
+	app.Get("/receptions/:id", jwtToken.CompareToken, policy.Require(rbac.ReceptionRead), receptionsHandler.GetReception)
+	app.Get("/pvz/:pvzId/receptions", jwtToken.CompareToken, policy.Require(rbac.ReceptionRead), receptionsHandler.ListReceptions)
+
+	app.Post("/products", jwtToken.CompareToken, policy.Require(rbac.ProductCreate), productsHandler.CreateProduct)
+	app.Post("/products/batch", jwtToken.CompareToken, policy.Require(rbac.ProductCreate), productsHandler.CreateProductsBatch)
+	app.Get("/products/by-barcode/:code", jwtToken.CompareToken, policy.Require(rbac.ProductRead), productsHandler.GetProductByBarcode)
 
-	app.Post("/pvz", jwtToken.CompareToken, pvzCreateHandler.Handle)
-	app.Get("/pvz", jwtToken.CompareToken, pvzGetHandler.GetPVZData)
-	app.Post("/pvz/:pvzId/close_last_reception", jwtToken.CompareToken, closeLastReceptionHandler.CloseLastReception)
-	app.Post("/pvz/:pvzId/delete_last_product", jwtToken.CompareToken, deleteLastProductHandler.DeleteLastProduct)
+	app.Get("/employees/:userId/pvz", jwtToken.CompareToken, policy.Require(rbac.AssignmentManage), assignmentsHandler.List)
+	app.Put("/employees/:userId/pvz/:pvzId", jwtToken.CompareToken, policy.Require(rbac.AssignmentManage), assignmentsHandler.Assign)
+	app.Delete("/employees/:userId/pvz/:pvzId", jwtToken.CompareToken, policy.Require(rbac.AssignmentManage), assignmentsHandler.Unassign)
 
-	app.Post("/receptions", jwtToken.CompareToken, receptionsHandler.CreateReception)
+	app.Get("/users", jwtToken.CompareToken, policy.Require(rbac.UserManage), usersHandler.List)
+	app.Patch("/users/:id/role", jwtToken.CompareToken, policy.Require(rbac.UserManage), usersHandler.ChangeRole)
+	app.Post("/users/:id/disable", jwtToken.CompareToken, policy.Require(rbac.UserManage), usersHandler.Disable)
+	app.Post("/users/:id/enable", jwtToken.CompareToken, policy.Require(rbac.UserManage), usersHandler.Enable)
+	app.Post("/users/:id/reset_password", jwtToken.CompareToken, policy.Require(rbac.UserManage), usersHandler.ResetPassword)
 
-	app.Post("/products", jwtToken.CompareToken, productsHandler.CreateProduct)
+	app.Get("/cities", jwtToken.CompareToken, policy.Require(rbac.ReferenceRead), citiesHandler.List)
+	app.Post("/cities", jwtToken.CompareToken, policy.Require(rbac.ReferenceWrite), citiesHandler.Create)
+	app.Delete("/cities/:name", jwtToken.CompareToken, policy.Require(rbac.ReferenceWrite), citiesHandler.Delete)
 
-	log.Println(cfg.App.String())
-	if err := app.Listen(cfg.App.String()); err != nil {
-		panic("app not start")
+	app.Get("/product_types", jwtToken.CompareToken, policy.Require(rbac.ReferenceRead), productTypesHandler.List)
+	app.Post("/product_types", jwtToken.CompareToken, policy.Require(rbac.ReferenceWrite), productTypesHandler.Create)
+	app.Delete("/product_types/:name", jwtToken.CompareToken, policy.Require(rbac.ReferenceWrite), productTypesHandler.Delete)
+
+	ln, err := net.Listen("tcp", cfg.App.String())
+	if err != nil {
+		panic(fmt.Sprintf("app not start: %v", err))
+	}
+
+	logger.Info("server started", slog.String("addr", cfg.App.String()))
+	if err := server.Serve(ctx, app, ln, requests, cfg.App.DrainTimeout); err != nil {
+		logger.Error("server stopped with error", slog.Any("error", err))
+	}
+	// Пул закрывается отложенным pool.Close: к этому моменту обработчики завершились
+	// или их контекст отменён по drainTimeout, и соединения возвращаются в пул
+	logger.Info("server stopped")
+}
+
+// component - логгер части приложения: записи получают атрибут component
+func component(logger *slog.Logger, name string) *slog.Logger {
+	return logger.With(slog.String("component", name))
+}
+
+// loginAttempts - хранилище счётчиков неудачных входов; в памяти оно не делится между экземплярами
+func loginAttempts(cfg config.Login, pool *pgxpool.Pool, logger *slog.Logger) loginUseCase.Attempts {
+	if cfg.Store == config.LoginStoreMemory {
+		logger.Info("login attempt counters are kept in memory and are not shared between instances")
+		return attemptsRepository.NewMemory()
+	}
+
+	return attemptsRepository.NewRepository(pool)
+}
+
+// setupTracing включает выгрузку span'ов в файл tracing.output или в stdout.
+// Возвращённая функция дописывает накопленные span'ы и закрывает файл
+func setupTracing(cfg config.Tracing, logger *slog.Logger) (func(), error) {
+	if !cfg.Enabled {
+		return func() {}, nil
+	}
+
+	out := os.Stdout
+	if cfg.Output != "" {
+		f, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("open trace output: %w", err)
+		}
+		out = f
+	}
+
+	shutdown, err := tracing.Setup(out, cfg.ServiceName, cfg.SampleRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdown(ctx); err != nil {
+			logger.Error("failed flush spans", slog.Any("error", err))
+		}
+		if out != os.Stdout {
+			_ = out.Close()
+		}
+	}, nil
+}
+
+// fiberConfig - настройки Fiber. Адрес клиента из ProxyHeader берётся только у запросов
+// от TrustedProxies и только если это корректный IP; иначе - адрес соединения
+func fiberConfig(cfg config.App) fiber.Config {
+	fiberCfg := fiber.Config{ErrorHandler: errhandler.Handle}
+	if cfg.ProxyHeader != "" {
+		fiberCfg.ProxyHeader = cfg.ProxyHeader
+		fiberCfg.EnableTrustedProxyCheck = true
+		fiberCfg.TrustedProxies = cfg.TrustedProxies
+		fiberCfg.EnableIPValidation = true
 	}
+
+	return fiberCfg
+}
+
+// passwordResetNotifier - доставка токенов сброса пароля в файл reset_outbox. Без него
+// (допустимо только вне production) токены отбрасываются и не попадают в журнал
+func passwordResetNotifier(cfg config.Password, logger *slog.Logger) (passwordsUseCase.Notifier, func(), error) {
+	if cfg.ResetOutbox == "" {
+		return notifier.NewDiscard(component(logger, "notifier")), func() {}, nil
+	}
+
+	f, err := os.OpenFile(cfg.ResetOutbox, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open password reset outbox: %w", err)
+	}
+
+	return notifier.NewLog(f), func() { _ = f.Close() }, nil
+}
+
+// loadJWTKeys - набор ключей подписи из конфига; без ключей токены подписываются HS256 на jwt.secret
+func loadJWTKeys(cfg config.JWT, logger *slog.Logger) (*jwt.KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		logger.Warn("no jwt signing keys configured, falling back to HS256 with shared secret")
+		return nil, nil
+	}
+
+	files := make([]jwt.KeyFile, 0, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		files = append(files, jwt.KeyFile{
+			ID:             key.ID,
+			PrivateKeyPath: key.PrivateKey,
+			PublicKeyPath:  key.PublicKey,
+		})
+	}
+
+	return jwt.LoadKeySet(cfg.SigningKey, files)
 }
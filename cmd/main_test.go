@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/config"
+	"AvitoPVZ/internal/logging"
+)
+
+func TestPasswordResetNotifier_WithoutOutboxKeepsTokenOutOfLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelDebug)
+
+	resetNotifier, closeNotifier, err := passwordResetNotifier(config.Password{}, logger)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	defer closeNotifier()
+
+	err = resetNotifier.SendPasswordReset(context.Background(), "user@example.com", "raw-reset-token", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "raw-reset-token") {
+		t.Errorf("токен сброса попал в журнал: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "user@example.com") {
+		t.Errorf("в журнале нет записи о запросе сброса: %s", buf.String())
+	}
+}
+
+func TestFiberConfig_ProxyHeaderOnlyFromTrustedProxies(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     config.App
+		forward string
+		want    string
+	}{
+		{"без proxy_header", config.App{}, "203.0.113.7", "0.0.0.0"},
+		{"недоверенный прокси", config.App{ProxyHeader: fiber.HeaderXForwardedFor, TrustedProxies: []string{"10.0.0.0/8"}}, "203.0.113.7", "0.0.0.0"},
+		{"доверенный прокси", config.App{ProxyHeader: fiber.HeaderXForwardedFor, TrustedProxies: []string{"0.0.0.0"}}, "203.0.113.7", "203.0.113.7"},
+		{"некорректный IP в заголовке", config.App{ProxyHeader: fiber.HeaderXForwardedFor, TrustedProxies: []string{"0.0.0.0"}}, "not-an-ip, 203.0.113.7", "203.0.113.7"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := fiber.New(fiberConfig(tc.cfg))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString(c.IP())
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(fiber.HeaderXForwardedFor, tc.forward)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tc.want {
+				t.Errorf("ожидался IP %q, получен %q", tc.want, body)
+			}
+		})
+	}
+}
@@ -2,29 +2,61 @@ package config
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	"net/url"
 	"os"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pkg/errors"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/ilyakaznacheev/cleanenv"
+
+	"AvitoPVZ/internal/migrations"
 )
 
 type Config struct {
 	App      App      `yaml:"app"`
 	Postgres Postgres `yaml:"postgres"`
 	JWT      JWT      `yaml:"jwt"`
+	Login    Login    `yaml:"login"`
+	Password Password `yaml:"password"`
+	Tracing  Tracing  `yaml:"tracing"`
+	Log      Log      `yaml:"log"`
+	// RBAC - права каждой роли, например employee: [pvz:read, reception:create]
+	RBAC map[string][]string `yaml:"rbac"`
 }
 
+const (
+	EnvDev        = "dev"
+	EnvTest       = "test"
+	EnvProduction = "production"
+)
+
+const (
+	LoginStoreMemory   = "memory"
+	LoginStorePostgres = "postgres"
+)
+
 type App struct {
 	Port string `yaml:"port"`
 	Host string `yaml:"host"`
+	// Env - окружение: dev, test или production. По умолчанию production,
+	// чтобы забытая настройка не включала отладочные маршруты
+	Env string `yaml:"env" env:"APP_ENV" env-default:"production"`
+	// ProxyHeader - заголовок с адресом клиента за балансировщиком, например X-Forwarded-For.
+	// Без него IP клиента берётся из соединения
+	ProxyHeader string `yaml:"proxy_header"`
+	// TrustedProxies - адреса и подсети балансировщиков. Заголовок ProxyHeader читается только
+	// у запросов от них, иначе клиент мог бы подставить любой IP. Обязателен вместе с ProxyHeader
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// DrainTimeout - сколько при остановке ждать завершения начатых запросов
+	DrainTimeout time.Duration `yaml:"drain_timeout" env-default:"15s"`
 }
 
 type Postgres struct {
@@ -36,7 +68,63 @@ type Postgres struct {
 }
 
 type JWT struct {
-	Secret string `yaml:"secret"`
+	Secret     string        `yaml:"secret"`
+	AccessTTL  time.Duration `yaml:"access_ttl" env-default:"15m"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env-default:"720h"`
+	Issuer     string        `yaml:"issuer" env-default:"avito-pvz"`
+	Audience   string        `yaml:"audience" env-default:"avito-pvz-api"`
+	// AllowLegacyClaims - принимать токены прежнего формата до их истечения
+	AllowLegacyClaims bool     `yaml:"allow_legacy_claims" env-default:"true"`
+	SigningKey        string   `yaml:"signing_key"`
+	Keys              []JWTKey `yaml:"keys"`
+	// SecretValidUntil - до какого момента при заданных keys ещё принимаются токены HS256
+	// на secret, выпущенные до перехода. Без него HS256 при заданных keys не принимается
+	SecretValidUntil time.Time `yaml:"secret_valid_until"`
+}
+
+// JWTKey - ключ подписи в PEM. Для ключа, оставленного только для проверки
+// после ротации, достаточно открытой части.
+type JWTKey struct {
+	ID         string `yaml:"id"`
+	PrivateKey string `yaml:"private_key"`
+	PublicKey  string `yaml:"public_key"`
+}
+
+// Login - защита входа от перебора паролей
+type Login struct {
+	MaxFailures   int           `yaml:"max_failures" env-default:"5"`
+	MaxIPFailures int           `yaml:"max_ip_failures" env-default:"20"`
+	BaseLockout   time.Duration `yaml:"base_lockout" env-default:"1m"`
+	MaxLockout    time.Duration `yaml:"max_lockout" env-default:"1h"`
+	FailureWindow time.Duration `yaml:"failure_window" env-default:"15m"`
+	// Store - хранилище счётчиков: memory для одного экземпляра, postgres для нескольких
+	Store string `yaml:"store" env-default:"postgres"`
+}
+
+// Password - хеширование паролей и сброс пароля
+type Password struct {
+	// BcryptCost - стоимость bcrypt; хеши с меньшей стоимостью пересчитываются при входе
+	BcryptCost int           `yaml:"bcrypt_cost" env-default:"12"`
+	ResetTTL   time.Duration `yaml:"reset_ttl" env-default:"30m"`
+	// ResetOutbox - файл, куда дописываются токены сброса. Обязателен в production:
+	// без него токены не доставляются, а в журнал попадает только факт запроса
+	ResetOutbox string `yaml:"reset_outbox"`
+}
+
+// Log - структурированный журнал в формате JSON
+type Log struct {
+	// Level - debug, info, warn или error
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+}
+
+// Tracing - трассировка OpenTelemetry с выгрузкой span'ов в JSON
+type Tracing struct {
+	Enabled     bool   `yaml:"enabled" env:"TRACING_ENABLED"`
+	ServiceName string `yaml:"service_name" env-default:"avito-pvz"`
+	// Output - файл для span'ов; без него они пишутся в stdout
+	Output string `yaml:"output"`
+	// SampleRatio - доля новых трасс от 0 до 1; трассы с traceparent следуют решению клиента
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
 }
 
 func New() *Config {
@@ -46,14 +134,16 @@ func New() *Config {
 	}
 }
 
+// MustConfig читает конфиг по пути p (значение уже разобранного флага -config),
+// при пустом пути - из CONFIG_PATH, иначе ./config.yml. Флаги разбирает вызывающий код
 func MustConfig(p *string) *Config {
 	var path string
-	if p == nil {
-		path = fetchConfigPath()
-	} else {
+	if p != nil {
 		path = *p
 	}
-
+	if path == "" {
+		path = os.Getenv("CONFIG_PATH")
+	}
 	if path == "" {
 		path = "./config.yml"
 	}
@@ -68,12 +158,42 @@ func MustConfig(p *string) *Config {
 		panic("failed to read config: " + err.Error())
 	}
 
+	switch cfg.App.Env {
+	case EnvDev, EnvTest, EnvProduction:
+	default:
+		panic("unknown app env: " + cfg.App.Env)
+	}
+
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		panic(fmt.Sprintf("tracing.sample_ratio must be between 0 and 1, got %v", cfg.Tracing.SampleRatio))
+	}
+
+	if cfg.App.ProxyHeader != "" && len(cfg.App.TrustedProxies) == 0 {
+		panic("app.trusted_proxies is required with app.proxy_header: otherwise clients can spoof their IP")
+	}
+
+	if cfg.App.Env == EnvProduction && cfg.Password.ResetOutbox == "" {
+		panic("password.reset_outbox is required in production: reset tokens have no other delivery channel")
+	}
+
+	switch cfg.Login.Store {
+	case LoginStoreMemory, LoginStorePostgres:
+	default:
+		panic("unknown login attempts store: " + cfg.Login.Store)
+	}
+
 	return cfg
 }
 
-func NewPostgres(ctx context.Context, cfg Postgres) *pgxpool.Pool {
-	pool, err := pgxpool.New(ctx, cfg.String())
-	fmt.Println("config database: ", cfg.String())
+// NewPostgres создаёт пул соединений; tracer, если задан, получает каждый запрос
+func NewPostgres(ctx context.Context, cfg Postgres, tracer pgx.QueryTracer) *pgxpool.Pool {
+	poolCfg, err := pgxpool.ParseConfig(cfg.String())
+	if err != nil {
+		panic("invalid database config: " + err.Error())
+	}
+	poolCfg.ConnConfig.Tracer = tracer
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		panic("no connect to database")
 	}
@@ -81,17 +201,9 @@ func NewPostgres(ctx context.Context, cfg Postgres) *pgxpool.Pool {
 	return pool
 }
 
-func fetchConfigPath() string {
-	var res string
-
-	flag.StringVar(&res, "config", "", "path to config")
-	flag.Parse()
-
-	if res == "" {
-		res = os.Getenv("CONFIG_PATH")
-	}
-
-	return res
+// DummyLoginEnabled - /dummyLogin выдаёт токен любой роли без пароля, поэтому доступен только в dev и test
+func (f App) DummyLoginEnabled() bool {
+	return f.Env == EnvDev || f.Env == EnvTest
 }
 
 func (f App) String() string {
@@ -114,15 +226,21 @@ func (p *Postgres) String() string {
 	return u.String()
 }
 
+// MigrationsUp применяет миграции, встроенные в бинарник, или миграции из url, если он передан
 func (p *Postgres) MigrationsUp(url ...string) error {
-	var sourceURL string
+	var (
+		m   *migrate.Migrate
+		err error
+	)
 	if url == nil {
-		sourceURL = "file://internal/migrations/up"
+		var src source.Driver
+		if src, err = migrations.Source(); err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		m, err = migrate.NewWithSourceInstance("iofs", src, p.String())
 	} else {
-		sourceURL = url[0]
+		m, err = migrate.New(url[0], p.String())
 	}
-	fmt.Println(p.String())
-	m, err := migrate.New(sourceURL, p.String())
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
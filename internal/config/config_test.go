@@ -4,7 +4,9 @@ import (
 	"AvitoPVZ/internal/config"
 	"github.com/stretchr/testify/suite"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 type ConfigSuite struct {
@@ -25,6 +27,17 @@ postgres:
   dbname: "dbname"
 jwt:
   secret: "supersecret"
+  signing_key: "2026-10"
+  keys:
+    - id: "2026-10"
+      private_key: "keys/2026-10.pem"
+    - id: "2026-04"
+      public_key: "keys/2026-04.pub.pem"
+password:
+  reset_outbox: "/tmp/password_resets.log"
+rbac:
+  moderator: ["pvz:create", "pvz:read"]
+  auditor: ["pvz:read"]
 `)
 	tmpFile, err := os.CreateTemp("", "config_test_*.yml")
 	s.Require().NoError(err)
@@ -45,12 +58,96 @@ func (s *ConfigSuite) TestMustConfig_WithValidPath() {
 
 	s.Equal("8080", cfg.App.Port)
 	s.Equal("localhost", cfg.App.Host)
+	s.Equal(config.EnvProduction, cfg.App.Env)
+	s.False(cfg.App.DummyLoginEnabled())
+	s.Equal(15*time.Second, cfg.App.DrainTimeout)
 	s.Equal("localhost", cfg.Postgres.Host)
 	s.Equal(5432, cfg.Postgres.Port)
 	s.Equal("user", cfg.Postgres.User)
 	s.Equal("password", cfg.Postgres.Password)
 	s.Equal("dbname", cfg.Postgres.DBName)
 	s.Equal("supersecret", cfg.JWT.Secret)
+	s.Equal(15*time.Minute, cfg.JWT.AccessTTL)
+	s.Equal(720*time.Hour, cfg.JWT.RefreshTTL)
+	s.Equal("avito-pvz", cfg.JWT.Issuer)
+	s.Equal("avito-pvz-api", cfg.JWT.Audience)
+	s.True(cfg.JWT.AllowLegacyClaims)
+	s.Equal("2026-10", cfg.JWT.SigningKey)
+	s.Equal([]config.JWTKey{
+		{ID: "2026-10", PrivateKey: "keys/2026-10.pem"},
+		{ID: "2026-04", PublicKey: "keys/2026-04.pub.pem"},
+	}, cfg.JWT.Keys)
+	s.Equal(map[string][]string{
+		"moderator": {"pvz:create", "pvz:read"},
+		"auditor":   {"pvz:read"},
+	}, cfg.RBAC)
+	s.Equal(config.Login{
+		MaxFailures:   5,
+		MaxIPFailures: 20,
+		BaseLockout:   time.Minute,
+		MaxLockout:    time.Hour,
+		FailureWindow: 15 * time.Minute,
+		Store:         config.LoginStorePostgres,
+	}, cfg.Login)
+	s.Equal(config.Password{BcryptCost: 12, ResetTTL: 30 * time.Minute, ResetOutbox: "/tmp/password_resets.log"}, cfg.Password)
+}
+
+func (s *ConfigSuite) TestMustConfig_ProductionRequiresResetOutbox() {
+	path := s.writeConfig(`
+app:
+  env: production
+jwt:
+  secret: "supersecret"
+`)
+
+	s.PanicsWithValue("password.reset_outbox is required in production: reset tokens have no other delivery channel", func() {
+		config.MustConfig(&path)
+	})
+}
+
+func (s *ConfigSuite) TestMustConfig_DevWithoutResetOutbox() {
+	path := s.writeConfig(`
+app:
+  env: dev
+jwt:
+  secret: "supersecret"
+`)
+
+	s.Empty(config.MustConfig(&path).Password.ResetOutbox)
+}
+
+func (s *ConfigSuite) TestMustConfig_ProxyHeaderRequiresTrustedProxies() {
+	path := s.writeConfig(`
+app:
+  env: dev
+  proxy_header: "X-Forwarded-For"
+`)
+
+	s.PanicsWithValue("app.trusted_proxies is required with app.proxy_header: otherwise clients can spoof their IP", func() {
+		config.MustConfig(&path)
+	})
+}
+
+func (s *ConfigSuite) TestMustConfig_EmptyPathFallsBackToEnv() {
+	s.T().Setenv("CONFIG_PATH", s.tempConfigPath)
+	empty := ""
+
+	s.Equal("8080", config.MustConfig(&empty).App.Port)
+	s.Equal("8080", config.MustConfig(nil).App.Port)
+}
+
+func (s *ConfigSuite) writeConfig(content string) string {
+	path := filepath.Join(s.T().TempDir(), "config.yml")
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func (s *ConfigSuite) TestApp_DummyLoginEnabled() {
+	s.True(config.App{Env: config.EnvDev}.DummyLoginEnabled())
+	s.True(config.App{Env: config.EnvTest}.DummyLoginEnabled())
+	s.False(config.App{Env: config.EnvProduction}.DummyLoginEnabled())
+	s.False(config.App{}.DummyLoginEnabled())
 }
 
 func (s *ConfigSuite) TestPostgres_String() {
@@ -0,0 +1,13 @@
+// Package access - общие для обработчиков проверки доступа к данным ПВЗ
+package access
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PVZAccess - проверка, что пользователь закреплён за ПВЗ. Реализуется use case'ом закреплений
+type PVZAccess interface {
+	CheckPVZAccess(ctx context.Context, userID uuid.UUID, pvzID string) error
+}
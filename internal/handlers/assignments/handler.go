@@ -0,0 +1,72 @@
+package assignments
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+type AssignmentUseCase interface {
+	Assign(ctx context.Context, userID, pvzID uuid.UUID) (models.Assignment, error)
+	Unassign(ctx context.Context, userID, pvzID uuid.UUID) error
+	List(ctx context.Context, userID uuid.UUID) ([]models.Assignment, error)
+}
+
+type Handler struct {
+	UC AssignmentUseCase
+}
+
+func NewHandler(uc AssignmentUseCase) *Handler {
+	return &Handler{UC: uc}
+}
+
+// List - ПВЗ, закреплённые за сотрудником
+func (h *Handler) List(c *fiber.Ctx) error {
+	req := assignmentReq{UserID: c.Params("userId")}
+	userID, _, err := req.validate()
+	if err != nil {
+		return err
+	}
+
+	assignments, err := h.UC.List(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(assignments)
+}
+
+func (h *Handler) Assign(c *fiber.Ctx) error {
+	req := assignmentReq{UserID: c.Params("userId"), PvzID: c.Params("pvzId")}
+	userID, pvzID, err := req.validate()
+	if err != nil {
+		return err
+	}
+
+	assignment, err := h.UC.Assign(c.UserContext(), userID, pvzID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(assignment)
+}
+
+func (h *Handler) Unassign(c *fiber.Ctx) error {
+	req := assignmentReq{UserID: c.Params("userId"), PvzID: c.Params("pvzId")}
+	userID, pvzID, err := req.validate()
+	if err != nil {
+		return err
+	}
+
+	if err := h.UC.Unassign(c.UserContext(), userID, pvzID); err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"description": "Сотрудник снят с ПВЗ",
+	})
+}
@@ -0,0 +1,110 @@
+package assignments
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+type mockAssignmentUseCase struct {
+	assignments []models.Assignment
+	err         error
+	userID      uuid.UUID
+	pvzID       uuid.UUID
+}
+
+func (m *mockAssignmentUseCase) Assign(_ context.Context, userID, pvzID uuid.UUID) (models.Assignment, error) {
+	m.userID, m.pvzID = userID, pvzID
+	return models.Assignment{UserID: userID, PvzID: pvzID, AssignedAt: time.Now()}, m.err
+}
+
+func (m *mockAssignmentUseCase) Unassign(_ context.Context, userID, pvzID uuid.UUID) error {
+	m.userID, m.pvzID = userID, pvzID
+	return m.err
+}
+
+func (m *mockAssignmentUseCase) List(_ context.Context, userID uuid.UUID) ([]models.Assignment, error) {
+	m.userID = userID
+	return m.assignments, m.err
+}
+
+type AssignmentsHandlerTestSuite struct {
+	suite.Suite
+	app *fiber.App
+	uc  *mockAssignmentUseCase
+}
+
+func (s *AssignmentsHandlerTestSuite) SetupTest() {
+	s.uc = &mockAssignmentUseCase{}
+	handler := NewHandler(s.uc)
+
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.app.Get("/employees/:userId/pvz", handler.List)
+	s.app.Put("/employees/:userId/pvz/:pvzId", handler.Assign)
+	s.app.Delete("/employees/:userId/pvz/:pvzId", handler.Unassign)
+}
+
+func (s *AssignmentsHandlerTestSuite) do(method, path string) *http.Response {
+	resp, err := s.app.Test(httptest.NewRequest(method, path, nil))
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *AssignmentsHandlerTestSuite) TestAssign() {
+	userID, pvzID := uuid.New(), uuid.New()
+
+	resp := s.do("PUT", "/employees/"+userID.String()+"/pvz/"+pvzID.String())
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(userID, s.uc.userID)
+	s.Equal(pvzID, s.uc.pvzID)
+
+	var body models.Assignment
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Equal(pvzID, body.PvzID)
+}
+
+func (s *AssignmentsHandlerTestSuite) TestAssignInvalidID() {
+	resp := s.do("PUT", "/employees/"+uuid.NewString()+"/pvz/not-a-uuid")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *AssignmentsHandlerTestSuite) TestAssignNotEmployee() {
+	s.uc.err = models.ErrNotEmployee
+
+	resp := s.do("PUT", "/employees/"+uuid.NewString()+"/pvz/"+uuid.NewString())
+	s.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func (s *AssignmentsHandlerTestSuite) TestUnassignNotFound() {
+	s.uc.err = models.ErrAssignmentNotFound
+
+	resp := s.do("DELETE", "/employees/"+uuid.NewString()+"/pvz/"+uuid.NewString())
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *AssignmentsHandlerTestSuite) TestList() {
+	userID := uuid.New()
+	s.uc.assignments = []models.Assignment{{UserID: userID, PvzID: uuid.New()}}
+
+	resp := s.do("GET", "/employees/"+userID.String()+"/pvz")
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var body []models.Assignment
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Len(body, 1)
+	s.Equal(userID, s.uc.userID)
+}
+
+func TestAssignmentsHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(AssignmentsHandlerTestSuite))
+}
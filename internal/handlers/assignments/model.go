@@ -0,0 +1,29 @@
+package assignments
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+type assignmentReq struct {
+	UserID string `validate:"required,uuid"`
+	PvzID  string `validate:"omitempty,uuid"`
+}
+
+// validate возвращает идентификаторы сотрудника и ПВЗ; PvzID не обязателен для списка закреплений
+func (r *assignmentReq) validate() (uuid.UUID, uuid.UUID, error) {
+	if err := validator.New().Struct(r); err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	userID := uuid.MustParse(r.UserID)
+	if r.PvzID == "" {
+		return userID, uuid.Nil, nil
+	}
+
+	return userID, uuid.MustParse(r.PvzID), nil
+}
@@ -0,0 +1,79 @@
+package cities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+type CityUseCase interface {
+	ListCities(ctx context.Context) ([]models.PVZCity, error)
+	AddCity(ctx context.Context, city models.PVZCity) error
+	DeleteCity(ctx context.Context, city models.PVZCity) error
+}
+
+type Handler struct {
+	UC CityUseCase
+}
+
+func NewHandler(uc CityUseCase) *Handler {
+	return &Handler{UC: uc}
+}
+
+func (h *Handler) List(ctx *fiber.Ctx) error {
+	cities, err := h.UC.ListCities(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"cities": cities,
+	})
+}
+
+func (h *Handler) Create(ctx *fiber.Ctx) error {
+	var req cityReq
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "bad request",
+		})
+	}
+
+	city, err := req.validate()
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: fmt.Sprintf("bad request: %s", err.Error()),
+		})
+	}
+
+	if err = h.UC.AddCity(ctx.UserContext(), city); err != nil {
+		return err
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"name": city,
+	})
+}
+
+func (h *Handler) Delete(ctx *fiber.Ctx) error {
+	name, err := url.PathUnescape(ctx.Params("name"))
+	if err != nil || name == "" {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "name is invalid",
+		})
+	}
+
+	if err = h.UC.DeleteCity(ctx.UserContext(), models.PVZCity(name)); err != nil {
+		return err
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
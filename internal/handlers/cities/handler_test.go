@@ -0,0 +1,145 @@
+package cities_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/handlers/cities"
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/models"
+)
+
+type mockCityUseCase struct {
+	mock.Mock
+}
+
+func (m *mockCityUseCase) ListCities(ctx context.Context) ([]models.PVZCity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.PVZCity), args.Error(1)
+}
+
+func (m *mockCityUseCase) AddCity(ctx context.Context, city models.PVZCity) error {
+	return m.Called(ctx, city).Error(0)
+}
+
+func (m *mockCityUseCase) DeleteCity(ctx context.Context, city models.PVZCity) error {
+	return m.Called(ctx, city).Error(0)
+}
+
+type CitiesHandlerSuite struct {
+	suite.Suite
+	app  *fiber.App
+	mock *mockCityUseCase
+}
+
+func (s *CitiesHandlerSuite) SetupTest() {
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.mock = new(mockCityUseCase)
+	handler := cities.NewHandler(s.mock)
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"moderator": {rbac.ReferenceRead, rbac.ReferenceWrite},
+		"employee":  {rbac.ReferenceRead},
+	})
+	s.Require().NoError(err)
+
+	s.app.Get("/cities", policy.Require(rbac.ReferenceRead), handler.List)
+	s.app.Post("/cities", policy.Require(rbac.ReferenceWrite), handler.Create)
+	s.app.Delete("/cities/:name", policy.Require(rbac.ReferenceWrite), handler.Delete)
+}
+
+func (s *CitiesHandlerSuite) Test_List_Success() {
+	s.mock.On("ListCities", mock.Anything).Return([]models.PVZCity{models.CityKazan, models.CityMoscow}, nil)
+
+	req := httptest.NewRequest("GET", "/cities", nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string][]string
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal([]string{string(models.CityKazan), string(models.CityMoscow)}, result["cities"])
+}
+
+func (s *CitiesHandlerSuite) Test_Create_AccessDenied() {
+	req := httptest.NewRequest("POST", "/cities", strings.NewReader(`{"name": "Новосибирск"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	s.mock.AssertNotCalled(s.T(), "AddCity")
+}
+
+func (s *CitiesHandlerSuite) Test_Create_Success() {
+	s.mock.On("AddCity", mock.Anything, models.PVZCity("Новосибирск")).Return(nil)
+
+	req := httptest.NewRequest("POST", "/cities", strings.NewReader(`{"name": "Новосибирск"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *CitiesHandlerSuite) Test_Create_AlreadyExists() {
+	s.mock.On("AddCity", mock.Anything, models.CityKazan).Return(models.ErrReferenceExists)
+
+	req := httptest.NewRequest("POST", "/cities", strings.NewReader(`{"name": "Казань"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *CitiesHandlerSuite) Test_Delete_InUse() {
+	s.mock.On("DeleteCity", mock.Anything, models.CityKazan).Return(models.ErrReferenceInUse)
+
+	req := httptest.NewRequest("DELETE", "/cities/"+url.PathEscape(string(models.CityKazan)), nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *CitiesHandlerSuite) Test_Delete_Success() {
+	s.mock.On("DeleteCity", mock.Anything, models.PVZCity("Новосибирск")).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/cities/"+url.PathEscape("Новосибирск"), nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+	s.mock.AssertExpectations(s.T())
+}
+
+func TestCitiesHandlerSuite(t *testing.T) {
+	suite.Run(t, new(CitiesHandlerSuite))
+}
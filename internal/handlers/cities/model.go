@@ -0,0 +1,22 @@
+package cities
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"AvitoPVZ/internal/models"
+)
+
+type cityReq struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+func (u *cityReq) validate() (models.PVZCity, error) {
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return "", fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return models.PVZCity(u.Name), nil
+}
@@ -1,30 +1,57 @@
 package dummy_login
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"AvitoPVZ/internal/models"
 )
 
-func DummyLoginHandler(c *fiber.Ctx) error {
+type dummyUsers interface {
+	Login(ctx context.Context, role models.UserRole) (uuid.UUID, error)
+}
+
+// roles - роли, заданные в политике rbac
+type roles interface {
+	HasRole(role models.UserRole) bool
+}
+
+type Handler struct {
+	users dummyUsers
+	roles roles
+}
+
+func NewHandler(users dummyUsers, roles roles) *Handler {
+	return &Handler{users: users, roles: roles}
+}
+
+// Login выдаёт токен пользователю /dummyLogin с запрошенной ролью; токен подписывает следующий обработчик
+func (h *Handler) Login(c *fiber.Ctx) error {
 	var req struct {
 		Role string `json:"role"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "Invalid request body",
 		})
 	}
 
-	if !models.IsUserRole(models.UserRole(req.Role)) {
+	if !h.roles.HasRole(models.UserRole(req.Role)) {
 		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
-			Message: "Role must be 'employee' or 'moderator'",
+			Code:    models.CodeValidation,
+			Message: fmt.Sprintf("unknown role %q", req.Role),
 		})
 	}
 
-	userID := uuid.New()
+	userID, err := h.users.Login(c.UserContext(), models.UserRole(req.Role))
+	if err != nil {
+		return err
+	}
 
 	c.Locals("UserID", userID)
 	c.Locals("Role", models.UserRole(req.Role))
@@ -2,7 +2,9 @@ package dummy_login_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
 
@@ -11,17 +13,32 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"AvitoPVZ/internal/handlers/dummy_login"
+	"AvitoPVZ/internal/middleware/errhandler"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
 )
 
+type stubUsers struct {
+	id    uuid.UUID
+	roles []models.UserRole
+	err   error
+}
+
+func (s *stubUsers) Login(_ context.Context, role models.UserRole) (uuid.UUID, error) {
+	s.roles = append(s.roles, role)
+	return s.id, s.err
+}
+
 type DummyLoginTestSuite struct {
 	suite.Suite
-	app *fiber.App
+	app   *fiber.App
+	users *stubUsers
 }
 
 func (suite *DummyLoginTestSuite) SetupTest() {
-	suite.app = fiber.New()
-	suite.app.Post("/login", dummy_login.DummyLoginHandler, func(c *fiber.Ctx) error {
+	suite.users = &stubUsers{id: uuid.New()}
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	suite.app.Post("/login", dummy_login.NewHandler(suite.users, testutil.DefaultRoles).Login, func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"userID": c.Locals("UserID"),
 			"role":   c.Locals("Role"),
@@ -43,7 +60,7 @@ func (suite *DummyLoginTestSuite) TestInvalidJSON() {
 }
 
 func (suite *DummyLoginTestSuite) TestInvalidRole() {
-	body := `{"role": "admin"}`
+	body := `{"role": "auditor"}`
 	req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(body))
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
@@ -52,7 +69,8 @@ func (suite *DummyLoginTestSuite) TestInvalidRole() {
 	var errResp models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&errResp)
 	suite.NoError(err)
-	suite.Equal("Role must be 'employee' or 'moderator'", errResp.Message)
+	suite.Equal(`unknown role "auditor"`, errResp.Message)
+	suite.Empty(suite.users.roles)
 }
 
 func (suite *DummyLoginTestSuite) TestValidLogin() {
@@ -70,10 +88,18 @@ func (suite *DummyLoginTestSuite) TestValidLogin() {
 	suite.NoError(err)
 
 	suite.Equal("employee", data["role"])
-	userID, ok := data["userID"].(string)
-	suite.True(ok, "UserID должен быть строкой")
-	_, err = uuid.Parse(userID)
-	suite.NoError(err, "UserID должен быть валидным UUID")
+	suite.Equal(suite.users.id.String(), data["userID"], "токен выдаётся пользователю из базы")
+	suite.Equal([]models.UserRole{models.RoleEmployee}, suite.users.roles)
+}
+
+func (suite *DummyLoginTestSuite) TestUseCaseError() {
+	suite.users.err = errors.New("db is down")
+
+	req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(`{"role": "moderator"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+	suite.NoError(err)
+	suite.Equal(fiber.StatusInternalServerError, resp.StatusCode)
 }
 
 func TestDummyLoginTestSuite(t *testing.T) {
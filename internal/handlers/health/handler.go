@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	defaultTimeout = 2 * time.Second
+)
+
+// Check - проверка одной зависимости для готовности
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type Handler struct {
+	checks  []Check
+	Timeout time.Duration
+	Logger  *slog.Logger
+}
+
+func NewHandler(checks ...Check) *Handler {
+	return &Handler{checks: checks, Timeout: defaultTimeout, Logger: slog.Default()}
+}
+
+type readyResp struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Live - процесс запущен и обрабатывает запросы; зависимости не проверяются,
+// чтобы недоступная база не приводила к перезапуску сервиса
+func (h *Handler) Live(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(fiber.Map{"status": StatusUp})
+}
+
+// Ready - сервис может обслуживать трафик: все зависимости отвечают.
+// Причины отказа пишутся в журнал, наружу отдаётся только статус каждой зависимости
+func (h *Handler) Ready(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), h.Timeout)
+	defer cancel()
+
+	resp := readyResp{Status: StatusUp, Checks: make(map[string]string, len(h.checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			status := StatusUp
+			if err := check.Check(ctx); err != nil {
+				h.Logger.WarnContext(ctx, "readiness check failed", slog.String("check", check.Name), slog.Any("error", err))
+				status = StatusDown
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[check.Name] = status
+			if status == StatusDown {
+				resp.Status = StatusDown
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	if resp.Status == StatusDown {
+		return c.Status(http.StatusServiceUnavailable).JSON(resp)
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
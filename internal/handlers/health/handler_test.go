@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (s *HealthHandlerTestSuite) app(checks ...Check) *fiber.App {
+	handler := NewHandler(checks...)
+	handler.Timeout = 100 * time.Millisecond
+
+	app := fiber.New()
+	app.Get("/healthz", handler.Live)
+	app.Get("/readyz", handler.Ready)
+	return app
+}
+
+func (s *HealthHandlerTestSuite) get(app *fiber.App, path string) (int, readyResp) {
+	resp, err := app.Test(httptest.NewRequest("GET", path, nil), 2000)
+	s.Require().NoError(err)
+
+	var body readyResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func ok(context.Context) error { return nil }
+
+func (s *HealthHandlerTestSuite) TestLiveIgnoresDependencies() {
+	app := s.app(Check{Name: "postgres", Check: func(context.Context) error { return errors.New("down") }})
+
+	status, body := s.get(app, "/healthz")
+	s.Equal(http.StatusOK, status)
+	s.Equal(StatusUp, body.Status)
+}
+
+func (s *HealthHandlerTestSuite) TestReadyAllUp() {
+	app := s.app(Check{Name: "postgres", Check: ok}, Check{Name: "migrations", Check: ok})
+
+	status, body := s.get(app, "/readyz")
+	s.Equal(http.StatusOK, status)
+	s.Equal(readyResp{Status: StatusUp, Checks: map[string]string{"postgres": StatusUp, "migrations": StatusUp}}, body)
+}
+
+func (s *HealthHandlerTestSuite) TestReadyReportsFailedDependency() {
+	app := s.app(
+		Check{Name: "postgres", Check: ok},
+		Check{Name: "migrations", Check: func(context.Context) error { return errors.New("schema version 7, expected 9") }},
+	)
+
+	status, body := s.get(app, "/readyz")
+	s.Equal(http.StatusServiceUnavailable, status)
+	s.Equal(StatusDown, body.Status)
+	s.Equal(StatusUp, body.Checks["postgres"])
+	s.Equal(StatusDown, body.Checks["migrations"])
+}
+
+func (s *HealthHandlerTestSuite) TestReadyTimesOutHangingCheck() {
+	app := s.app(Check{Name: "postgres", Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	status, body := s.get(app, "/readyz")
+	s.Equal(http.StatusServiceUnavailable, status)
+	s.Equal(StatusDown, body.Checks["postgres"])
+}
+
+func TestHealthHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthHandlerTestSuite))
+}
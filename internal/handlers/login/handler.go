@@ -10,7 +10,7 @@ import (
 )
 
 type login interface {
-	LoginUser(ctx context.Context, user models.User) (models.User, error)
+	LoginUser(ctx context.Context, user models.User, clientIP string) (models.User, error)
 }
 
 type Handler struct {
@@ -26,6 +26,7 @@ func (h *Handler) Register(ctx *fiber.Ctx) error {
 
 	if err := ctx.BodyParser(&req); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "invalid request body",
 		})
 	}
@@ -33,18 +34,17 @@ func (h *Handler) Register(ctx *fiber.Ctx) error {
 	email, password, err := req.validate()
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: fmt.Sprintf("invalid request body: %s", err.Error()),
 		})
 	}
 
-	user, err := h.login.LoginUser(ctx.Context(), models.User{
+	user, err := h.login.LoginUser(ctx.UserContext(), models.User{
 		Email:    email,
 		Password: password,
-	})
+	}, ctx.IP())
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
-			Message: fmt.Sprintf("login failed: %s", err.Error()),
-		})
+		return err
 	}
 
 	ctx.Locals("UserID", user.ID)
@@ -8,12 +8,14 @@ import (
 	"github.com/google/uuid"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	"AvitoPVZ/internal/handlers/login"
+	"AvitoPVZ/internal/middleware/errhandler"
 	"AvitoPVZ/internal/models"
 )
 
@@ -21,8 +23,8 @@ type loginMock struct {
 	mock.Mock
 }
 
-func (m *loginMock) LoginUser(ctx context.Context, user models.User) (models.User, error) {
-	args := m.Called(ctx, user)
+func (m *loginMock) LoginUser(ctx context.Context, user models.User, clientIP string) (models.User, error) {
+	args := m.Called(ctx, user, clientIP)
 	return args.Get(0).(models.User), args.Error(1)
 }
 
@@ -34,7 +36,7 @@ type LoginHandlerSuite struct {
 }
 
 func (s *LoginHandlerSuite) SetupTest() {
-	s.app = fiber.New()
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 	s.mock = new(loginMock)
 	s.router = login.NewHandler(s.mock)
 
@@ -64,7 +66,7 @@ func (s *LoginHandlerSuite) TestRegister_Success() {
 
 	s.mock.On("LoginUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
 		return u.Email == reqBody.Email && u.Password == reqBody.Password
-	})).Return(expectedUser, nil)
+	}), "0.0.0.0").Return(expectedUser, nil)
 
 	req := httptest.NewRequest("POST", "/login", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
@@ -112,7 +114,7 @@ func (s *LoginHandlerSuite) TestRegister_LoginError() {
 	}
 	bodyBytes, _ := json.Marshal(reqBody)
 
-	s.mock.On("LoginUser", mock.Anything, mock.Anything).Return(models.User{}, errors.New("login failed"))
+	s.mock.On("LoginUser", mock.Anything, mock.Anything, mock.Anything).Return(models.User{}, errors.New("login failed"))
 
 	req := httptest.NewRequest("POST", "/login", bytes.NewReader(bodyBytes))
 	req.Header.Set("Content-Type", "application/json")
@@ -120,10 +122,25 @@ func (s *LoginHandlerSuite) TestRegister_LoginError() {
 	resp, err := s.app.Test(req)
 
 	s.Require().NoError(err)
-	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+	s.Equal(fiber.StatusInternalServerError, resp.StatusCode)
 	s.mock.AssertExpectations(s.T())
 }
 
+func (s *LoginHandlerSuite) TestRegister_TooManyAttempts() {
+	bodyBytes, _ := json.Marshal(login.UserLoginIn{Email: "test@example.com", Password: "StrongPassword123!"})
+
+	s.mock.On("LoginUser", mock.Anything, mock.Anything, mock.Anything).Return(models.User{},
+		&models.RetryAfterError{Err: models.ErrTooManyAttempts, RetryAfter: 90 * time.Second})
+
+	req := httptest.NewRequest("POST", "/login", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("90", resp.Header.Get("Retry-After"))
+}
+
 func TestLoginHandlerSuite(t *testing.T) {
 	suite.Run(t, new(LoginHandlerSuite))
 }
@@ -17,7 +17,7 @@ type UserLoginIn struct {
 func (u *UserLoginIn) validate() (string, string, error) {
 	validate := validator.New()
 	if err := validate.Struct(u); err != nil {
-		return "", "", fmt.Errorf("%s: %w", models.ErrValidation, err)
+		return "", "", fmt.Errorf("%w: %s", models.ErrValidation, err)
 	}
 
 	if err := passwordValidator.Validate(u.Password, float64(models.MinEntropyBits)); err != nil {
@@ -0,0 +1,89 @@
+package password
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+type PasswordUseCase interface {
+	ChangePassword(ctx context.Context, userID uuid.UUID, current, next string) error
+	RequestReset(ctx context.Context, email, clientIP string) error
+	ConfirmReset(ctx context.Context, token, password string) error
+}
+
+type Handler struct {
+	UC PasswordUseCase
+}
+
+func NewHandler(uc PasswordUseCase) *Handler {
+	return &Handler{UC: uc}
+}
+
+// Change - смена пароля текущим пользователем
+func (h *Handler) Change(c *fiber.Ctx) error {
+	userID, ok := c.Locals("UserID").(uuid.UUID)
+	if !ok {
+		return models.ErrAuthUser
+	}
+
+	var req changeReq
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c)
+	}
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	if err := h.UC.ChangePassword(c.UserContext(), userID, req.CurrentPassword, req.NewPassword); err != nil {
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// RequestReset отвечает одинаково независимо от того, есть ли пользователь с таким email
+func (h *Handler) RequestReset(c *fiber.Ctx) error {
+	var req resetReq
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c)
+	}
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	if err := h.UC.RequestReset(c.UserContext(), req.Email, c.IP()); err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusAccepted).JSON(fiber.Map{
+		"description": "Если пользователь с таким email существует, ему отправлена инструкция по сбросу пароля",
+	})
+}
+
+func (h *Handler) ConfirmReset(c *fiber.Ctx) error {
+	var req confirmReq
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c)
+	}
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	if err := h.UC.ConfirmReset(c.UserContext(), req.Token, req.NewPassword); err != nil {
+		return err
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func invalidBody(c *fiber.Ctx) error {
+	return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+		Code:    models.CodeValidation,
+		Message: "invalid request body",
+	})
+}
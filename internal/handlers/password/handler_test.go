@@ -0,0 +1,149 @@
+package password
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+const strongPassword = "Correct-Horse-Battery-42"
+
+type mockPasswordUseCase struct {
+	err      error
+	userID   uuid.UUID
+	current  string
+	next     string
+	email    string
+	clientIP string
+	token    string
+	requests int
+}
+
+func (m *mockPasswordUseCase) ChangePassword(_ context.Context, userID uuid.UUID, current, next string) error {
+	m.userID, m.current, m.next = userID, current, next
+	return m.err
+}
+
+func (m *mockPasswordUseCase) RequestReset(_ context.Context, email, clientIP string) error {
+	m.email, m.clientIP = email, clientIP
+	m.requests++
+	return m.err
+}
+
+func (m *mockPasswordUseCase) ConfirmReset(_ context.Context, token, password string) error {
+	m.token, m.next = token, password
+	return m.err
+}
+
+type PasswordHandlerTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	uc     *mockPasswordUseCase
+	userID uuid.UUID
+}
+
+func (s *PasswordHandlerTestSuite) SetupTest() {
+	s.uc = &mockPasswordUseCase{}
+	s.userID = uuid.New()
+	handler := NewHandler(s.uc)
+
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.app.Post("/me/password", func(c *fiber.Ctx) error {
+		c.Locals("UserID", s.userID)
+		return c.Next()
+	}, handler.Change)
+	s.app.Post("/password/reset", handler.RequestReset)
+	s.app.Post("/password/reset/confirm", handler.ConfirmReset)
+}
+
+func (s *PasswordHandlerTestSuite) do(path, body string) *http.Response {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *PasswordHandlerTestSuite) TestChange() {
+	resp := s.do("/me/password", `{"currentPassword":"old","newPassword":"`+strongPassword+`"}`)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+	s.Equal(s.userID, s.uc.userID)
+	s.Equal("old", s.uc.current)
+	s.Equal(strongPassword, s.uc.next)
+}
+
+func (s *PasswordHandlerTestSuite) TestChangeWeakPassword() {
+	resp := s.do("/me/password", `{"currentPassword":"old","newPassword":"123456"}`)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Equal(uuid.Nil, s.uc.userID)
+}
+
+// longPassword короче 255 символов, но длиннее 72 байт, которые хэширует bcrypt
+var longPassword = strongPassword + strings.Repeat("ж", models.MaxPasswordBytes/2)
+
+func (s *PasswordHandlerTestSuite) TestChangeTooLongPassword() {
+	resp := s.do("/me/password", `{"currentPassword":"old","newPassword":"`+longPassword+`"}`)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Equal(uuid.Nil, s.uc.userID)
+}
+
+func (s *PasswordHandlerTestSuite) TestChangeWrongCurrent() {
+	s.uc.err = models.ErrInvalidCredentials
+
+	resp := s.do("/me/password", `{"currentPassword":"guess","newPassword":"`+strongPassword+`"}`)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *PasswordHandlerTestSuite) TestRequestReset() {
+	resp := s.do("/password/reset", `{"email":"user@example.com"}`)
+	s.Equal(http.StatusAccepted, resp.StatusCode)
+	s.Equal("user@example.com", s.uc.email)
+	s.Equal("0.0.0.0", s.uc.clientIP)
+}
+
+func (s *PasswordHandlerTestSuite) TestRequestResetThrottled() {
+	s.uc.err = &models.RetryAfterError{Err: models.ErrTooManyAttempts, RetryAfter: time.Minute}
+
+	resp := s.do("/password/reset", `{"email":"user@example.com"}`)
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("60", resp.Header.Get("Retry-After"))
+}
+
+func (s *PasswordHandlerTestSuite) TestRequestResetInvalidEmail() {
+	resp := s.do("/password/reset", `{"email":"not-an-email"}`)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Zero(s.uc.requests)
+}
+
+func (s *PasswordHandlerTestSuite) TestConfirmReset() {
+	resp := s.do("/password/reset/confirm", `{"token":"abc","newPassword":"`+strongPassword+`"}`)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+	s.Equal("abc", s.uc.token)
+}
+
+func (s *PasswordHandlerTestSuite) TestConfirmResetTooLongPassword() {
+	resp := s.do("/password/reset/confirm", `{"token":"abc","newPassword":"`+longPassword+`"}`)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Empty(s.uc.token)
+}
+
+func (s *PasswordHandlerTestSuite) TestConfirmResetInvalidToken() {
+	s.uc.err = models.ErrInvalidResetToken
+
+	resp := s.do("/password/reset/confirm", `{"token":"abc","newPassword":"`+strongPassword+`"}`)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestPasswordHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordHandlerTestSuite))
+}
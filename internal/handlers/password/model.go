@@ -0,0 +1,61 @@
+package password
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	passwordValidator "github.com/wagslane/go-password-validator"
+
+	"AvitoPVZ/internal/models"
+)
+
+type changeReq struct {
+	CurrentPassword string `json:"currentPassword" validate:"required,max=255"`
+	NewPassword     string `json:"newPassword" validate:"required,max=255"`
+}
+
+func (r *changeReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return validateStrength(r.NewPassword)
+}
+
+type resetReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (r *resetReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return nil
+}
+
+type confirmReq struct {
+	Token       string `json:"token" validate:"required,max=255"`
+	NewPassword string `json:"newPassword" validate:"required,max=255"`
+}
+
+func (r *confirmReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return validateStrength(r.NewPassword)
+}
+
+// validateStrength проверяет длину в байтах, а не в символах: validator считает руны,
+// а bcrypt не хэширует пароль длиннее models.MaxPasswordBytes байт
+func validateStrength(password string) error {
+	if len(password) > models.MaxPasswordBytes {
+		return fmt.Errorf("%w: password is longer than %d bytes", models.ErrValidation, models.MaxPasswordBytes)
+	}
+	if err := passwordValidator.Validate(password, float64(models.MinEntropyBits)); err != nil {
+		return fmt.Errorf("%w: password is too simple: %s", models.ErrValidation, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package product_types
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+type ProductTypeUseCase interface {
+	ListProductTypes(ctx context.Context) ([]models.TypeProduct, error)
+	AddProductType(ctx context.Context, productType models.TypeProduct) error
+	DeleteProductType(ctx context.Context, productType models.TypeProduct) error
+}
+
+type Handler struct {
+	UC ProductTypeUseCase
+}
+
+func NewHandler(uc ProductTypeUseCase) *Handler {
+	return &Handler{UC: uc}
+}
+
+func (h *Handler) List(ctx *fiber.Ctx) error {
+	productTypes, err := h.UC.ListProductTypes(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"productTypes": productTypes,
+	})
+}
+
+func (h *Handler) Create(ctx *fiber.Ctx) error {
+	var req productTypeReq
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "bad request",
+		})
+	}
+
+	productType, err := req.validate()
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: fmt.Sprintf("bad request: %s", err.Error()),
+		})
+	}
+
+	if err = h.UC.AddProductType(ctx.UserContext(), productType); err != nil {
+		return err
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"name": productType,
+	})
+}
+
+func (h *Handler) Delete(ctx *fiber.Ctx) error {
+	name, err := url.PathUnescape(ctx.Params("name"))
+	if err != nil || name == "" {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "name is invalid",
+		})
+	}
+
+	if err = h.UC.DeleteProductType(ctx.UserContext(), models.TypeProduct(name)); err != nil {
+		return err
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
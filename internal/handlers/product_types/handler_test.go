@@ -0,0 +1,128 @@
+package product_types_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/handlers/product_types"
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/models"
+)
+
+type mockProductTypeUseCase struct {
+	mock.Mock
+}
+
+func (m *mockProductTypeUseCase) ListProductTypes(ctx context.Context) ([]models.TypeProduct, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TypeProduct), args.Error(1)
+}
+
+func (m *mockProductTypeUseCase) AddProductType(ctx context.Context, productType models.TypeProduct) error {
+	return m.Called(ctx, productType).Error(0)
+}
+
+func (m *mockProductTypeUseCase) DeleteProductType(ctx context.Context, productType models.TypeProduct) error {
+	return m.Called(ctx, productType).Error(0)
+}
+
+type ProductTypesHandlerSuite struct {
+	suite.Suite
+	app  *fiber.App
+	mock *mockProductTypeUseCase
+}
+
+func (s *ProductTypesHandlerSuite) SetupTest() {
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.mock = new(mockProductTypeUseCase)
+	handler := product_types.NewHandler(s.mock)
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"moderator": {rbac.ReferenceRead, rbac.ReferenceWrite},
+		"employee":  {rbac.ReferenceRead},
+	})
+	s.Require().NoError(err)
+
+	s.app.Get("/product_types", policy.Require(rbac.ReferenceRead), handler.List)
+	s.app.Post("/product_types", policy.Require(rbac.ReferenceWrite), handler.Create)
+	s.app.Delete("/product_types/:name", policy.Require(rbac.ReferenceWrite), handler.Delete)
+}
+
+func (s *ProductTypesHandlerSuite) Test_List_AccessDenied() {
+	req := httptest.NewRequest("GET", "/product_types", nil)
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *ProductTypesHandlerSuite) Test_List_Success() {
+	s.mock.On("ListProductTypes", mock.Anything).Return([]models.TypeProduct{models.TypeShoes}, nil)
+
+	req := httptest.NewRequest("GET", "/product_types", nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string][]string
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal([]string{string(models.TypeShoes)}, result["productTypes"])
+}
+
+func (s *ProductTypesHandlerSuite) Test_Create_ValidationError() {
+	req := httptest.NewRequest("POST", "/product_types", strings.NewReader(`{"name": ""}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *ProductTypesHandlerSuite) Test_Create_Success() {
+	s.mock.On("AddProductType", mock.Anything, models.TypeProduct("книги")).Return(nil)
+
+	req := httptest.NewRequest("POST", "/product_types", strings.NewReader(`{"name": "книги"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *ProductTypesHandlerSuite) Test_Delete_NotFound() {
+	s.mock.On("DeleteProductType", mock.Anything, models.TypeProduct("книги")).Return(models.ErrReferenceNotFound)
+
+	req := httptest.NewRequest("DELETE", "/product_types/"+url.PathEscape("книги"), nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+	s.mock.AssertExpectations(s.T())
+}
+
+func TestProductTypesHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ProductTypesHandlerSuite))
+}
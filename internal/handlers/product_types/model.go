@@ -0,0 +1,22 @@
+package product_types
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"AvitoPVZ/internal/models"
+)
+
+type productTypeReq struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+func (u *productTypeReq) validate() (models.TypeProduct, error) {
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return "", fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return models.TypeProduct(u.Name), nil
+}
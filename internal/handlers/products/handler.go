@@ -2,59 +2,186 @@ package products
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"AvitoPVZ/internal/handlers/access"
+	"AvitoPVZ/internal/middleware/errhandler"
 	"AvitoPVZ/internal/models"
 )
 
 type ProductUseCase interface {
-	CreateProduct(ctx context.Context, pvzID uuid.UUID, productType models.TypeProduct) (models.Product, error)
+	CreateProduct(ctx context.Context, pvzID uuid.UUID, product models.Product) (models.Product, error)
+	CreateProductsBatch(ctx context.Context, pvzID uuid.UUID, products []models.Product) ([]models.Product, error)
+	GetProductByBarcode(ctx context.Context, barcode string) (models.ProductLocation, error)
+}
+
+// ProductTypeDictionary - справочник допустимых типов товаров
+type ProductTypeDictionary interface {
+	IsTypeProduct(productType string) bool
 }
 
 type ProductHandler struct {
-	UC ProductUseCase
+	UC     ProductUseCase
+	Types  ProductTypeDictionary
+	Access access.PVZAccess
 }
 
-func NewProductHandler(uc ProductUseCase) *ProductHandler {
-	return &ProductHandler{UC: uc}
+func NewProductHandler(uc ProductUseCase, types ProductTypeDictionary, access access.PVZAccess) *ProductHandler {
+	return &ProductHandler{UC: uc, Types: types, Access: access}
 }
 
 func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
-	userRole, ok := c.Locals("Role").(models.UserRole)
-	if !ok || userRole != models.RoleEmployee {
-		return c.Status(http.StatusForbidden).JSON(models.ErrorResp{
-			Message: "Access denied (only PVZ employee)",
-		})
-	}
-
 	var req ReqProducts
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "Bad Request",
 		})
 	}
 
-	typeProduct, pvzID, err := req.validate()
+	draft, pvzID, err := req.validate(h.Types)
 	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: err.Error(),
 		})
 	}
 
-	product, err := h.UC.CreateProduct(c.Context(), pvzID, typeProduct)
+	userID, _ := c.Locals("UserID").(uuid.UUID)
+	if err := h.Access.CheckPVZAccess(c.UserContext(), userID, pvzID.String()); err != nil {
+		return err
+	}
+
+	product, err := h.UC.CreateProduct(c.UserContext(), pvzID, draft)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusCreated).JSON(toResponse(product))
+}
+
+// batchItemResult - результат обработки одного товара пакетного запроса
+type batchItemResult struct {
+	Index   int       `json:"index"`
+	Status  string    `json:"status"`
+	Code    string    `json:"code,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Product fiber.Map `json:"product,omitempty"`
+}
+
+const (
+	batchStatusCreated  = "created"
+	batchStatusFailed   = "failed"
+	batchStatusSkipped  = "skipped"
+	batchStatusRejected = "rejected"
+)
+
+// CreateProductsBatch - приёмка пачки товаров одной транзакцией.
+// Результат возвращается для каждого товара: при любой ошибке не добавляется ни один.
+func (h *ProductHandler) CreateProductsBatch(c *fiber.Ctx) error {
+	var req ReqProductsBatch
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "invalid request body",
+		})
+	}
+
+	drafts, pvzID, itemErrs, err := req.validate(h.Types)
 	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: err.Error(),
 		})
 	}
 
+	userID, _ := c.Locals("UserID").(uuid.UUID)
+	if err := h.Access.CheckPVZAccess(c.UserContext(), userID, pvzID.String()); err != nil {
+		return err
+	}
+
+	if len(itemErrs) > 0 {
+		return c.Status(models.ErrValidation.Status).JSON(fiber.Map{
+			"items": batchFailure(len(drafts), itemErrs, batchStatusRejected),
+		})
+	}
+
+	products, err := h.UC.CreateProductsBatch(c.UserContext(), pvzID, drafts)
+	var itemErr *models.BatchItemError
+	if errors.As(err, &itemErr) {
+		status, _ := errhandler.Resolve(itemErr.Err)
+
+		return c.Status(status).JSON(fiber.Map{
+			"items": batchFailure(len(drafts), map[int]error{itemErr.Index: itemErr.Err}, batchStatusFailed),
+		})
+	} else if err != nil {
+		return err
+	}
+
+	results := make([]batchItemResult, len(products))
+	for i, product := range products {
+		results[i] = batchItemResult{Index: i, Status: batchStatusCreated, Product: toResponse(product)}
+	}
+
 	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"items": results,
+	})
+}
+
+// batchFailure строит результаты для отклонённой пачки: ошибочные товары получают failStatus, остальные - skipped
+func batchFailure(size int, itemErrs map[int]error, failStatus string) []batchItemResult {
+	results := make([]batchItemResult, size)
+	for i := range results {
+		results[i] = batchItemResult{Index: i, Status: batchStatusSkipped}
+		if err, ok := itemErrs[i]; ok {
+			_, resp := errhandler.Resolve(err)
+			results[i].Status = failStatus
+			results[i].Code = resp.Code
+			results[i].Error = resp.Message
+		}
+	}
+
+	return results
+}
+
+func toResponse(product models.Product) fiber.Map {
+	resp := fiber.Map{
 		"id":          product.ID.String(),
 		"dateTime":    product.DateTime.Format("2006-01-02 15:04:05"),
 		"type":        product.Type,
 		"receptionId": product.ReceptionID,
-	})
+	}
+	if product.Barcode != nil {
+		resp["barcode"] = *product.Barcode
+	}
+	if product.WeightGrams != nil {
+		resp["weightGrams"] = *product.WeightGrams
+	}
+	if product.Dimensions != nil {
+		resp["dimensions"] = product.Dimensions
+	}
+
+	return resp
+}
+
+func (h *ProductHandler) GetProductByBarcode(c *fiber.Ctx) error {
+	code := c.Params("code")
+	if err := validator.New().Var(code, "required,max=64,alphanum"); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "barcode is invalid",
+		})
+	}
+
+	location, err := h.UC.GetProductByBarcode(c.UserContext(), code)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(location)
 }
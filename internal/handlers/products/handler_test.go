@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -15,27 +16,57 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"AvitoPVZ/internal/handlers/products"
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
 )
 
 type mockProductUseCase struct {
-	product models.Product
-	err     error
+	product  models.Product
+	batch    []models.Product
+	drafts   []models.Product
+	location models.ProductLocation
+	draft    models.Product
+	err      error
 }
 
-func (m *mockProductUseCase) CreateProduct(_ context.Context, _ uuid.UUID, _ models.TypeProduct) (models.Product, error) {
+func (m *mockProductUseCase) CreateProduct(_ context.Context, _ uuid.UUID, draft models.Product) (models.Product, error) {
+	m.draft = draft
 	return m.product, m.err
 }
 
+func (m *mockProductUseCase) CreateProductsBatch(_ context.Context, _ uuid.UUID, drafts []models.Product) ([]models.Product, error) {
+	m.drafts = drafts
+	return m.batch, m.err
+}
+
+func (m *mockProductUseCase) GetProductByBarcode(_ context.Context, _ string) (models.ProductLocation, error) {
+	return m.location, m.err
+}
+
+type stubProductTypes map[string]bool
+
+func (s stubProductTypes) IsTypeProduct(productType string) bool {
+	return s[productType]
+}
+
+var productTypes = stubProductTypes{
+	string(models.TypeElectronic): true,
+	string(models.TypeClothes):    true,
+	string(models.TypeShoes):      true,
+}
+
 type ProductHandlerTestSuite struct {
 	suite.Suite
 	app     *fiber.App
 	useCase *mockProductUseCase
+	access  *testutil.StubAccess
 	handler *products.ProductHandler
 }
 
 func (suite *ProductHandlerTestSuite) SetupTest() {
-	suite.app = fiber.New()
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 
 	suite.app.Use(func(c *fiber.Ctx) error {
 		if role := c.Get("X-Role"); role != "" {
@@ -45,9 +76,18 @@ func (suite *ProductHandlerTestSuite) SetupTest() {
 	})
 
 	suite.useCase = &mockProductUseCase{}
-	suite.handler = products.NewProductHandler(suite.useCase)
+	suite.access = &testutil.StubAccess{}
+	suite.handler = products.NewProductHandler(suite.useCase, productTypes, suite.access)
 
-	suite.app.Post("/products", suite.handler.CreateProduct)
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee":  {rbac.ProductCreate, rbac.ProductRead},
+		"moderator": {rbac.ProductRead},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Post("/products", policy.Require(rbac.ProductCreate), suite.handler.CreateProduct)
+	suite.app.Post("/products/batch", policy.Require(rbac.ProductCreate), suite.handler.CreateProductsBatch)
+	suite.app.Get("/products/by-barcode/:code", policy.Require(rbac.ProductRead), suite.handler.GetProductByBarcode)
 }
 
 func (suite *ProductHandlerTestSuite) TestAccessDenied() {
@@ -62,7 +102,7 @@ func (suite *ProductHandlerTestSuite) TestAccessDenied() {
 	var body models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	suite.Require().NoError(err)
-	suite.Equal("Access denied (only PVZ employee)", body.Message)
+	suite.Equal("access denied", body.Message)
 }
 
 func (suite *ProductHandlerTestSuite) TestBadBody() {
@@ -109,11 +149,11 @@ func (suite *ProductHandlerTestSuite) TestUseCaseError() {
 	resp, err := suite.app.Test(req)
 	suite.Require().NoError(err)
 
-	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
 	var body models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	suite.Require().NoError(err)
-	suite.Equal("use case error", body.Message)
+	suite.Equal(models.CodeInternal, body.Code)
 }
 
 func (suite *ProductHandlerTestSuite) TestSuccess() {
@@ -150,6 +190,202 @@ func (suite *ProductHandlerTestSuite) TestSuccess() {
 	suite.Equal(expectedDateTime, payload["dateTime"])
 }
 
+func (suite *ProductHandlerTestSuite) TestSuccessWithSKU() {
+	barcode := "4601234567890"
+	weight := 1200
+	suite.useCase.product = models.Product{
+		ID:          uuid.New(),
+		DateTime:    time.Now(),
+		Type:        models.TypeShoes,
+		ReceptionID: uuid.New(),
+		Barcode:     &barcode,
+		WeightGrams: &weight,
+		Dimensions:  &models.Dimensions{LengthMM: 300, WidthMM: 200, HeightMM: 120},
+	}
+
+	reqBody := `{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "type": "обувь", "barcode": "4601234567890",
+		"weightGrams": 1200, "dimensions": {"lengthMm": 300, "widthMm": 200, "heightMm": 120}}`
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+
+	suite.Require().NotNil(suite.useCase.draft.Barcode)
+	suite.Equal(barcode, *suite.useCase.draft.Barcode)
+	suite.Equal(&weight, suite.useCase.draft.WeightGrams)
+	suite.Equal(suite.useCase.product.Dimensions, suite.useCase.draft.Dimensions)
+
+	var payload map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&payload))
+	suite.Equal(barcode, payload["barcode"])
+	suite.Equal(float64(weight), payload["weightGrams"])
+}
+
+func (suite *ProductHandlerTestSuite) TestInvalidDimensions() {
+	reqBody := `{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "type": "обувь", "dimensions": {"lengthMm": 300}}`
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *ProductHandlerTestSuite) TestDuplicateBarcode() {
+	suite.useCase.err = models.ErrDuplicateBarcode
+
+	reqBody := `{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "type": "обувь", "barcode": "4601234567890"}`
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (suite *ProductHandlerTestSuite) TestGetByBarcode() {
+	barcode := "4601234567890"
+	suite.useCase.location = models.ProductLocation{
+		Product:   models.Product{ID: uuid.New(), Barcode: &barcode, Type: models.TypeShoes},
+		Reception: models.Reception{ID: uuid.New(), Status: models.StatusInProgress},
+		PVZ:       models.PVZ{ID: uuid.NewString(), City: string(models.CityMoscow), Active: true},
+	}
+
+	req := httptest.NewRequest("GET", "/products/by-barcode/"+barcode, nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var payload map[string]map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&payload))
+	suite.Equal(barcode, payload["product"]["barcode"])
+	suite.Equal(suite.useCase.location.Reception.ID.String(), payload["reception"]["id"])
+	suite.Equal(suite.useCase.location.PVZ.ID, payload["pvz"]["id"])
+}
+
+func (suite *ProductHandlerTestSuite) TestGetByBarcodeNotFound() {
+	suite.useCase.err = models.ErrProductNotFound
+
+	req := httptest.NewRequest("GET", "/products/by-barcode/000", nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *ProductHandlerTestSuite) batchRequest(body string) (*http.Response, []map[string]interface{}) {
+	req := httptest.NewRequest("POST", "/products/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+
+	var payload struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&payload)
+	return resp, payload.Items
+}
+
+func (suite *ProductHandlerTestSuite) TestBatchSuccess() {
+	suite.useCase.batch = []models.Product{
+		{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeShoes, ReceptionID: uuid.New()},
+		{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeClothes, ReceptionID: uuid.New()},
+	}
+
+	resp, items := suite.batchRequest(`{"pvzId": "123e4567-e89b-12d3-a456-426614174000",
+		"items": [{"type": "обувь"}, {"type": "одежда", "barcode": "123"}]}`)
+	suite.Equal(http.StatusCreated, resp.StatusCode)
+	suite.Require().Len(items, 2)
+	suite.Equal("created", items[1]["status"])
+	suite.Len(suite.useCase.drafts, 2)
+	suite.Equal("123", *suite.useCase.drafts[1].Barcode)
+}
+
+func (suite *ProductHandlerTestSuite) TestBatchInvalidItemType() {
+	resp, items := suite.batchRequest(`{"pvzId": "123e4567-e89b-12d3-a456-426614174000",
+		"items": [{"type": "обувь"}, {"type": "мебель"}]}`)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Require().Len(items, 2)
+	suite.Equal("skipped", items[0]["status"])
+	suite.Equal("rejected", items[1]["status"])
+	suite.Nil(suite.useCase.drafts)
+}
+
+func (suite *ProductHandlerTestSuite) TestBatchTooLarge() {
+	body := `{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "items": [` +
+		strings.Repeat(`{"type": "обувь"},`, products.MaxBatchSize) + `{"type": "обувь"}]}`
+
+	resp, _ := suite.batchRequest(body)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Nil(suite.useCase.drafts)
+}
+
+func (suite *ProductHandlerTestSuite) TestBatchEmpty() {
+	resp, _ := suite.batchRequest(`{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "items": []}`)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *ProductHandlerTestSuite) TestBatchDuplicateBarcode() {
+	suite.useCase.err = &models.BatchItemError{Index: 1, Err: models.ErrDuplicateBarcode}
+
+	resp, items := suite.batchRequest(`{"pvzId": "123e4567-e89b-12d3-a456-426614174000",
+		"items": [{"type": "обувь", "barcode": "1"}, {"type": "обувь", "barcode": "2"}]}`)
+	suite.Equal(http.StatusConflict, resp.StatusCode)
+	suite.Require().Len(items, 2)
+	suite.Equal("skipped", items[0]["status"])
+	suite.Equal("failed", items[1]["status"])
+	suite.Equal(models.ErrDuplicateBarcode.Error(), items[1]["error"])
+}
+
+func (suite *ProductHandlerTestSuite) TestBatchNoActiveReception() {
+	suite.useCase.err = fmt.Errorf("create products batch: %w", models.ErrNoActiveReception)
+
+	req := httptest.NewRequest("POST", "/products/batch", strings.NewReader(`{"pvzId": "123e4567-e89b-12d3-a456-426614174000",
+		"items": [{"type": "обувь"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body models.ErrorResp
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(models.ErrNoActiveReception.Code, body.Code)
+}
+
+func (suite *ProductHandlerTestSuite) TestPVZNotAssigned() {
+	suite.access.Err = models.ErrPVZNotAssigned
+
+	for _, tc := range []struct{ path, body string }{
+		{"/products", `{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "type": "одежда"}`},
+		{"/products/batch", `{"pvzId": "123e4567-e89b-12d3-a456-426614174000", "items": [{"type": "одежда"}]}`},
+	} {
+		req := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Role", string(models.RoleEmployee))
+
+		resp, err := suite.app.Test(req)
+		suite.Require().NoError(err)
+		suite.Equal(http.StatusForbidden, resp.StatusCode, tc.path)
+
+		var body models.ErrorResp
+		suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+		suite.Equal(models.ErrPVZNotAssigned.Code, body.Code)
+	}
+	suite.Equal([]string{"123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-426614174000"}, suite.access.Checked)
+}
+
 func TestProductHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(ProductHandlerTestSuite))
 }
@@ -7,25 +7,105 @@ import (
 	"github.com/google/uuid"
 )
 
+// MaxBatchSize - максимальное количество товаров в одном запросе POST /products/batch
+const MaxBatchSize = 500
+
 type ReqProducts struct {
-	Type  string `json:"type" validate:"required"`
+	ReqProductItem
 	PvzID string `json:"pvzId" validate:"required,uuid"`
 }
 
-func (u *ReqProducts) validate() (models.TypeProduct, uuid.UUID, error) {
+// ReqProductItem - описание одного принимаемого товара
+type ReqProductItem struct {
+	Type        string         `json:"type" validate:"required"`
+	Barcode     string         `json:"barcode" validate:"omitempty,max=64,alphanum"`
+	WeightGrams *int           `json:"weightGrams" validate:"omitempty,min=1"`
+	Dimensions  *ReqDimensions `json:"dimensions" validate:"omitempty"`
+}
+
+type ReqDimensions struct {
+	LengthMM int `json:"lengthMm" validate:"required,min=1"`
+	WidthMM  int `json:"widthMm" validate:"required,min=1"`
+	HeightMM int `json:"heightMm" validate:"required,min=1"`
+}
+
+type ReqProductsBatch struct {
+	PvzID string           `json:"pvzId" validate:"required,uuid"`
+	Items []ReqProductItem `json:"items"`
+}
+
+func (u *ReqProducts) validate(types ProductTypeDictionary) (models.Product, uuid.UUID, error) {
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return models.Product{}, uuid.UUID{}, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	product, err := u.ReqProductItem.validate(types)
+	if err != nil {
+		return models.Product{}, uuid.UUID{}, err
+	}
+
+	pvzID, err := uuid.Parse(u.PvzID)
+	if err != nil {
+		return models.Product{}, uuid.UUID{}, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return product, pvzID, nil
+}
+
+func (u *ReqProductItem) validate(types ProductTypeDictionary) (models.Product, error) {
 	validate := validator.New()
 	if err := validate.Struct(u); err != nil {
-		return "", uuid.UUID{}, fmt.Errorf("%s: %w", models.ErrValidation, err)
+		return models.Product{}, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	if !types.IsTypeProduct(u.Type) {
+		return models.Product{}, models.ErrValidation
 	}
 
-	if !models.IsTypeProduct(u.Type) {
-		return "", uuid.UUID{}, models.ErrValidation
+	product := models.Product{
+		Type:        models.TypeProduct(u.Type),
+		WeightGrams: u.WeightGrams,
+	}
+	if u.Barcode != "" {
+		product.Barcode = &u.Barcode
+	}
+	if u.Dimensions != nil {
+		product.Dimensions = &models.Dimensions{
+			LengthMM: u.Dimensions.LengthMM,
+			WidthMM:  u.Dimensions.WidthMM,
+			HeightMM: u.Dimensions.HeightMM,
+		}
+	}
+
+	return product, nil
+}
+
+// validate проверяет заголовок пакета и каждый товар по отдельности.
+// Ошибки товаров возвращаются по индексам, чтобы клиент видел результат для каждого элемента.
+func (u *ReqProductsBatch) validate(types ProductTypeDictionary) ([]models.Product, uuid.UUID, map[int]error, error) {
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return nil, uuid.UUID{}, nil, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	if len(u.Items) == 0 || len(u.Items) > MaxBatchSize {
+		return nil, uuid.UUID{}, nil, fmt.Errorf("%w: batch must contain from 1 to %d items", models.ErrValidation, MaxBatchSize)
 	}
 
 	pvzID, err := uuid.Parse(u.PvzID)
 	if err != nil {
-		return "", uuid.UUID{}, fmt.Errorf("%s: %w", models.ErrValidation, err)
+		return nil, uuid.UUID{}, nil, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	products := make([]models.Product, len(u.Items))
+	itemErrs := make(map[int]error)
+	for i := range u.Items {
+		products[i], err = u.Items[i].validate(types)
+		if err != nil {
+			itemErrs[i] = err
+		}
 	}
 
-	return models.TypeProduct(u.Type), pvzID, nil
+	return products, pvzID, itemErrs, nil
 }
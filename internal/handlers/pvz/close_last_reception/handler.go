@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 
+	"AvitoPVZ/internal/handlers/access"
 	"AvitoPVZ/internal/models"
 )
 
@@ -14,35 +16,33 @@ type ReceptionUseCase interface {
 }
 
 type ReceptionHandler struct {
-	UC ReceptionUseCase
+	UC     ReceptionUseCase
+	Access access.PVZAccess
 }
 
-func NewReceptionHandler(uc ReceptionUseCase) *ReceptionHandler {
-	return &ReceptionHandler{UC: uc}
+func NewReceptionHandler(uc ReceptionUseCase, access access.PVZAccess) *ReceptionHandler {
+	return &ReceptionHandler{UC: uc, Access: access}
 }
 
 func (h *ReceptionHandler) CloseLastReception(c *fiber.Ctx) error {
-	userRole, ok := c.Locals("Role").(models.UserRole)
-	if !ok || userRole != models.RoleEmployee {
-		return c.Status(http.StatusForbidden).JSON(models.ErrorResp{
-			Message: "Access denied (only PVZ employee)",
-		})
-	}
-
 	req := CloseReceptionRequest{
 		PvzID: c.Params("pvzId"),
 	}
 	if err := validateCloseReceptionRequest(req); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "PvzID is invalid",
 		})
 	}
 
-	closedRec, err := h.UC.CloseLastReception(c.Context(), req.PvzID)
+	userID, _ := c.Locals("UserID").(uuid.UUID)
+	if err := h.Access.CheckPVZAccess(c.UserContext(), userID, req.PvzID); err != nil {
+		return err
+	}
+
+	closedRec, err := h.UC.CloseLastReception(c.UserContext(), req.PvzID)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{
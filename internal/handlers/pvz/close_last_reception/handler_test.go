@@ -13,7 +13,10 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/suite"
 
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
 )
 
 type mockReceptionUseCase struct {
@@ -29,11 +32,12 @@ type ReceptionHandlerTestSuite struct {
 	suite.Suite
 	app     *fiber.App
 	useCase *mockReceptionUseCase
+	access  *testutil.StubAccess
 	handler *ReceptionHandler
 }
 
 func (suite *ReceptionHandlerTestSuite) SetupTest() {
-	suite.app = fiber.New()
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 
 	suite.app.Use(func(c *fiber.Ctx) error {
 		if role := c.Get("X-Role"); role != "" {
@@ -44,9 +48,15 @@ func (suite *ReceptionHandlerTestSuite) SetupTest() {
 
 	suite.useCase = &mockReceptionUseCase{}
 
-	suite.handler = NewReceptionHandler(suite.useCase)
+	suite.access = &testutil.StubAccess{}
+	suite.handler = NewReceptionHandler(suite.useCase, suite.access)
 
-	suite.app.Post("/close/:pvzId", suite.handler.CloseLastReception)
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee": {rbac.ReceptionClose},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Post("/close/:pvzId", policy.Require(rbac.ReceptionClose), suite.handler.CloseLastReception)
 }
 
 func (suite *ReceptionHandlerTestSuite) TestAccessDenied() {
@@ -61,7 +71,7 @@ func (suite *ReceptionHandlerTestSuite) TestAccessDenied() {
 	var body models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	suite.Require().NoError(err)
-	suite.Equal("Access denied (only PVZ employee)", body.Message)
+	suite.Equal("access denied", body.Message)
 }
 
 func (suite *ReceptionHandlerTestSuite) TestInvalidPvzID() {
@@ -87,11 +97,11 @@ func (suite *ReceptionHandlerTestSuite) TestUseCaseError() {
 	resp, err := suite.app.Test(req)
 	suite.Require().NoError(err)
 
-	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
 	var body models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	suite.Require().NoError(err)
-	suite.Equal("use case error", body.Message)
+	suite.Equal(models.CodeInternal, body.Code)
 }
 
 func (suite *ReceptionHandlerTestSuite) TestSuccess() {
@@ -124,6 +134,21 @@ func (suite *ReceptionHandlerTestSuite) TestSuccess() {
 	suite.Equal(string(expectedReception.Status), payload["status"])
 }
 
+func (suite *ReceptionHandlerTestSuite) TestPVZNotAssigned() {
+	suite.access.Err = models.ErrPVZNotAssigned
+
+	req := httptest.NewRequest("POST", "/close/"+uuid.NewString(), nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+
+	var body models.ErrorResp
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(models.ErrPVZNotAssigned.Code, body.Code)
+}
+
 func TestReceptionHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(ReceptionHandlerTestSuite))
 }
@@ -0,0 +1,41 @@
+package deactivate
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+type PVZUseCase interface {
+	DeactivatePVZ(ctx context.Context, id string) (models.PVZ, error)
+}
+
+type DeactivatePVZHandler struct {
+	UC PVZUseCase
+}
+
+func NewDeactivatePVZHandler(uc PVZUseCase) *DeactivatePVZHandler {
+	return &DeactivatePVZHandler{UC: uc}
+}
+
+func (h *DeactivatePVZHandler) Handle(c *fiber.Ctx) error {
+	req := DeactivatePVZRequest{
+		PvzID: c.Params("pvzId"),
+	}
+	if err := validateDeactivatePVZRequest(req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "PvzID is invalid",
+		})
+	}
+
+	pvz, err := h.UC.DeactivatePVZ(c.UserContext(), req.PvzID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(pvz)
+}
@@ -0,0 +1,93 @@
+package deactivate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/models"
+)
+
+type mockPVZUseCase struct {
+	pvz models.PVZ
+	err error
+}
+
+func (m *mockPVZUseCase) DeactivatePVZ(_ context.Context, _ string) (models.PVZ, error) {
+	return m.pvz, m.err
+}
+
+type DeactivatePVZHandlerTestSuite struct {
+	suite.Suite
+	app     *fiber.App
+	useCase *mockPVZUseCase
+}
+
+func (suite *DeactivatePVZHandlerTestSuite) SetupTest() {
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+
+	suite.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+
+	suite.useCase = &mockPVZUseCase{}
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"moderator": {rbac.PVZDeactivate},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Post("/pvz/:pvzId/deactivate", policy.Require(rbac.PVZDeactivate), NewDeactivatePVZHandler(suite.useCase).Handle)
+}
+
+func (suite *DeactivatePVZHandlerTestSuite) request(role, pvzID string) *http.Response {
+	req := httptest.NewRequest("POST", "/pvz/"+pvzID+"/deactivate", nil)
+	req.Header.Set("X-Role", role)
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *DeactivatePVZHandlerTestSuite) TestAccessDenied() {
+	resp := suite.request(string(models.RoleEmployee), uuid.NewString())
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *DeactivatePVZHandlerTestSuite) TestInvalidPvzID() {
+	resp := suite.request(string(models.RoleModerator), "not-a-uuid")
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *DeactivatePVZHandlerTestSuite) TestNotFound() {
+	suite.useCase.err = models.ErrPVZNotFound
+	resp := suite.request(string(models.RoleModerator), uuid.NewString())
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *DeactivatePVZHandlerTestSuite) TestSuccess() {
+	now := time.Now()
+	suite.useCase.pvz = models.PVZ{ID: uuid.NewString(), City: string(models.CityMoscow), DeactivatedAt: &now}
+	resp := suite.request(string(models.RoleModerator), suite.useCase.pvz.ID)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var payload map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&payload))
+	suite.Equal(false, payload["active"])
+	suite.NotEmpty(payload["deactivatedAt"])
+}
+
+func TestDeactivatePVZHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(DeactivatePVZHandlerTestSuite))
+}
@@ -0,0 +1,12 @@
+package deactivate
+
+import "github.com/go-playground/validator/v10"
+
+type DeactivatePVZRequest struct {
+	PvzID string `param:"pvzId" validate:"required,uuid"`
+}
+
+func validateDeactivatePVZRequest(req DeactivatePVZRequest) error {
+	v := validator.New()
+	return v.Struct(req)
+}
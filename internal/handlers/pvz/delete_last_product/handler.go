@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 
+	"AvitoPVZ/internal/handlers/access"
 	"AvitoPVZ/internal/models"
 )
 
@@ -14,35 +16,33 @@ type ProductUseCase interface {
 }
 
 type ProductHandler struct {
-	UC ProductUseCase
+	UC     ProductUseCase
+	Access access.PVZAccess
 }
 
-func NewProductHandler(uc ProductUseCase) *ProductHandler {
-	return &ProductHandler{UC: uc}
+func NewProductHandler(uc ProductUseCase, access access.PVZAccess) *ProductHandler {
+	return &ProductHandler{UC: uc, Access: access}
 }
 
 func (h *ProductHandler) DeleteLastProduct(c *fiber.Ctx) error {
-	userRole, ok := c.Locals("Role").(models.UserRole)
-	if !ok || userRole != models.RoleEmployee {
-		return c.Status(http.StatusForbidden).JSON(models.ErrorResp{
-			Message: "Access denied (only PVZ employee)",
-		})
-	}
-
 	req := DeleteProductRequest{
 		PvzID: c.Params("pvzId"),
 	}
 
 	if err := validateDeleteProductRequest(req); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "PvzID is invalid",
 		})
 	}
 
-	if err := h.UC.DeleteLastProduct(c.Context(), req.PvzID); err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: err.Error(),
-		})
+	userID, _ := c.Locals("UserID").(uuid.UUID)
+	if err := h.Access.CheckPVZAccess(c.UserContext(), userID, req.PvzID); err != nil {
+		return err
+	}
+
+	if err := h.UC.DeleteLastProduct(c.UserContext(), req.PvzID); err != nil {
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(fiber.Map{
@@ -13,7 +13,10 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"AvitoPVZ/internal/handlers/pvz/delete_last_product"
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
 )
 
 type mockProductUseCase struct {
@@ -27,15 +30,17 @@ func (m *mockProductUseCase) DeleteLastProduct(ctx context.Context, pvzID string
 
 type DeleteLastProductSuite struct {
 	suite.Suite
-	app  *fiber.App
-	mock *mockProductUseCase
+	app    *fiber.App
+	mock   *mockProductUseCase
+	access *testutil.StubAccess
 }
 
 func (s *DeleteLastProductSuite) SetupTest() {
-	s.app = fiber.New()
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 	s.mock = new(mockProductUseCase)
 
-	handler := delete_last_product.NewProductHandler(s.mock)
+	s.access = &testutil.StubAccess{}
+	handler := delete_last_product.NewProductHandler(s.mock, s.access)
 
 	s.app.Use(func(c *fiber.Ctx) error {
 		if role := c.Get("X-Role"); role != "" {
@@ -44,7 +49,12 @@ func (s *DeleteLastProductSuite) SetupTest() {
 		return c.Next()
 	})
 
-	s.app.Delete("/product/:pvzId/delete", handler.DeleteLastProduct)
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee": {rbac.ProductDelete},
+	})
+	s.Require().NoError(err)
+
+	s.app.Delete("/product/:pvzId/delete", policy.Require(rbac.ProductDelete), handler.DeleteLastProduct)
 }
 
 func (s *DeleteLastProductSuite) TestDeleteLastProduct_Success() {
@@ -97,10 +107,22 @@ func (s *DeleteLastProductSuite) TestDeleteLastProduct_UseCaseError() {
 	resp, err := s.app.Test(req)
 
 	s.Require().NoError(err)
-	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+	s.Equal(fiber.StatusInternalServerError, resp.StatusCode)
 	s.mock.AssertExpectations(s.T())
 }
 
+func (s *DeleteLastProductSuite) TestDeleteLastProduct_PVZNotAssigned() {
+	s.access.Err = models.ErrPVZNotAssigned
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/product/%s/delete", uuid.NewString()), nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusForbidden, resp.StatusCode)
+	s.mock.AssertNotCalled(s.T(), "DeleteLastProduct", mock.Anything, mock.Anything)
+}
+
 func TestDeleteLastProductSuite(t *testing.T) {
 	suite.Run(t, new(DeleteLastProductSuite))
 }
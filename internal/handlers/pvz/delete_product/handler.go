@@ -0,0 +1,53 @@
+package delete_product
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/handlers/access"
+	"AvitoPVZ/internal/models"
+)
+
+type ProductUseCase interface {
+	DeleteProduct(ctx context.Context, pvzID, productID string) error
+}
+
+type ProductHandler struct {
+	UC     ProductUseCase
+	Access access.PVZAccess
+}
+
+func NewProductHandler(uc ProductUseCase, access access.PVZAccess) *ProductHandler {
+	return &ProductHandler{UC: uc, Access: access}
+}
+
+func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	req := DeleteProductRequest{
+		PvzID:     c.Params("pvzId"),
+		ProductID: c.Params("productId"),
+	}
+
+	if err := validateDeleteProductRequest(req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "PvzID or ProductID is invalid",
+		})
+	}
+
+	userID, _ := c.Locals("UserID").(uuid.UUID)
+	if err := h.Access.CheckPVZAccess(c.UserContext(), userID, req.PvzID); err != nil {
+		return err
+	}
+
+	err := h.UC.DeleteProduct(c.UserContext(), req.PvzID, req.ProductID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"description": "Товар удален",
+	})
+}
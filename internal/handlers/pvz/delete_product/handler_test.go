@@ -0,0 +1,118 @@
+package delete_product_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/handlers/pvz/delete_product"
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
+)
+
+type mockProductUseCase struct {
+	mock.Mock
+}
+
+func (m *mockProductUseCase) DeleteProduct(ctx context.Context, pvzID, productID string) error {
+	args := m.Called(ctx, pvzID, productID)
+	return args.Error(0)
+}
+
+type DeleteProductSuite struct {
+	suite.Suite
+	app    *fiber.App
+	mock   *mockProductUseCase
+	access *testutil.StubAccess
+}
+
+func (s *DeleteProductSuite) SetupTest() {
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.mock = new(mockProductUseCase)
+
+	s.access = &testutil.StubAccess{}
+	handler := delete_product.NewProductHandler(s.mock, s.access)
+
+	s.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee": {rbac.ProductDelete},
+	})
+	s.Require().NoError(err)
+
+	s.app.Delete("/pvz/:pvzId/products/:productId", policy.Require(rbac.ProductDelete), handler.DeleteProduct)
+}
+
+func (s *DeleteProductSuite) request(role, pvzID, productID string) int {
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/pvz/%s/products/%s", pvzID, productID), nil)
+	req.Header.Set("X-Role", role)
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_Success() {
+	pvzID, productID := uuid.NewString(), uuid.NewString()
+	s.mock.On("DeleteProduct", mock.Anything, pvzID, productID).Return(nil)
+
+	s.Equal(fiber.StatusOK, s.request(string(models.RoleEmployee), pvzID, productID))
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_Forbidden() {
+	s.Equal(fiber.StatusForbidden, s.request(string(models.RoleModerator), uuid.NewString(), uuid.NewString()))
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_InvalidProductID() {
+	s.Equal(fiber.StatusBadRequest, s.request(string(models.RoleEmployee), uuid.NewString(), "not-a-uuid"))
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_NotFound() {
+	pvzID, productID := uuid.NewString(), uuid.NewString()
+	s.mock.On("DeleteProduct", mock.Anything, pvzID, productID).Return(models.ErrProductNotFound)
+
+	s.Equal(fiber.StatusNotFound, s.request(string(models.RoleEmployee), pvzID, productID))
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_ReceptionClosed() {
+	pvzID, productID := uuid.NewString(), uuid.NewString()
+	s.mock.On("DeleteProduct", mock.Anything, pvzID, productID).Return(models.ErrReceptionClosed)
+
+	s.Equal(fiber.StatusConflict, s.request(string(models.RoleEmployee), pvzID, productID))
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_UseCaseError() {
+	pvzID, productID := uuid.NewString(), uuid.NewString()
+	s.mock.On("DeleteProduct", mock.Anything, pvzID, productID).Return(errors.New("deletion failed"))
+
+	s.Equal(fiber.StatusInternalServerError, s.request(string(models.RoleEmployee), pvzID, productID))
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *DeleteProductSuite) TestDeleteProduct_PVZNotAssigned() {
+	s.access.Err = models.ErrPVZNotAssigned
+
+	s.Equal(fiber.StatusForbidden, s.request(string(models.RoleEmployee), uuid.NewString(), uuid.NewString()))
+	s.mock.AssertNotCalled(s.T(), "DeleteProduct", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteProductSuite(t *testing.T) {
+	suite.Run(t, new(DeleteProductSuite))
+}
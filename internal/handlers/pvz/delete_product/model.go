@@ -0,0 +1,13 @@
+package delete_product
+
+import "github.com/go-playground/validator/v10"
+
+type DeleteProductRequest struct {
+	PvzID     string `param:"pvzId" validate:"required,uuid"`
+	ProductID string `param:"productId" validate:"required,uuid"`
+}
+
+func validateDeleteProductRequest(req DeleteProductRequest) error {
+	v := validator.New()
+	return v.Struct(req)
+}
@@ -13,6 +13,7 @@ import (
 
 type PVZDataUseCase interface {
 	GetPVZData(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]models.PVZData, error)
+	GetPVZDataByCursor(ctx context.Context, startDate, endDate *time.Time, cursor *models.PVZCursor, limit int) ([]models.PVZData, *models.PVZCursor, error)
 }
 
 type PVZDataHandler struct {
@@ -24,18 +25,12 @@ func NewPVZDataHandler(uc PVZDataUseCase) *PVZDataHandler {
 }
 
 func (h *PVZDataHandler) GetPVZData(c *fiber.Ctx) error {
-	userRole, ok := c.Locals("Role").(models.UserRole)
-	if !ok || !models.IsUserRole(userRole) {
-		return c.Status(http.StatusForbidden).JSON(models.ErrorResp{
-			Message: "access denied",
-		})
-	}
-
 	req := PVZDataRequest{
 		StartDate: c.Query("startDate", ""),
 		EndDate:   c.Query("endDate", ""),
 		Page:      1,
 		Limit:     10,
+		Cursor:    c.Query("cursor", ""),
 	}
 	if pageStr := c.Query("page", ""); pageStr != "" {
 		if p, err := strconv.Atoi(pageStr); err == nil {
@@ -50,6 +45,7 @@ func (h *PVZDataHandler) GetPVZData(c *fiber.Ctx) error {
 
 	if err := validateGetPVZDataRequest(req); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "Неверные параметры запроса: " + err.Error(),
 		})
 	}
@@ -60,6 +56,7 @@ func (h *PVZDataHandler) GetPVZData(c *fiber.Ctx) error {
 			startDatePtr = &t
 		} else {
 			return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+				Code:    models.CodeValidation,
 				Message: "Неверный формат startDate",
 			})
 		}
@@ -69,18 +66,51 @@ func (h *PVZDataHandler) GetPVZData(c *fiber.Ctx) error {
 			endDatePtr = &t
 		} else {
 			return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+				Code:    models.CodeValidation,
 				Message: "Неверный формат endDate",
 			})
 		}
 	}
 
-	data, err := h.UC.GetPVZData(c.Context(), startDatePtr, endDatePtr, req.Page, req.Limit)
-	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: err.Error(),
+	// Клиент переходит на курсорную пагинацию, передав параметр cursor (пустой - первая страница)
+	if c.Request().URI().QueryArgs().Has("cursor") {
+		var cursor *models.PVZCursor
+		if req.Cursor != "" {
+			decoded, err := models.DecodePVZCursor(req.Cursor)
+			if err != nil {
+				return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+					Code:    models.CodeValidation,
+					Message: "Неверный формат cursor",
+				})
+			}
+			cursor = &decoded
+		}
+
+		data, next, err := h.UC.GetPVZDataByCursor(c.UserContext(), startDatePtr, endDatePtr, cursor, req.Limit)
+		if err != nil {
+			return err
+		}
+
+		var nextCursor interface{}
+		if next != nil {
+			nextCursor = next.Encode()
+		}
+
+		return c.Status(http.StatusOK).JSON(fiber.Map{
+			"items":      toResponse(data),
+			"nextCursor": nextCursor,
 		})
 	}
 
+	data, err := h.UC.GetPVZData(c.UserContext(), startDatePtr, endDatePtr, req.Page, req.Limit)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(toResponse(data))
+}
+
+func toResponse(data []models.PVZData) []fiber.Map {
 	var result []fiber.Map
 	for _, item := range data {
 		var recs []fiber.Map
@@ -97,5 +127,5 @@ func (h *PVZDataHandler) GetPVZData(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(http.StatusOK).JSON(result)
+	return result
 }
@@ -16,18 +16,27 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
 	"AvitoPVZ/internal/models"
 )
 
 type mockPVZDataUseCase struct {
-	data []models.PVZData
-	err  error
+	data   []models.PVZData
+	next   *models.PVZCursor
+	cursor *models.PVZCursor
+	err    error
 }
 
 func (m *mockPVZDataUseCase) GetPVZData(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]models.PVZData, error) {
 	return m.data, m.err
 }
 
+func (m *mockPVZDataUseCase) GetPVZDataByCursor(ctx context.Context, startDate, endDate *time.Time, cursor *models.PVZCursor, limit int) ([]models.PVZData, *models.PVZCursor, error) {
+	m.cursor = cursor
+	return m.data, m.next, m.err
+}
+
 type PVZDataHandlerTestSuite struct {
 	suite.Suite
 	app     *fiber.App
@@ -36,7 +45,7 @@ type PVZDataHandlerTestSuite struct {
 }
 
 func (suite *PVZDataHandlerTestSuite) SetupTest() {
-	suite.app = fiber.New()
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 
 	suite.app.Use(func(c *fiber.Ctx) error {
 		if role := c.Get("X-Role"); role != "" {
@@ -48,7 +57,13 @@ func (suite *PVZDataHandlerTestSuite) SetupTest() {
 	suite.uc = &mockPVZDataUseCase{}
 	suite.handler = get.NewPVZDataHandler(suite.uc)
 
-	suite.app.Get("/pvzdata", suite.handler.GetPVZData)
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee":  {rbac.PVZRead},
+		"moderator": {rbac.PVZRead},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Get("/pvzdata", policy.Require(rbac.PVZRead), suite.handler.GetPVZData)
 }
 
 func (suite *PVZDataHandlerTestSuite) TestAccessDenied() {
@@ -66,7 +81,7 @@ func (suite *PVZDataHandlerTestSuite) TestAccessDenied() {
 
 func (suite *PVZDataHandlerTestSuite) TestValidationError() {
 	req := httptest.NewRequest("GET", "/pvzdata?page=-1&limit=10", nil)
-	req.Header.Set("X-Role", string(models.RoleEmployee)) // предполагаем, что "employee" проходит проверку models.IsUserRole
+	req.Header.Set("X-Role", string(models.RoleEmployee))
 
 	resp, err := suite.app.Test(req)
 	suite.Require().NoError(err)
@@ -122,11 +137,11 @@ func (suite *PVZDataHandlerTestSuite) TestUseCaseError() {
 	resp, err := suite.app.Test(req)
 	suite.Require().NoError(err)
 
-	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
 	var body models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	suite.Require().NoError(err)
-	suite.Equal("use case error", body.Message)
+	suite.Equal(models.CodeInternal, body.Code)
 }
 
 func (suite *PVZDataHandlerTestSuite) TestSuccess() {
@@ -201,6 +216,57 @@ func (suite *PVZDataHandlerTestSuite) TestSuccess() {
 	suite.Len(products, 2)
 }
 
+func (suite *PVZDataHandlerTestSuite) TestCursorFirstPage() {
+	req := httptest.NewRequest("GET", "/pvzdata?cursor=&limit=1", nil)
+	req.Header.Set("X-Role", "moderator")
+
+	next := models.PVZCursor{RegistrationDate: time.Date(2025, 4, 13, 10, 30, 0, 0, time.UTC), ID: "pvz-1"}
+	suite.uc.data = []models.PVZData{{PVZ: models.PVZ{ID: "pvz-1"}}}
+	suite.uc.next = &next
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Nil(suite.uc.cursor)
+
+	var result map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	suite.Require().NoError(err)
+	suite.Equal(next.Encode(), result["nextCursor"])
+	suite.Len(result["items"], 1)
+}
+
+func (suite *PVZDataHandlerTestSuite) TestCursorNextPage() {
+	cursor := models.PVZCursor{RegistrationDate: time.Date(2025, 4, 13, 10, 30, 0, 0, time.UTC), ID: "pvz-1"}
+	req := httptest.NewRequest("GET", "/pvzdata?cursor="+cursor.Encode(), nil)
+	req.Header.Set("X-Role", "employee")
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().NotNil(suite.uc.cursor)
+	suite.Equal(cursor.ID, suite.uc.cursor.ID)
+
+	var result map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	suite.Require().NoError(err)
+	suite.Nil(result["nextCursor"])
+}
+
+func (suite *PVZDataHandlerTestSuite) TestCursorInvalid() {
+	req := httptest.NewRequest("GET", "/pvzdata?cursor=bm90LWpzb24=", nil)
+	req.Header.Set("X-Role", "employee")
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	var body models.ErrorResp
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	suite.Require().NoError(err)
+	suite.Equal("Неверный формат cursor", body.Message)
+}
+
 func TestPVZDataHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(PVZDataHandlerTestSuite))
 }
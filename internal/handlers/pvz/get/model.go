@@ -7,6 +7,8 @@ type PVZDataRequest struct {
 	EndDate   string `query:"endDate" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 	Page      int    `query:"page" validate:"omitempty,min=1"`
 	Limit     int    `query:"limit" validate:"omitempty,min=1,max=30"`
+	// Cursor - непрозрачный курсор keyset-пагинации, при его наличии page игнорируется
+	Cursor string `query:"cursor" validate:"omitempty,base64url"`
 }
 
 func validateGetPVZDataRequest(req PVZDataRequest) error {
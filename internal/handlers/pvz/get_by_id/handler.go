@@ -0,0 +1,41 @@
+package get_by_id
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+type PVZUseCase interface {
+	GetPVZ(ctx context.Context, id string) (models.PVZ, error)
+}
+
+type PVZHandler struct {
+	UC PVZUseCase
+}
+
+func NewPVZHandler(uc PVZUseCase) *PVZHandler {
+	return &PVZHandler{UC: uc}
+}
+
+func (h *PVZHandler) GetPVZ(c *fiber.Ctx) error {
+	req := GetPVZRequest{
+		PvzID: c.Params("pvzId"),
+	}
+	if err := validateGetPVZRequest(req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "PvzID is invalid",
+		})
+	}
+
+	pvz, err := h.UC.GetPVZ(c.UserContext(), req.PvzID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(pvz)
+}
@@ -0,0 +1,106 @@
+package get_by_id
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/models"
+)
+
+type mockPVZUseCase struct {
+	pvz models.PVZ
+	err error
+}
+
+func (m *mockPVZUseCase) GetPVZ(_ context.Context, _ string) (models.PVZ, error) {
+	return m.pvz, m.err
+}
+
+type PVZHandlerTestSuite struct {
+	suite.Suite
+	app     *fiber.App
+	useCase *mockPVZUseCase
+}
+
+func (suite *PVZHandlerTestSuite) SetupTest() {
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+
+	suite.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+
+	suite.useCase = &mockPVZUseCase{}
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee":  {rbac.PVZRead},
+		"moderator": {rbac.PVZRead},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Get("/pvz/:pvzId", policy.Require(rbac.PVZRead), NewPVZHandler(suite.useCase).GetPVZ)
+}
+
+func (suite *PVZHandlerTestSuite) TestAccessDenied() {
+	req := httptest.NewRequest("GET", "/pvz/"+uuid.NewString(), nil)
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *PVZHandlerTestSuite) TestInvalidPvzID() {
+	req := httptest.NewRequest("GET", "/pvz/not-a-uuid", nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *PVZHandlerTestSuite) TestNotFound() {
+	suite.useCase.err = models.ErrPVZNotFound
+	req := httptest.NewRequest("GET", "/pvz/"+uuid.NewString(), nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *PVZHandlerTestSuite) TestSuccess() {
+	deactivatedAt := time.Date(2025, 4, 13, 10, 30, 0, 0, time.UTC)
+	suite.useCase.pvz = models.PVZ{
+		ID:               uuid.NewString(),
+		RegistrationDate: deactivatedAt,
+		City:             string(models.CityMoscow),
+		DeactivatedAt:    &deactivatedAt,
+	}
+	req := httptest.NewRequest("GET", "/pvz/"+suite.useCase.pvz.ID, nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+
+	var payload map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&payload))
+	suite.Equal(suite.useCase.pvz.ID, payload["id"])
+	suite.Equal(false, payload["active"])
+	suite.Equal(deactivatedAt.Format(time.RFC3339), payload["deactivatedAt"])
+}
+
+func TestPVZHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(PVZHandlerTestSuite))
+}
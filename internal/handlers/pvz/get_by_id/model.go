@@ -0,0 +1,12 @@
+package get_by_id
+
+import "github.com/go-playground/validator/v10"
+
+type GetPVZRequest struct {
+	PvzID string `param:"pvzId" validate:"required,uuid"`
+}
+
+func validateGetPVZRequest(req GetPVZRequest) error {
+	v := validator.New()
+	return v.Struct(req)
+}
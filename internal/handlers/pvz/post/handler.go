@@ -14,41 +14,40 @@ type PVZUseCase interface {
 	CreatePVZ(ctx context.Context, city models.PVZCity) (models.PVZ, error)
 }
 
+// CityDictionary - справочник допустимых городов
+type CityDictionary interface {
+	IsPVZCity(city models.PVZCity) bool
+}
+
 type CreatePVZHandler struct {
-	UC PVZUseCase
+	UC     PVZUseCase
+	Cities CityDictionary
 }
 
-func NewCreatePVZHandler(uc PVZUseCase) *CreatePVZHandler {
-	return &CreatePVZHandler{UC: uc}
+func NewCreatePVZHandler(uc PVZUseCase, cities CityDictionary) *CreatePVZHandler {
+	return &CreatePVZHandler{UC: uc, Cities: cities}
 }
 
 func (h *CreatePVZHandler) Handle(ctx *fiber.Ctx) error {
-	userRole, ok := ctx.Locals("Role").(models.UserRole)
-	if !ok || !models.IsUserRole(userRole) || userRole != models.RoleModerator {
-		return ctx.Status(http.StatusForbidden).JSON(models.ErrorResp{
-			Message: "access denied",
-		})
-	}
-
 	var req pvzPostReq
 	if err := ctx.BodyParser(&req); err != nil {
 		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "bad request",
 		})
 	}
 
-	city, err := req.validate()
+	city, err := req.validate(h.Cities)
 	if err != nil {
 		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: fmt.Sprintf("bad request: %s", err.Error()),
 		})
 	}
 
-	newPVZ, err := h.UC.CreatePVZ(ctx.Context(), city)
+	newPVZ, err := h.UC.CreatePVZ(ctx.UserContext(), city)
 	if err != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: fmt.Sprintf("create pvz failed: %s", err.Error()),
-		})
+		return err
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
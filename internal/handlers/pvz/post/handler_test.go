@@ -14,6 +14,8 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/suite"
 
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
 	"AvitoPVZ/internal/models"
 )
 
@@ -26,6 +28,18 @@ func (m *mockPVZUseCase) CreatePVZ(ctx context.Context, city models.PVZCity) (mo
 	return m.pvz, m.err
 }
 
+type stubCities map[models.PVZCity]bool
+
+func (s stubCities) IsPVZCity(city models.PVZCity) bool {
+	return s[city]
+}
+
+var cities = stubCities{
+	models.CityMoscow: true,
+	models.CitySPB:    true,
+	models.CityKazan:  true,
+}
+
 type CreatePVZHandlerTestSuite struct {
 	suite.Suite
 	app     *fiber.App
@@ -34,7 +48,7 @@ type CreatePVZHandlerTestSuite struct {
 }
 
 func (suite *CreatePVZHandlerTestSuite) SetupTest() {
-	suite.app = fiber.New()
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 
 	suite.app.Use(func(c *fiber.Ctx) error {
 		if role := c.Get("X-Role"); role != "" {
@@ -44,9 +58,14 @@ func (suite *CreatePVZHandlerTestSuite) SetupTest() {
 	})
 
 	suite.uc = &mockPVZUseCase{}
-	suite.handler = NewCreatePVZHandler(suite.uc)
+	suite.handler = NewCreatePVZHandler(suite.uc, cities)
 
-	suite.app.Post("/pvz", suite.handler.Handle)
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"moderator": {rbac.PVZCreate},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Post("/pvz", policy.Require(rbac.PVZCreate), suite.handler.Handle)
 }
 
 func (suite *CreatePVZHandlerTestSuite) TestAccessDenied() {
@@ -122,11 +141,11 @@ func (suite *CreatePVZHandlerTestSuite) TestUseCaseError() {
 	resp, err := suite.app.Test(req)
 	suite.Require().NoError(err)
 
-	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
 	var body models.ErrorResp
 	err = json.NewDecoder(resp.Body).Decode(&body)
 	suite.Require().NoError(err)
-	suite.Contains(body.Message, "create pvz failed")
+	suite.Equal(models.CodeInternal, body.Code)
 }
 
 func (suite *CreatePVZHandlerTestSuite) TestSuccess() {
@@ -10,13 +10,13 @@ type pvzPostReq struct {
 	City string `json:"city" validate:"required"`
 }
 
-func (u *pvzPostReq) validate() (models.PVZCity, error) {
+func (u *pvzPostReq) validate(cities CityDictionary) (models.PVZCity, error) {
 	validate := validator.New()
 	if err := validate.Struct(u); err != nil {
-		return "", fmt.Errorf("%s: %w", models.ErrValidation, err)
+		return "", fmt.Errorf("%w: %s", models.ErrValidation, err)
 	}
 
-	if !models.IsPVZCity(models.PVZCity(u.City)) {
+	if !cities.IsPVZCity(models.PVZCity(u.City)) {
 		return "", fmt.Errorf("%w: %s", models.ErrValidation, "city is not allowed")
 	}
 
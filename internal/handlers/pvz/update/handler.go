@@ -0,0 +1,55 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+type PVZUseCase interface {
+	UpdatePVZ(ctx context.Context, id string, city models.PVZCity) (models.PVZ, error)
+}
+
+// CityDictionary - справочник допустимых городов
+type CityDictionary interface {
+	IsPVZCity(city models.PVZCity) bool
+}
+
+type UpdatePVZHandler struct {
+	UC     PVZUseCase
+	Cities CityDictionary
+}
+
+func NewUpdatePVZHandler(uc PVZUseCase, cities CityDictionary) *UpdatePVZHandler {
+	return &UpdatePVZHandler{UC: uc, Cities: cities}
+}
+
+func (h *UpdatePVZHandler) Handle(ctx *fiber.Ctx) error {
+	var req pvzUpdateReq
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "bad request",
+		})
+	}
+	req.PvzID = ctx.Params("pvzId")
+
+	city, err := req.validate(h.Cities)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: fmt.Sprintf("bad request: %s", err.Error()),
+		})
+	}
+
+	updated, err := h.UC.UpdatePVZ(ctx.UserContext(), req.PvzID, city)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(http.StatusOK).JSON(updated)
+}
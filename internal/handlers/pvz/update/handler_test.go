@@ -0,0 +1,112 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/models"
+)
+
+type mockPVZUseCase struct {
+	pvz  models.PVZ
+	err  error
+	city models.PVZCity
+}
+
+func (m *mockPVZUseCase) UpdatePVZ(_ context.Context, _ string, city models.PVZCity) (models.PVZ, error) {
+	m.city = city
+	return m.pvz, m.err
+}
+
+type stubCities map[models.PVZCity]bool
+
+func (s stubCities) IsPVZCity(city models.PVZCity) bool {
+	return s[city]
+}
+
+type UpdatePVZHandlerTestSuite struct {
+	suite.Suite
+	app     *fiber.App
+	useCase *mockPVZUseCase
+}
+
+func (suite *UpdatePVZHandlerTestSuite) SetupTest() {
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+
+	suite.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+
+	suite.useCase = &mockPVZUseCase{}
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"moderator": {rbac.PVZUpdate},
+	})
+	suite.Require().NoError(err)
+
+	suite.app.Patch("/pvz/:pvzId", policy.Require(rbac.PVZUpdate), NewUpdatePVZHandler(suite.useCase, stubCities{models.CityKazan: true}).Handle)
+}
+
+func (suite *UpdatePVZHandlerTestSuite) request(role, pvzID, body string) *http.Response {
+	req := httptest.NewRequest("PATCH", "/pvz/"+pvzID, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", role)
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *UpdatePVZHandlerTestSuite) TestAccessDenied() {
+	resp := suite.request(string(models.RoleEmployee), uuid.NewString(), `{"city": "Казань"}`)
+	suite.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *UpdatePVZHandlerTestSuite) TestCityNotAllowed() {
+	resp := suite.request(string(models.RoleModerator), uuid.NewString(), `{"city": "Тверь"}`)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *UpdatePVZHandlerTestSuite) TestNotFound() {
+	suite.useCase.err = models.ErrPVZNotFound
+	resp := suite.request(string(models.RoleModerator), uuid.NewString(), `{"city": "Казань"}`)
+	suite.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *UpdatePVZHandlerTestSuite) TestUseCaseError() {
+	suite.useCase.err = errors.New("db error")
+	resp := suite.request(string(models.RoleModerator), uuid.NewString(), `{"city": "Казань"}`)
+	suite.Equal(http.StatusInternalServerError, resp.StatusCode)
+
+	var body models.ErrorResp
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	suite.Equal(models.CodeInternal, body.Code)
+}
+
+func (suite *UpdatePVZHandlerTestSuite) TestSuccess() {
+	suite.useCase.pvz = models.PVZ{ID: uuid.NewString(), City: string(models.CityKazan), Active: true}
+	resp := suite.request(string(models.RoleModerator), suite.useCase.pvz.ID, `{"city": "Казань"}`)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(models.CityKazan, suite.useCase.city)
+
+	var payload map[string]interface{}
+	suite.Require().NoError(json.NewDecoder(resp.Body).Decode(&payload))
+	suite.Equal(string(models.CityKazan), payload["city"])
+}
+
+func TestUpdatePVZHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdatePVZHandlerTestSuite))
+}
@@ -0,0 +1,27 @@
+package update
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"AvitoPVZ/internal/models"
+)
+
+type pvzUpdateReq struct {
+	PvzID string `param:"pvzId" validate:"required,uuid"`
+	City  string `json:"city" validate:"required"`
+}
+
+func (u *pvzUpdateReq) validate(cities CityDictionary) (models.PVZCity, error) {
+	validate := validator.New()
+	if err := validate.Struct(u); err != nil {
+		return "", fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	if !cities.IsPVZCity(models.PVZCity(u.City)) {
+		return "", fmt.Errorf("%w: %s", models.ErrValidation, "city is not allowed")
+	}
+
+	return models.PVZCity(u.City), nil
+}
@@ -8,39 +8,38 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"AvitoPVZ/internal/handlers/access"
 	"AvitoPVZ/internal/models"
 )
 
 type ReceptionUseCase interface {
 	CreateReception(ctx context.Context, pvzID uuid.UUID) (models.Reception, error)
+	GetReception(ctx context.Context, id uuid.UUID) (models.ReceptionDetails, error)
+	ListReceptions(ctx context.Context, pvzID uuid.UUID, filter models.ReceptionFilter) ([]models.ReceptionDetails, error)
 }
 
 type ReceptionHandler struct {
-	UC ReceptionUseCase
+	UC     ReceptionUseCase
+	Access access.PVZAccess
 }
 
-func NewReceptionHandler(uc ReceptionUseCase) *ReceptionHandler {
-	return &ReceptionHandler{UC: uc}
+func NewReceptionHandler(uc ReceptionUseCase, access access.PVZAccess) *ReceptionHandler {
+	return &ReceptionHandler{UC: uc, Access: access}
 }
 
 func (h *ReceptionHandler) CreateReception(c *fiber.Ctx) error {
-	userRole, ok := c.Locals("Role").(models.UserRole)
-	if !ok || !models.IsUserRole(userRole) || userRole != models.RoleEmployee {
-		return c.Status(http.StatusForbidden).JSON(models.ErrorResp{
-			Message: "Access denied (only for PVZ employee)",
-		})
-	}
-
 	var req struct {
 		PvzID string `json:"pvzId"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "Bad request",
 		})
 	}
 	if req.PvzID == "" {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "pvzId is required",
 		})
 	}
@@ -48,15 +47,19 @@ func (h *ReceptionHandler) CreateReception(c *fiber.Ctx) error {
 	PvzUUID, err := uuid.Parse(req.PvzID)
 	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: fmt.Sprintf("Invalid Pvz ID: %s", req.PvzID),
 		})
 	}
 
-	reception, err := h.UC.CreateReception(c.Context(), PvzUUID)
+	userID, _ := c.Locals("UserID").(uuid.UUID)
+	if err := h.Access.CheckPVZAccess(c.UserContext(), userID, req.PvzID); err != nil {
+		return err
+	}
+
+	reception, err := h.UC.CreateReception(c.UserContext(), PvzUUID)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(http.StatusCreated).JSON(fiber.Map{
@@ -66,3 +69,46 @@ func (h *ReceptionHandler) CreateReception(c *fiber.Ctx) error {
 		"status":   reception.Status,
 	})
 }
+
+func (h *ReceptionHandler) GetReception(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: fmt.Sprintf("Invalid reception ID: %s", c.Params("id")),
+		})
+	}
+
+	details, err := h.UC.GetReception(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(details)
+}
+
+func (h *ReceptionHandler) ListReceptions(c *fiber.Ctx) error {
+	req := ListReceptionsRequest{
+		PvzID:     c.Params("pvzId"),
+		Status:    c.Query("status", ""),
+		StartDate: c.Query("startDate", ""),
+		EndDate:   c.Query("endDate", ""),
+		Page:      c.QueryInt("page", 1),
+		Limit:     c.QueryInt("limit", 10),
+	}
+
+	pvzID, filter, err := req.validate()
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: err.Error(),
+		})
+	}
+
+	list, err := h.UC.ListReceptions(c.UserContext(), pvzID, filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(list)
+}
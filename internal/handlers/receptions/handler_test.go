@@ -15,7 +15,10 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"AvitoPVZ/internal/handlers/receptions"
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/rbac"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
 )
 
 type mockReceptionUseCase struct {
@@ -27,16 +30,28 @@ func (m *mockReceptionUseCase) CreateReception(ctx context.Context, pvzID uuid.U
 	return args.Get(0).(models.Reception), args.Error(1)
 }
 
+func (m *mockReceptionUseCase) GetReception(ctx context.Context, id uuid.UUID) (models.ReceptionDetails, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.ReceptionDetails), args.Error(1)
+}
+
+func (m *mockReceptionUseCase) ListReceptions(ctx context.Context, pvzID uuid.UUID, filter models.ReceptionFilter) ([]models.ReceptionDetails, error) {
+	args := m.Called(ctx, pvzID, filter)
+	return args.Get(0).([]models.ReceptionDetails), args.Error(1)
+}
+
 type ReceptionHandlerSuite struct {
 	suite.Suite
-	app  *fiber.App
-	mock *mockReceptionUseCase
+	app    *fiber.App
+	mock   *mockReceptionUseCase
+	access *testutil.StubAccess
 }
 
 func (s *ReceptionHandlerSuite) SetupTest() {
-	s.app = fiber.New()
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 	s.mock = new(mockReceptionUseCase)
-	handler := receptions.NewReceptionHandler(s.mock)
+	s.access = &testutil.StubAccess{}
+	handler := receptions.NewReceptionHandler(s.mock, s.access)
 
 	s.app.Use(func(c *fiber.Ctx) error {
 		if role := c.Get("X-Role"); role != "" {
@@ -45,7 +60,15 @@ func (s *ReceptionHandlerSuite) SetupTest() {
 		return c.Next()
 	})
 
-	s.app.Post("/reception", handler.CreateReception)
+	policy, err := rbac.NewPolicy(map[string][]string{
+		"employee":  {rbac.ReceptionCreate, rbac.ReceptionRead},
+		"moderator": {rbac.ReceptionRead},
+	})
+	s.Require().NoError(err)
+
+	s.app.Post("/reception", policy.Require(rbac.ReceptionCreate), handler.CreateReception)
+	s.app.Get("/receptions/:id", policy.Require(rbac.ReceptionRead), handler.GetReception)
+	s.app.Get("/pvz/:pvzId/receptions", policy.Require(rbac.ReceptionRead), handler.ListReceptions)
 }
 
 func (s *ReceptionHandlerSuite) Test_CreateReception_Success() {
@@ -143,10 +166,149 @@ func (s *ReceptionHandlerSuite) Test_CreateReception_UseCaseError() {
 	resp, err := s.app.Test(req)
 
 	s.Require().NoError(err)
-	s.Equal(400, resp.StatusCode)
+	s.Equal(500, resp.StatusCode)
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *ReceptionHandlerSuite) Test_CreateReception_AlreadyOpen() {
+	pvzID := uuid.New()
+	s.mock.On("CreateReception", mock.Anything, pvzID).Return(models.Reception{}, models.ErrReceptionAlreadyOpen)
+
+	body := map[string]string{"pvzId": pvzID.String()}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/reception", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	s.Equal(409, resp.StatusCode)
+
+	var errResp models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&errResp))
+	s.Equal("RECEPTION_ALREADY_OPEN", errResp.Code)
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *ReceptionHandlerSuite) Test_GetReception_Success() {
+	id := uuid.New()
+	expected := models.ReceptionDetails{
+		Reception:    models.Reception{ID: id, PvzID: uuid.New(), Status: models.StatusClose},
+		ProductCount: 1,
+		Products:     []models.Product{{ID: uuid.New(), ReceptionID: id, Type: models.TypeShoes}},
+	}
+	s.mock.On("GetReception", mock.Anything, id).Return(expected, nil)
+
+	req := httptest.NewRequest("GET", "/receptions/"+id.String(), nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(200, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal(float64(1), result["productCount"])
+	s.Len(result["products"], 1)
+	s.mock.AssertExpectations(s.T())
+}
+
+func (s *ReceptionHandlerSuite) Test_GetReception_NotFound() {
+	id := uuid.New()
+	s.mock.On("GetReception", mock.Anything, id).Return(models.ReceptionDetails{}, models.ErrReceptionNotFound)
+
+	req := httptest.NewRequest("GET", "/receptions/"+id.String(), nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(404, resp.StatusCode)
+}
+
+func (s *ReceptionHandlerSuite) Test_GetReception_Forbidden() {
+	req := httptest.NewRequest("GET", "/receptions/"+uuid.NewString(), nil)
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(403, resp.StatusCode)
+}
+
+func (s *ReceptionHandlerSuite) Test_ListReceptions_Filters() {
+	pvzID := uuid.New()
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	status := models.StatusInProgress
+	filter := models.ReceptionFilter{Status: &status, StartDate: &start, Page: 2, Limit: 5}
+	s.mock.On("ListReceptions", mock.Anything, pvzID, filter).Return([]models.ReceptionDetails{}, nil)
+
+	url := "/pvz/" + pvzID.String() + "/receptions?status=in_progress&startDate=" + start.Format(time.RFC3339) + "&page=2&limit=5"
+	req := httptest.NewRequest("GET", url, nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(200, resp.StatusCode)
 	s.mock.AssertExpectations(s.T())
 }
 
+func (s *ReceptionHandlerSuite) Test_ListReceptions_InvalidStatus() {
+	req := httptest.NewRequest("GET", "/pvz/"+uuid.NewString()+"/receptions?status=open", nil)
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(400, resp.StatusCode)
+}
+
+func (s *ReceptionHandlerSuite) Test_ListReceptions_ZeroPageOrLimit() {
+	for _, query := range []string{"page=0", "limit=0", "page=-1"} {
+		req := httptest.NewRequest("GET", "/pvz/"+uuid.NewString()+"/receptions?"+query, nil)
+		req.Header.Set("X-Role", string(models.RoleEmployee))
+
+		resp, err := s.app.Test(req)
+
+		s.Require().NoError(err)
+		s.Equal(400, resp.StatusCode, query)
+	}
+	s.mock.AssertNotCalled(s.T(), "ListReceptions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ReceptionHandlerSuite) Test_ListReceptions_PVZNotFound() {
+	pvzID := uuid.New()
+	s.mock.On("ListReceptions", mock.Anything, pvzID, mock.Anything).Return([]models.ReceptionDetails(nil), models.ErrPVZNotFound)
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/receptions", nil)
+	req.Header.Set("X-Role", string(models.RoleModerator))
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(404, resp.StatusCode)
+}
+
+func (s *ReceptionHandlerSuite) Test_CreateReception_PVZNotAssigned() {
+	s.access.Err = models.ErrPVZNotAssigned
+
+	bodyBytes, _ := json.Marshal(map[string]string{"pvzId": uuid.NewString()})
+	req := httptest.NewRequest("POST", "/reception", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Role", string(models.RoleEmployee))
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	s.Equal(403, resp.StatusCode)
+
+	var body models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Equal(models.ErrPVZNotAssigned.Code, body.Code)
+	s.mock.AssertNotCalled(s.T(), "CreateReception", mock.Anything, mock.Anything)
+}
+
 func TestReceptionHandlerSuite(t *testing.T) {
 	suite.Run(t, new(ReceptionHandlerSuite))
 }
@@ -0,0 +1,53 @@
+package receptions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+type ListReceptionsRequest struct {
+	PvzID     string `param:"pvzId" validate:"required,uuid"`
+	Status    string `query:"status" validate:"omitempty,oneof=in_progress close"`
+	StartDate string `query:"startDate" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndDate   string `query:"endDate" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	// Page и Limit проверяются без omitempty: значения по умолчанию подставляет обработчик,
+	// а явные page=0 и limit=0 - ошибка клиента, а не отрицательный OFFSET в запросе
+	Page  int `query:"page" validate:"min=1"`
+	Limit int `query:"limit" validate:"min=1,max=30"`
+}
+
+func (r *ListReceptionsRequest) validate() (uuid.UUID, models.ReceptionFilter, error) {
+	v := validator.New()
+	if err := v.Struct(r); err != nil {
+		return uuid.UUID{}, models.ReceptionFilter{}, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	pvzID, err := uuid.Parse(r.PvzID)
+	if err != nil {
+		return uuid.UUID{}, models.ReceptionFilter{}, fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	filter := models.ReceptionFilter{
+		Page:  r.Page,
+		Limit: r.Limit,
+	}
+	if r.Status != "" {
+		status := models.StatusReception(r.Status)
+		filter.Status = &status
+	}
+	if r.StartDate != "" {
+		t, _ := time.Parse(time.RFC3339, r.StartDate)
+		filter.StartDate = &t
+	}
+	if r.EndDate != "" {
+		t, _ := time.Parse(time.RFC3339, r.EndDate)
+		filter.EndDate = &t
+	}
+
+	return pvzID, filter, nil
+}
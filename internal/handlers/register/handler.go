@@ -2,6 +2,7 @@ package register
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,13 +14,20 @@ type register interface {
 	RegisterUser(ctx context.Context, user models.User) (string, error)
 }
 
+// roles - роли, заданные в политике rbac
+type roles interface {
+	HasRole(role models.UserRole) bool
+}
+
 type Handler struct {
 	register register
+	roles    roles
 }
 
-func NewHandler(register register) *Handler {
+func NewHandler(register register, roles roles) *Handler {
 	return &Handler{
 		register: register,
+		roles:    roles,
 	}
 }
 
@@ -28,22 +36,24 @@ func (h *Handler) Register(ctx *fiber.Ctx) error {
 
 	if err := ctx.BodyParser(&req); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "invalid request body",
 		})
 	}
 
-	user, err := req.validate()
-	if err != nil {
+	user, err := req.validate(h.roles)
+	if errors.Is(err, errSelfRegistrationRole) {
+		return err
+	} else if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: fmt.Sprintf("invalid request body: %s", err.Error()),
 		})
 	}
 
-	userID, err := h.register.RegisterUser(ctx.Context(), user)
+	userID, err := h.register.RegisterUser(ctx.UserContext(), user)
 	if err != nil {
-		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResp{
-			Message: fmt.Sprintf("register user failed: %s", err.Error()),
-		})
+		return err
 	}
 
 	return ctx.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,7 +16,9 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"AvitoPVZ/internal/handlers/register"
+	"AvitoPVZ/internal/middleware/errhandler"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
 )
 
 type mockRegister struct {
@@ -33,9 +37,9 @@ type RegisterHandlerSuite struct {
 }
 
 func (s *RegisterHandlerSuite) SetupTest() {
-	s.app = fiber.New()
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 	s.mockReg = new(mockRegister)
-	handler := register.NewHandler(s.mockReg)
+	handler := register.NewHandler(s.mockReg, testutil.DefaultRoles)
 
 	s.app.Post("/register", handler.Register)
 }
@@ -103,6 +107,24 @@ func (s *RegisterHandlerSuite) Test_Register_WeakPassword() {
 	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
 }
 
+func (s *RegisterHandlerSuite) Test_Register_PasswordLongerThanBcryptLimit() {
+	reqBody := map[string]string{
+		"email":    "test@example.com",
+		"password": strings.Repeat("Пароль-9!", 8), // 72 символа, но больше 72 байт
+		"role":     string(models.RoleEmployee),
+	}
+	bodyBytes, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+	s.mockReg.AssertNotCalled(s.T(), "RegisterUser", mock.Anything, mock.Anything)
+}
+
 func (s *RegisterHandlerSuite) Test_Register_InvalidRole() {
 	reqBody := map[string]string{
 		"email":    "test@example.com",
@@ -124,7 +146,7 @@ func (s *RegisterHandlerSuite) Test_Register_UseCaseError() {
 	reqBody := map[string]string{
 		"email":    "test@example.com",
 		"password": "StrongPassword123!",
-		"role":     string(models.RoleModerator),
+		"role":     string(models.RoleEmployee),
 	}
 	bodyBytes, _ := json.Marshal(reqBody)
 
@@ -144,10 +166,68 @@ func (s *RegisterHandlerSuite) Test_Register_UseCaseError() {
 	resp, err := s.app.Test(req)
 
 	s.Require().NoError(err)
-	s.Equal(fiber.StatusBadRequest, resp.StatusCode)
+	s.Equal(fiber.StatusInternalServerError, resp.StatusCode)
 	s.mockReg.AssertExpectations(s.T())
 }
 
+func (s *RegisterHandlerSuite) Test_Register_PrivilegedRoleForbidden() {
+	for _, role := range []models.UserRole{models.RoleModerator, models.RoleAdmin} {
+		bodyBytes, _ := json.Marshal(map[string]string{
+			"email":    "test@example.com",
+			"password": "StrongPassword123!",
+			"role":     string(role),
+		})
+
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.app.Test(req)
+		s.Require().NoError(err)
+		s.Equal(fiber.StatusForbidden, resp.StatusCode, role)
+	}
+	s.mockReg.AssertNotCalled(s.T(), "RegisterUser", mock.Anything, mock.Anything)
+}
+
+func (s *RegisterHandlerSuite) Test_Register_DefaultsToEmployee() {
+	bodyBytes, _ := json.Marshal(map[string]string{
+		"email":    "test@example.com",
+		"password": "StrongPassword123!",
+	})
+
+	s.mockReg.On("RegisterUser", mock.Anything, mock.MatchedBy(func(u models.User) bool {
+		return u.Role == models.RoleEmployee
+	})).Return(uuid.NewString(), nil)
+
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusCreated, resp.StatusCode)
+	s.mockReg.AssertExpectations(s.T())
+}
+
+func (s *RegisterHandlerSuite) Test_Register_DuplicateEmail() {
+	bodyBytes, _ := json.Marshal(map[string]string{
+		"email":    "taken@example.com",
+		"password": "StrongPassword123!",
+	})
+
+	s.mockReg.On("RegisterUser", mock.Anything, mock.Anything).
+		Return("", fmt.Errorf("failed of create user: %w", models.ErrEmailTaken))
+
+	req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusConflict, resp.StatusCode)
+
+	var body models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Equal("EMAIL_TAKEN", body.Code)
+}
+
 func TestRegisterHandlerSuite(t *testing.T) {
 	suite.Run(t, new(RegisterHandlerSuite))
 }
@@ -10,30 +10,45 @@ import (
 	"AvitoPVZ/internal/models"
 )
 
+var errSelfRegistrationRole = models.ErrForbidden.WithMessage("self-registration is only allowed for employees")
+
 type userAuthIn struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,max=255"`
-	Role     string `json:"role" validate:"required"`
+	Role     string `json:"role" validate:"omitempty"`
 }
 
-func (u *userAuthIn) validate() (models.User, error) {
+func (u *userAuthIn) validate(roles roles) (models.User, error) {
 	validate := validator.New()
 	if err := validate.Struct(u); err != nil {
-		return models.User{}, fmt.Errorf("%s: %w", models.ErrValidation, err)
+		return models.User{}, fmt.Errorf("%w: %s", models.ErrValidation, err)
 	}
 
+	if len(u.Password) > models.MaxPasswordBytes {
+		return models.User{}, fmt.Errorf("%w: password is longer than %d bytes", models.ErrValidation, models.MaxPasswordBytes)
+	}
 	if err := passwordValidator.Validate(u.Password, float64(models.MinEntropyBits)); err != nil {
 		return models.User{}, fmt.Errorf("password is too simple: %w", err)
 	}
 
-	if !models.IsUserRole(models.UserRole(u.Role)) {
+	role := models.RoleEmployee
+	if u.Role != "" {
+		role = models.UserRole(u.Role)
+	}
+
+	if !roles.HasRole(role) {
 		return models.User{}, fmt.Errorf("%s is not a valid role", u.Role)
 	}
 
+	// Модераторов и администраторов назначает администратор, самостоятельно регистрируются только сотрудники
+	if role != models.RoleEmployee {
+		return models.User{}, errSelfRegistrationRole
+	}
+
 	return models.User{
 		ID:       uuid.New(),
 		Email:    u.Email,
 		Password: u.Password,
-		Role:     models.UserRole(u.Role),
+		Role:     role,
 	}, nil
 }
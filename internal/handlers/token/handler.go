@@ -0,0 +1,81 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+type TokenUseCase interface {
+	Refresh(ctx context.Context, refreshToken string) (models.RefreshToken, error)
+	Logout(ctx context.Context, userID, jti uuid.UUID, accessExpiresAt time.Time, refreshToken string) error
+}
+
+type Handler struct {
+	UC TokenUseCase
+}
+
+func NewHandler(uc TokenUseCase) *Handler {
+	return &Handler{UC: uc}
+}
+
+// Refresh - погашение refresh-токена; новую пару токенов выпускает следующий обработчик (jwt.SignedToken)
+func (h *Handler) Refresh(c *fiber.Ctx) error {
+	var req refreshReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "invalid request body",
+		})
+	}
+
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	session, err := h.UC.Refresh(c.UserContext(), req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	c.Locals("UserID", session.UserID)
+	c.Locals("Role", session.Role)
+
+	return c.Next()
+}
+
+// Logout - отзыв текущего access-токена и переданного refresh-токена
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	userID, okUser := c.Locals("UserID").(uuid.UUID)
+	jti, okID := c.Locals("TokenID").(uuid.UUID)
+	expiresAt, okExp := c.Locals("TokenExpiresAt").(time.Time)
+	if !okUser || !okID || !okExp {
+		return models.ErrAuthUser
+	}
+
+	var req logoutReq
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+				Code:    models.CodeValidation,
+				Message: "invalid request body",
+			})
+		}
+		if err := req.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := h.UC.Logout(c.UserContext(), userID, jti, expiresAt, req.RefreshToken); err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"description": "Выход выполнен",
+	})
+}
@@ -0,0 +1,108 @@
+package token
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+type mockTokenUseCase struct {
+	session      models.RefreshToken
+	err          error
+	refreshToken string
+	revokedJTI   uuid.UUID
+}
+
+func (m *mockTokenUseCase) Refresh(_ context.Context, refreshToken string) (models.RefreshToken, error) {
+	m.refreshToken = refreshToken
+	return m.session, m.err
+}
+
+func (m *mockTokenUseCase) Logout(_ context.Context, _, jti uuid.UUID, _ time.Time, refreshToken string) error {
+	m.revokedJTI = jti
+	m.refreshToken = refreshToken
+	return m.err
+}
+
+type TokenHandlerTestSuite struct {
+	suite.Suite
+	app     *fiber.App
+	useCase *mockTokenUseCase
+	jti     uuid.UUID
+}
+
+func (suite *TokenHandlerTestSuite) SetupTest() {
+	suite.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	suite.useCase = &mockTokenUseCase{}
+	suite.jti = uuid.New()
+	handler := NewHandler(suite.useCase)
+
+	suite.app.Post("/token/refresh", handler.Refresh, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"userId": c.Locals("UserID"), "role": c.Locals("Role")})
+	})
+	suite.app.Post("/logout", func(c *fiber.Ctx) error {
+		c.Locals("UserID", uuid.New())
+		c.Locals("TokenID", suite.jti)
+		c.Locals("TokenExpiresAt", time.Now().Add(time.Minute))
+		return c.Next()
+	}, handler.Logout)
+}
+
+func (suite *TokenHandlerTestSuite) post(path, body string) *http.Response {
+	req := httptest.NewRequest("POST", path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	return resp
+}
+
+func (suite *TokenHandlerTestSuite) TestRefreshPassesSessionToSigner() {
+	suite.useCase.session = models.RefreshToken{UserID: uuid.New(), Role: models.RoleEmployee}
+
+	resp := suite.post("/token/refresh", `{"refreshToken": "abc"}`)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal("abc", suite.useCase.refreshToken)
+}
+
+func (suite *TokenHandlerTestSuite) TestRefreshMissingToken() {
+	resp := suite.post("/token/refresh", `{}`)
+	suite.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *TokenHandlerTestSuite) TestRefreshInvalidToken() {
+	suite.useCase.err = models.ErrInvalidRefreshToken
+
+	resp := suite.post("/token/refresh", `{"refreshToken": "abc"}`)
+	suite.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *TokenHandlerTestSuite) TestLogout() {
+	resp := suite.post("/logout", `{"refreshToken": "abc"}`)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(suite.jti, suite.useCase.revokedJTI)
+	suite.Equal("abc", suite.useCase.refreshToken)
+}
+
+func (suite *TokenHandlerTestSuite) TestLogoutWithoutBody() {
+	req := httptest.NewRequest("POST", "/logout", nil)
+	resp, err := suite.app.Test(req)
+	suite.Require().NoError(err)
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Equal(suite.jti, suite.useCase.revokedJTI)
+	suite.Empty(suite.useCase.refreshToken)
+}
+
+func TestTokenHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(TokenHandlerTestSuite))
+}
@@ -0,0 +1,33 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"AvitoPVZ/internal/models"
+)
+
+type refreshReq struct {
+	RefreshToken string `json:"refreshToken" validate:"required,max=128"`
+}
+
+func (r *refreshReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return nil
+}
+
+type logoutReq struct {
+	RefreshToken string `json:"refreshToken" validate:"omitempty,max=128"`
+}
+
+func (r *logoutReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return nil
+}
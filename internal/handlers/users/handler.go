@@ -0,0 +1,121 @@
+package users
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+type UserUseCase interface {
+	List(ctx context.Context, limit, offset int) ([]models.User, error)
+	ChangeRole(ctx context.Context, id uuid.UUID, role models.UserRole) (models.User, error)
+	Disable(ctx context.Context, id uuid.UUID) (models.User, error)
+	Enable(ctx context.Context, id uuid.UUID) (models.User, error)
+	ResetPassword(ctx context.Context, id uuid.UUID) (string, error)
+}
+
+// Handler - управление пользователями, доступное администратору
+type Handler struct {
+	UC UserUseCase
+}
+
+func NewHandler(uc UserUseCase) *Handler {
+	return &Handler{UC: uc}
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	req := listReq{
+		Limit:  c.QueryInt("limit", defaultLimit),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	users, err := h.UC.List(c.UserContext(), req.Limit, req.Offset)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]userResp, len(users))
+	for i, user := range users {
+		resp[i] = toResponse(user)
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func (h *Handler) ChangeRole(c *fiber.Ctx) error {
+	userID, err := parseUserID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	var req roleReq
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
+			Message: "invalid request body",
+		})
+	}
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	user, err := h.UC.ChangeRole(c.UserContext(), userID, models.UserRole(req.Role))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(toResponse(user))
+}
+
+func (h *Handler) Disable(c *fiber.Ctx) error {
+	userID, err := parseUserID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	user, err := h.UC.Disable(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(toResponse(user))
+}
+
+func (h *Handler) Enable(c *fiber.Ctx) error {
+	userID, err := parseUserID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	user, err := h.UC.Enable(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(toResponse(user))
+}
+
+// ResetPassword - временный пароль возвращается только в этом ответе и нигде не сохраняется в открытом виде
+func (h *Handler) ResetPassword(c *fiber.Ctx) error {
+	userID, err := parseUserID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	password, err := h.UC.ResetPassword(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"temporaryPassword": password,
+	})
+}
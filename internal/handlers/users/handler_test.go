@@ -0,0 +1,165 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+type mockUserUseCase struct {
+	users    []models.User
+	err      error
+	id       uuid.UUID
+	role     models.UserRole
+	limit    int
+	offset   int
+	password string
+}
+
+func (m *mockUserUseCase) List(_ context.Context, limit, offset int) ([]models.User, error) {
+	m.limit, m.offset = limit, offset
+	return m.users, m.err
+}
+
+func (m *mockUserUseCase) ChangeRole(_ context.Context, id uuid.UUID, role models.UserRole) (models.User, error) {
+	m.id, m.role = id, role
+	return models.User{ID: id, Email: "user@example.com", Role: role}, m.err
+}
+
+func (m *mockUserUseCase) Disable(_ context.Context, id uuid.UUID) (models.User, error) {
+	m.id = id
+	now := time.Now()
+	return models.User{ID: id, Role: models.RoleEmployee, DisabledAt: &now}, m.err
+}
+
+func (m *mockUserUseCase) Enable(_ context.Context, id uuid.UUID) (models.User, error) {
+	m.id = id
+	return models.User{ID: id, Role: models.RoleEmployee}, m.err
+}
+
+func (m *mockUserUseCase) ResetPassword(_ context.Context, id uuid.UUID) (string, error) {
+	m.id = id
+	return m.password, m.err
+}
+
+type UsersHandlerTestSuite struct {
+	suite.Suite
+	app *fiber.App
+	uc  *mockUserUseCase
+}
+
+func (s *UsersHandlerTestSuite) SetupTest() {
+	s.uc = &mockUserUseCase{}
+	handler := NewHandler(s.uc)
+
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.app.Get("/users", handler.List)
+	s.app.Patch("/users/:id/role", handler.ChangeRole)
+	s.app.Post("/users/:id/disable", handler.Disable)
+	s.app.Post("/users/:id/enable", handler.Enable)
+	s.app.Post("/users/:id/reset_password", handler.ResetPassword)
+}
+
+func (s *UsersHandlerTestSuite) do(method, path, body string) *http.Response {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *UsersHandlerTestSuite) TestListDefaults() {
+	s.uc.users = []models.User{{ID: uuid.New(), Email: "a@example.com", Password: "hash", Role: models.RoleAdmin}}
+
+	resp := s.do("GET", "/users", "")
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(defaultLimit, s.uc.limit)
+	s.Equal(0, s.uc.offset)
+
+	var body []map[string]any
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Require().Len(body, 1)
+	s.Equal("a@example.com", body[0]["email"])
+	s.NotContains(body[0], "password")
+	s.Equal(false, body[0]["disabled"])
+}
+
+func (s *UsersHandlerTestSuite) TestListInvalidLimit() {
+	resp := s.do("GET", "/users?limit=1000", "")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UsersHandlerTestSuite) TestChangeRole() {
+	id := uuid.New()
+
+	resp := s.do("PATCH", "/users/"+id.String()+"/role", `{"role":"moderator"}`)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(id, s.uc.id)
+	s.Equal(models.RoleModerator, s.uc.role)
+}
+
+func (s *UsersHandlerTestSuite) TestChangeRoleMissingRole() {
+	resp := s.do("PATCH", "/users/"+uuid.NewString()+"/role", `{}`)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UsersHandlerTestSuite) TestChangeRoleLastAdmin() {
+	s.uc.err = models.ErrLastAdmin
+
+	resp := s.do("PATCH", "/users/"+uuid.NewString()+"/role", `{"role":"employee"}`)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *UsersHandlerTestSuite) TestDisable() {
+	id := uuid.New()
+
+	resp := s.do("POST", "/users/"+id.String()+"/disable", "")
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(id, s.uc.id)
+
+	var body userResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.True(body.Disabled)
+	s.NotNil(body.DisabledAt)
+}
+
+func (s *UsersHandlerTestSuite) TestDisableInvalidID() {
+	resp := s.do("POST", "/users/not-a-uuid/disable", "")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UsersHandlerTestSuite) TestEnableNotFound() {
+	s.uc.err = models.ErrUserNotFound
+
+	resp := s.do("POST", "/users/"+uuid.NewString()+"/enable", "")
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *UsersHandlerTestSuite) TestResetPassword() {
+	s.uc.password = "temporary-password"
+
+	resp := s.do("POST", "/users/"+uuid.NewString()+"/reset_password", "")
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal("no-store", resp.Header.Get("Cache-Control"))
+
+	var body map[string]string
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Equal("temporary-password", body["temporaryPassword"])
+}
+
+func TestUsersHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(UsersHandlerTestSuite))
+}
@@ -0,0 +1,67 @@
+package users
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+)
+
+const (
+	defaultLimit = 50
+)
+
+type listReq struct {
+	Limit  int `validate:"min=1,max=500"`
+	Offset int `validate:"min=0"`
+}
+
+func (r *listReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return nil
+}
+
+type roleReq struct {
+	Role string `json:"role" validate:"required"`
+}
+
+func (r *roleReq) validate() error {
+	if err := validator.New().Struct(r); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrValidation, err)
+	}
+
+	return nil
+}
+
+func parseUserID(id string) (uuid.UUID, error) {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: user id is invalid", models.ErrValidation)
+	}
+
+	return userID, nil
+}
+
+type userResp struct {
+	ID         uuid.UUID       `json:"id"`
+	Email      string          `json:"email"`
+	Role       models.UserRole `json:"role"`
+	Disabled   bool            `json:"disabled"`
+	DisabledAt *time.Time      `json:"disabledAt,omitempty"`
+}
+
+func toResponse(user models.User) userResp {
+	return userResp{
+		ID:         user.ID,
+		Email:      user.Email,
+		Role:       user.Role,
+		Disabled:   user.DisabledAt != nil,
+		DisabledAt: user.DisabledAt,
+	}
+}
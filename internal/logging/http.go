@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/middleware/route"
+)
+
+// HeaderRequestID - идентификатор запроса от клиента или балансировщика; отдаётся и в ответе
+const HeaderRequestID = "X-Request-ID"
+
+// validRequestID ограничивает принятый от клиента идентификатор, чтобы в журнал не попадал произвольный текст
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware присваивает запросу идентификатор (из X-Request-ID или новый), кладёт его
+// в c.UserContext() для всех записей журнала и по завершении пишет строку о запросе
+func Middleware(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestID := c.Get(HeaderRequestID)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(HeaderRequestID, requestID)
+		c.SetUserContext(WithAttrs(c.UserContext(), slog.String("request_id", requestID)))
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		// Контекст берётся после c.Next(): CompareToken дополняет его пользователем и ролью
+		logger.LogAttrs(c.UserContext(), level, "request",
+			slog.String("method", c.Method()),
+			slog.String("route", route.Template(c)),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("ip", c.IP()),
+		)
+
+		return err
+	}
+}
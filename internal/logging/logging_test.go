@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+type LoggingTestSuite struct {
+	suite.Suite
+	buf    *bytes.Buffer
+	logger *slog.Logger
+}
+
+func (s *LoggingTestSuite) SetupTest() {
+	s.buf = new(bytes.Buffer)
+	s.logger = New(s.buf, slog.LevelDebug)
+}
+
+// entries - записи журнала, по одной JSON-строке на запись
+func (s *LoggingTestSuite) entries() []map[string]any {
+	var result []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(s.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		s.Require().NoError(json.Unmarshal([]byte(line), &entry))
+		result = append(result, entry)
+	}
+	return result
+}
+
+func (s *LoggingTestSuite) Test_RedactsSecrets() {
+	s.logger.Info("connecting to postgres://app:s3cr3t@db:5432/pvz",
+		slog.String("password", "plain"),
+		slog.String("refresh_token", "abc"),
+		slog.Any("error", errors.New("dial postgres://app:s3cr3t@db:5432/pvz: refused")),
+		slog.String("email", "user@example.com"),
+	)
+
+	s.NotContains(s.buf.String(), "s3cr3t")
+	entry := s.entries()[0]
+	s.Equal(Redacted, entry["password"])
+	s.Equal(Redacted, entry["refresh_token"])
+	s.Equal("connecting to postgres://app:"+Redacted+"@db:5432/pvz", entry["msg"])
+	s.Equal("user@example.com", entry["email"])
+}
+
+func (s *LoggingTestSuite) Test_RedactsSecretPairsInMessage() {
+	s.logger.Info("password reset for user@example.com: token=abc123 expires_at=2026-04-01T12:30:00Z",
+		slog.String("detail", `refresh_token: "abc123", Password=qwerty`),
+	)
+
+	s.NotContains(s.buf.String(), "abc123")
+	s.NotContains(s.buf.String(), "qwerty")
+	entry := s.entries()[0]
+	s.Equal("password reset for user@example.com: token="+Redacted+" expires_at=2026-04-01T12:30:00Z", entry["msg"])
+	s.Equal("refresh_token: "+Redacted+", Password="+Redacted, entry["detail"])
+}
+
+func (s *LoggingTestSuite) Test_ContextAttrs() {
+	ctx := WithAttrs(context.Background(), slog.String("request_id", "req-1"))
+	ctx = WithAttrs(ctx, slog.String("user_id", "u-1"), slog.String("role", "employee"))
+
+	s.logger.With(slog.String("component", "login")).ErrorContext(ctx, "failed")
+
+	entry := s.entries()[0]
+	s.Equal("req-1", entry["request_id"])
+	s.Equal("u-1", entry["user_id"])
+	s.Equal("employee", entry["role"])
+	s.Equal("login", entry["component"])
+}
+
+func (s *LoggingTestSuite) app() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	app.Use(Middleware(s.logger))
+	app.Use(errhandler.Middleware())
+	app.Get("/pvz/:pvzId", func(c *fiber.Ctx) error {
+		// Так пользователь попадает в контекст в jwt.CompareToken
+		c.SetUserContext(WithAttrs(c.UserContext(), slog.String("user_id", "u-1"), slog.String("role", "moderator")))
+		s.logger.InfoContext(c.UserContext(), "use case")
+		if c.Params("pvzId") == "missing" {
+			return models.ErrPVZNotFound
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func (s *LoggingTestSuite) Test_Middleware_HonoursRequestID() {
+	req := httptest.NewRequest("GET", "/pvz/42", nil)
+	req.Header.Set(HeaderRequestID, "lb-7f3a")
+
+	resp, err := s.app().Test(req)
+	s.Require().NoError(err)
+	s.Equal("lb-7f3a", resp.Header.Get(HeaderRequestID))
+
+	entries := s.entries()
+	s.Require().Len(entries, 2)
+	for _, entry := range entries {
+		s.Equal("lb-7f3a", entry["request_id"])
+		s.Equal("u-1", entry["user_id"])
+		s.Equal("moderator", entry["role"])
+	}
+
+	access := entries[1]
+	s.Equal("request", access["msg"])
+	s.Equal("/pvz/:pvzId", access["route"])
+	s.Equal(float64(fiber.StatusOK), access["status"])
+}
+
+func (s *LoggingTestSuite) Test_Middleware_ReplacesInvalidRequestID() {
+	req := httptest.NewRequest("GET", "/pvz/missing", nil)
+	req.Header.Set(HeaderRequestID, "bad id\nINFO forged entry")
+
+	resp, err := s.app().Test(req)
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusNotFound, resp.StatusCode)
+
+	requestID := resp.Header.Get(HeaderRequestID)
+	s.Len(requestID, 36)
+
+	access := s.entries()[1]
+	s.Equal(requestID, access["request_id"])
+	s.Equal(float64(fiber.StatusNotFound), access["status"])
+	s.Equal("INFO", access["level"])
+}
+
+func (s *LoggingTestSuite) Test_ParseLevel() {
+	level, err := ParseLevel("warn")
+	s.Require().NoError(err)
+	s.Equal(slog.LevelWarn, level)
+
+	_, err = ParseLevel("verbose")
+	s.Error(err)
+}
+
+func TestLoggingTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggingTestSuite))
+}
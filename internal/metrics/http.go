@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"AvitoPVZ/internal/middleware/route"
+)
+
+// Middleware измеряет длительность запроса с меткой шаблона маршрута (/pvz/:pvzId), а не пути
+func (m *Metrics) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		m.httpDuration.
+			WithLabelValues(route.Template(c), c.Method(), strconv.Itoa(c.Response().StatusCode())).
+			Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// Handler отдаёт метрики в формате Prometheus
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,105 @@
+// Package metrics - метрики Prometheus: HTTP, пул соединений pgx и доменные счётчики.
+// Доменные счётчики увеличивают use case'ы через свои узкие интерфейсы, HTTP-метрики - middleware.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+
+	"AvitoPVZ/internal/models"
+)
+
+const namespace = "avito_pvz"
+
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpDuration         *prometheus.HistogramVec
+	receptionsOpened     *prometheus.CounterVec
+	receptionsClosed     *prometheus.CounterVec
+	productsAccepted     *prometheus.CounterVec
+	productsDeleted      prometheus.Counter
+	loginFailures        *prometheus.CounterVec
+	serializationRetries *prometheus.CounterVec
+}
+
+// New создаёт метрики в собственном реестре вместе со стандартными метриками процесса и Go
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		receptionsOpened: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "receptions_opened_total",
+			Help:      "Receptions opened, by pickup point city.",
+		}, []string{"city"}),
+		receptionsClosed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "receptions_closed_total",
+			Help:      "Receptions closed, by pickup point city.",
+		}, []string{"city"}),
+		productsAccepted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "products_accepted_total",
+			Help:      "Products added to receptions, by product type.",
+		}, []string{"type"}),
+		productsDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "products_deleted_total",
+			Help:      "Products removed from open receptions.",
+		}),
+		loginFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "login_failures_total",
+			Help:      "Rejected login attempts, by reason.",
+		}, []string{"reason"}),
+		serializationRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_transaction_retries_total",
+			Help:      "Serializable transactions retried after a serialization failure or deadlock, by SQLSTATE.",
+		}, []string{"sqlstate"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.receptionsOpened,
+		m.receptionsClosed,
+		m.productsAccepted,
+		m.productsDeleted,
+		m.loginFailures,
+		m.serializationRetries,
+	)
+
+	return m
+}
+
+func (m *Metrics) ReceptionOpened(city models.PVZCity) {
+	m.receptionsOpened.WithLabelValues(string(city)).Inc()
+}
+
+func (m *Metrics) ReceptionClosed(city models.PVZCity) {
+	m.receptionsClosed.WithLabelValues(string(city)).Inc()
+}
+
+func (m *Metrics) ProductAccepted(productType models.TypeProduct) {
+	m.productsAccepted.WithLabelValues(string(productType)).Inc()
+}
+
+func (m *Metrics) ProductDeleted() {
+	m.productsDeleted.Inc()
+}
+
+func (m *Metrics) LoginFailed(reason string) {
+	m.loginFailures.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) TransactionRetried(sqlState string) {
+	m.serializationRetries.WithLabelValues(sqlState).Inc()
+}
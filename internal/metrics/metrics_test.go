@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"io"
+	"maps"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/middleware/route"
+	"AvitoPVZ/internal/models"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	m   *Metrics
+	app *fiber.App
+}
+
+func (s *MetricsTestSuite) SetupTest() {
+	s.m = New()
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.app.Use(s.m.Middleware())
+	s.app.Use(errhandler.Middleware())
+	s.app.Get("/pvz/:pvzId", func(c *fiber.Ctx) error {
+		if c.Params("pvzId") == "missing" {
+			return models.ErrPVZNotFound
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+	s.app.Get("/metrics", s.m.Handler())
+}
+
+func (s *MetricsTestSuite) do(method, path string) int {
+	resp, err := s.app.Test(httptest.NewRequest(method, path, nil))
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+// observations - число измерений длительности с заданными метками
+func (s *MetricsTestSuite) observations(route, method, status string) uint64 {
+	families, err := s.m.Registry.Gather()
+	s.Require().NoError(err)
+
+	want := map[string]string{"route": route, "method": method, "status": status}
+	for _, family := range families {
+		if family.GetName() != "avito_pvz_http_request_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			got := make(map[string]string, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				got[label.GetName()] = label.GetValue()
+			}
+			if maps.Equal(got, want) {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
+
+func (s *MetricsTestSuite) Test_Middleware_LabelsByRouteTemplate() {
+	s.Equal(fiber.StatusOK, s.do("GET", "/pvz/1"))
+	s.Equal(fiber.StatusOK, s.do("GET", "/pvz/2"))
+
+	s.Equal(uint64(2), s.observations("/pvz/:pvzId", "GET", "200"))
+}
+
+func (s *MetricsTestSuite) Test_Middleware_RecordsErrorStatus() {
+	s.Equal(fiber.StatusNotFound, s.do("GET", "/pvz/missing"))
+
+	s.Equal(uint64(1), s.observations("/pvz/:pvzId", "GET", "404"))
+}
+
+func (s *MetricsTestSuite) Test_Middleware_UnmatchedRoute() {
+	s.Equal(fiber.StatusNotFound, s.do("GET", "/wp-admin/setup.php"))
+
+	s.Equal(uint64(1), s.observations(route.Unmatched, "GET", "404"))
+}
+
+func (s *MetricsTestSuite) Test_DomainCounters() {
+	s.m.ReceptionOpened(models.CityKazan)
+	s.m.ReceptionOpened(models.CityKazan)
+	s.m.ReceptionClosed(models.CityMoscow)
+	s.m.ProductAccepted(models.TypeShoes)
+	s.m.ProductDeleted()
+	s.m.LoginFailed("locked")
+	s.m.TransactionRetried("40001")
+
+	s.Equal(2.0, testutil.ToFloat64(s.m.receptionsOpened.WithLabelValues(string(models.CityKazan))))
+	s.Equal(1.0, testutil.ToFloat64(s.m.receptionsClosed.WithLabelValues(string(models.CityMoscow))))
+	s.Equal(1.0, testutil.ToFloat64(s.m.productsAccepted.WithLabelValues(string(models.TypeShoes))))
+	s.Equal(1.0, testutil.ToFloat64(s.m.productsDeleted))
+	s.Equal(1.0, testutil.ToFloat64(s.m.loginFailures.WithLabelValues("locked")))
+	s.Equal(1.0, testutil.ToFloat64(s.m.serializationRetries.WithLabelValues("40001")))
+}
+
+func (s *MetricsTestSuite) Test_Handler_ExposesMetrics() {
+	s.m.ProductDeleted()
+
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/metrics", nil))
+	s.Require().NoError(err)
+	s.Equal(fiber.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err)
+	s.True(strings.Contains(string(body), "avito_pvz_products_deleted_total 1"))
+	s.True(strings.Contains(string(body), "go_goroutines"))
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stater - источник статистики пула (pgxpool.Pool)
+type Stater interface {
+	Stat() *pgxpool.Stat
+}
+
+// poolCollector снимает статистику пула в момент запроса /metrics, поэтому не требует фонового опроса
+type poolCollector struct {
+	pool Stater
+
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	constructingConns *prometheus.Desc
+	totalConns        *prometheus.Desc
+	maxConns          *prometheus.Desc
+	acquires          *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	newConns          *prometheus.Desc
+}
+
+// RegisterPool добавляет в реестр метрики пула соединений с меткой pool=name
+func (m *Metrics) RegisterPool(name string, pool Stater) {
+	labels := prometheus.Labels{"pool": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", metric), help, nil, labels)
+	}
+
+	m.Registry.MustRegister(&poolCollector{
+		pool:              pool,
+		acquiredConns:     desc("acquired_connections", "Connections currently checked out of the pool."),
+		idleConns:         desc("idle_connections", "Idle connections in the pool."),
+		constructingConns: desc("constructing_connections", "Connections being established."),
+		totalConns:        desc("total_connections", "All connections owned by the pool."),
+		maxConns:          desc("max_connections", "Configured maximum pool size."),
+		acquires:          desc("acquires_total", "Successful connection acquisitions."),
+		emptyAcquires:     desc("empty_acquires_total", "Acquisitions that had to wait because the pool was empty."),
+		canceledAcquires:  desc("canceled_acquires_total", "Acquisitions canceled by the caller's context."),
+		acquireDuration:   desc("acquire_duration_seconds_total", "Total time spent waiting for connections."),
+		newConns:          desc("new_connections_total", "Connections opened by the pool."),
+	})
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireDuration
+	ch <- c.newConns
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(s.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.newConns, prometheus.CounterValue, float64(s.NewConnsCount()))
+}
@@ -0,0 +1,96 @@
+// Package errhandler - центральный обработчик ошибок Fiber.
+// Доменные ошибки models.DomainError превращаются в ответ с их статусом и кодом,
+// остальные ошибки скрываются за 500, чтобы не раскрывать детали хранилища.
+package errhandler
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+const internalMessage = "internal server error"
+
+// localsErr - ключ c.Locals() с ошибкой, которую обработал Middleware
+const localsErr = "errhandler.err"
+
+// Middleware превращает ошибку обработчика в ответ через Handle. Регистрируется последним
+// в app.Use, чтобы журнал, трассировка и метрики снаружи видели итоговый статус ответа
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err == nil {
+			return nil
+		}
+
+		c.Locals(localsErr, err)
+		if handleErr := Handle(c, err); handleErr != nil {
+			return c.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		return nil
+	}
+}
+
+// Err возвращает ошибку обработчика, уже превращённую Middleware в ответ
+func Err(c *fiber.Ctx) error {
+	err, _ := c.Locals(localsErr).(error)
+	return err
+}
+
+// Handle используется как fiber.Config.ErrorHandler. Ошибки 5xx пишутся с контекстом запроса
+// в slog.Default(), который приложение заменяет своим логгером при запуске
+func Handle(c *fiber.Ctx, err error) error {
+	status, resp := Resolve(err)
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(c.UserContext(), "request failed",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Any("error", err),
+		)
+	}
+
+	var retryErr *models.RetryAfterError
+	if errors.As(err, &retryErr) && retryErr.RetryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryErr.RetryAfter.Seconds()))))
+	}
+
+	return c.Status(status).JSON(resp)
+}
+
+// Resolve определяет HTTP-статус и тело ответа для ошибки
+func Resolve(err error) (int, models.ErrorResp) {
+	var domainErr *models.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Status, models.ErrorResp{Code: domainErr.Code, Message: err.Error()}
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		if fiberErr.Code >= http.StatusInternalServerError {
+			return fiberErr.Code, models.ErrorResp{Code: models.CodeInternal, Message: internalMessage}
+		}
+		return fiberErr.Code, models.ErrorResp{Code: codeForStatus(fiberErr.Code), Message: fiberErr.Message}
+	}
+
+	return http.StatusInternalServerError, models.ErrorResp{Code: models.CodeInternal, Message: internalMessage}
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return models.CodeUnauthorized
+	case http.StatusForbidden:
+		return models.CodeForbidden
+	case http.StatusNotFound:
+		return models.CodeNotFound
+	default:
+		return models.CodeValidation
+	}
+}
@@ -0,0 +1,124 @@
+package errhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/models"
+)
+
+type ErrHandlerTestSuite struct {
+	suite.Suite
+	app *fiber.App
+	err error
+}
+
+func (s *ErrHandlerTestSuite) SetupTest() {
+	s.app = fiber.New(fiber.Config{ErrorHandler: Handle})
+	s.app.Get("/", func(c *fiber.Ctx) error {
+		return s.err
+	})
+}
+
+func (s *ErrHandlerTestSuite) request() (int, models.ErrorResp) {
+	resp := s.response()
+
+	var body models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body
+}
+
+func (s *ErrHandlerTestSuite) response() *http.Response {
+	resp, err := s.app.Test(httptest.NewRequest("GET", "/", nil))
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *ErrHandlerTestSuite) TestDomainErrors() {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{models.ErrPVZNotFound, http.StatusNotFound, "PVZ_NOT_FOUND"},
+		{models.ErrReceptionAlreadyOpen, http.StatusConflict, "RECEPTION_ALREADY_OPEN"},
+		{models.ErrNoActiveReception, http.StatusUnprocessableEntity, "NO_ACTIVE_RECEPTION"},
+		{fmt.Errorf("failed to update PVZ: %w", models.ErrPVZNotFound), http.StatusNotFound, "PVZ_NOT_FOUND"},
+		{fmt.Errorf("%w: city is not allowed", models.ErrValidation), http.StatusBadRequest, models.CodeValidation},
+	}
+
+	for _, tc := range cases {
+		s.err = tc.err
+		status, body := s.request()
+		s.Equal(tc.status, status, tc.err.Error())
+		s.Equal(tc.code, body.Code)
+		s.Equal(tc.err.Error(), body.Message)
+	}
+}
+
+func (s *ErrHandlerTestSuite) TestUnknownErrorIsHidden() {
+	s.err = errors.New("нет активной приёмки: pq: relation does not exist")
+	status, body := s.request()
+	s.Equal(http.StatusInternalServerError, status)
+	s.Equal(models.CodeInternal, body.Code)
+	s.Equal(internalMessage, body.Message)
+}
+
+func (s *ErrHandlerTestSuite) TestFiberError() {
+	s.err = fiber.ErrNotFound
+	status, body := s.request()
+	s.Equal(http.StatusNotFound, status)
+	s.Equal(models.CodeNotFound, body.Code)
+}
+
+func (s *ErrHandlerTestSuite) TestRetryAfter() {
+	s.err = &models.RetryAfterError{Err: models.ErrTooManyAttempts, RetryAfter: 1500 * time.Millisecond}
+
+	resp := s.response()
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("2", resp.Header.Get("Retry-After"))
+
+	var body models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Equal("TOO_MANY_ATTEMPTS", body.Code)
+}
+
+func TestMiddleware_OuterMiddlewareSeesFinalStatus(t *testing.T) {
+	var (
+		status  int
+		handled error
+	)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		status, handled = c.Response().StatusCode(), Err(c)
+		return err
+	})
+	app.Use(Middleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return models.ErrPVZNotFound
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound || status != http.StatusNotFound {
+		t.Errorf("ожидался статус 404 и в ответе, и снаружи: %d, %d", resp.StatusCode, status)
+	}
+	if !errors.Is(handled, models.ErrPVZNotFound) {
+		t.Errorf("ошибка обработчика не передана наружу: %v", handled)
+	}
+}
+
+func TestErrHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ErrHandlerTestSuite))
+}
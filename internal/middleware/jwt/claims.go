@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"AvitoPVZ/internal/models"
+)
+
+// Claims - полезная нагрузка access-токена: sub - идентификатор пользователя, jti - идентификатор токена
+type Claims struct {
+	Role models.UserRole `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// tokenClaims разбирает как текущий формат, так и прежний, с полями ExpiresAt и UserID.
+// Прежний формат принимается, пока включён allowLegacy, и только до истечения токена.
+type tokenClaims struct {
+	Claims
+	LegacyExpiresAt *jwt.NumericDate `json:"ExpiresAt,omitempty"`
+	LegacyUserID    string           `json:"UserID,omitempty"`
+
+	issuer      string
+	audience    string
+	allowLegacy bool
+}
+
+var (
+	errLegacyClaims    = errors.New("legacy token claims are not accepted")
+	errMissingClaims   = errors.New("token has no subject or expiration")
+	errInvalidIssuer   = errors.New("token has invalid issuer")
+	errInvalidAudience = errors.New("token has invalid audience")
+)
+
+func (c *tokenClaims) legacy() bool {
+	return c.Subject == "" && c.LegacyUserID != ""
+}
+
+func (c *tokenClaims) userID() string {
+	if c.legacy() {
+		return c.LegacyUserID
+	}
+	return c.Subject
+}
+
+func (c *tokenClaims) expiresAt() time.Time {
+	if c.legacy() {
+		return c.LegacyExpiresAt.Time
+	}
+	return c.ExpiresAt.Time
+}
+
+// Valid проверяет exp, iat и nbf, а для токенов текущего формата ещё iss и aud
+func (c *tokenClaims) Valid() error {
+	if c.legacy() {
+		if !c.allowLegacy {
+			return errLegacyClaims
+		}
+		if c.LegacyExpiresAt == nil {
+			return errMissingClaims
+		}
+		if !c.LegacyExpiresAt.After(time.Now()) {
+			return &jwt.ValidationError{Errors: jwt.ValidationErrorExpired}
+		}
+		return nil
+	}
+
+	if c.Subject == "" || c.ExpiresAt == nil {
+		return errMissingClaims
+	}
+	if err := c.RegisteredClaims.Valid(); err != nil {
+		return err
+	}
+	if c.issuer != "" && !c.VerifyIssuer(c.issuer, true) {
+		return errInvalidIssuer
+	}
+	if c.audience != "" && !c.VerifyAudience(c.audience, true) {
+		return errInvalidAudience
+	}
+
+	return nil
+}
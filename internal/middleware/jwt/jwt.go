@@ -1,7 +1,10 @@
 package jwt
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -9,25 +12,62 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"AvitoPVZ/internal/logging"
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
 )
 
+// Sessions - выпуск refresh-токенов и проверка отзыва access-токенов. IsRevoked получает
+// пользователя и роль из токена, чтобы отвергать токены заблокированных и сменивших роль пользователей
+type Sessions interface {
+	IssueRefreshToken(ctx context.Context, userID uuid.UUID, role models.UserRole) (string, error)
+	IsRevoked(ctx context.Context, jti, userID uuid.UUID, role models.UserRole) (bool, error)
+}
+
+// Middleware подписывает токены активным ключом из Keys. Если набор ключей не задан,
+// токены подписываются HS256 на SecretKey. При заданных ключах HS256 принимается
+// только до SecretValidUntil - пока истекают токены, выпущенные до перехода на
+// асимметричную подпись; без SecretValidUntil не принимается вовсе.
 type Middleware struct {
-	SecretKey string
+	SecretKey        string
+	SecretValidUntil time.Time
+	Keys             *KeySet
+	AccessTTL        time.Duration
+	Validation       Validation
+	Sessions         Sessions
+}
+
+// Validation - ожидаемые iss и aud выпускаемых и принимаемых токенов. Пустое значение не проверяется.
+// AllowLegacy разрешает токены прежнего формата (ExpiresAt/UserID/Role) до их истечения.
+type Validation struct {
+	Issuer      string
+	Audience    string
+	AllowLegacy bool
 }
 
-func NewMiddleware(secretKey string) *Middleware {
+func NewMiddleware(secretKey string, keys *KeySet, accessTTL time.Duration, validation Validation, sessions Sessions) *Middleware {
+	if accessTTL <= 0 {
+		accessTTL = models.DurationJwtToken
+	}
+
 	return &Middleware{
-		SecretKey: secretKey,
+		SecretKey:  secretKey,
+		Keys:       keys,
+		AccessTTL:  accessTTL,
+		Validation: validation,
+		Sessions:   sessions,
 	}
 }
 
-// SignedToken - подписание JWT для авторизированного пользователя токена
+// SignedToken - подписание JWT для авторизированного пользователя токена и выпуск refresh-токена
 func (m *Middleware) SignedToken(ctx *fiber.Ctx) error {
 	userID, ok := ctx.Context().Value("UserID").(uuid.UUID)
 	if !ok {
 		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "header user is empty",
 		})
 	}
@@ -35,102 +75,198 @@ func (m *Middleware) SignedToken(ctx *fiber.Ctx) error {
 	userStatus, ok := ctx.Context().Value("Role").(models.UserRole)
 	if !ok {
 		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
+			Code:    models.CodeValidation,
 			Message: "header user is empty",
 		})
 	}
 
-	payload := jwt.MapClaims{
-		"ExpiresAt": jwt.NewNumericDate(time.Now().UTC().Add(models.DurationJwtToken)),
-		"IssuedAt":  jwt.NewNumericDate(time.Now().UTC()),
-		"UserID":    userID,
-		"Role":      userStatus,
+	now := time.Now().UTC()
+	jti := uuid.New()
+	claims := Claims{
+		Role: userStatus,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			Issuer:    m.Validation.Issuer,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.AccessTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ID:        jti.String(),
+		},
+	}
+	if m.Validation.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.Validation.Audience}
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
 
-	token.Header["kid"] = uuid.New().String()
+	jwtToken, err := m.sign(claims, jti)
+	if err != nil {
+		return err
+	}
 
-	secretKey := []byte(m.SecretKey)
+	resp := fiber.Map{
+		"Token":     jwtToken,
+		"ExpiresIn": int(m.AccessTTL.Seconds()),
+	}
 
-	jwtToken, err := token.SignedString(secretKey)
-	if err != nil {
-		return ctx.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: err.Error(),
-		})
+	if m.Sessions != nil {
+		refreshToken, err := m.Sessions.IssueRefreshToken(ctx.UserContext(), userID, userStatus)
+		if err != nil {
+			return err
+		}
+		resp["RefreshToken"] = refreshToken
 	}
 
 	ctx.Set(models.AuthorizationToken, jwtToken)
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"Token": jwtToken,
-	})
+	return ctx.Status(http.StatusOK).JSON(resp)
 }
 
 func (m *Middleware) CompareToken(c *fiber.Ctx) error {
 	tokenStr := c.Get(models.AuthorizationToken, "")
 	if tokenStr == "" {
 		return c.Status(http.StatusUnauthorized).JSON(models.ErrorResp{
+			Code:    models.CodeUnauthorized,
 			Message: "Token is empty",
 		})
 	}
 
 	tokenStr = strings.TrimPrefix(tokenStr, "Bearer ")
 
-	secretKey := []byte(m.SecretKey)
+	ctx, span := tracing.Start(c.UserContext(), "jwt.CompareToken")
+	verified, err := m.verify(ctx, tokenStr)
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+
+	trace.SpanFromContext(c.UserContext()).SetAttributes(
+		attribute.String("enduser.id", verified.userID.String()),
+		attribute.String("enduser.role", string(verified.claims.Role)),
+	)
+	c.SetUserContext(logging.WithAttrs(c.UserContext(),
+		slog.String("user_id", verified.userID.String()),
+		slog.String("role", string(verified.claims.Role)),
+	))
 
-	jwtToken, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	c.Locals("UserID", verified.userID)
+	c.Locals("Role", verified.claims.Role)
+	c.Locals("TokenID", verified.jti)
+	c.Locals("TokenExpiresAt", verified.claims.expiresAt())
+
+	return c.Next()
+}
+
+type verifiedToken struct {
+	claims *tokenClaims
+	userID uuid.UUID
+	jti    uuid.UUID
+}
+
+// verify проверяет подпись, срок, iss/aud, отзыв токена и то, что пользователь активен с той же ролью
+func (m *Middleware) verify(ctx context.Context, tokenStr string) (verifiedToken, error) {
+	claims := &tokenClaims{
+		issuer:      m.Validation.Issuer,
+		audience:    m.Validation.Audience,
+		allowLegacy: m.Validation.AllowLegacy,
+	}
+	jwtToken, err := jwt.ParseWithClaims(tokenStr, claims, m.verificationKey)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return verifiedToken{}, models.ErrTokenExpired
 		}
-		return secretKey, nil
-	})
+		return verifiedToken{}, models.ErrInvalidToken
+	}
+
+	userUUID, err := uuid.Parse(claims.userID())
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: fmt.Sprintf("JWT token is not valid: %v", err),
-		})
+		return verifiedToken{}, models.ErrInvalidToken
 	}
 
-	payload, ok := jwtToken.Claims.(jwt.MapClaims)
-	if !ok || !jwtToken.Valid {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: "Invalid token claims",
-		})
+	jti, err := tokenID(jwtToken, claims)
+	if err != nil {
+		return verifiedToken{}, models.ErrInvalidToken
 	}
 
-	expires, ok := payload["ExpiresAt"].(float64)
-	if !ok {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: "Missing or invalid 'ExpiresAt' field",
-		})
+	if m.Sessions != nil {
+		revoked, err := m.Sessions.IsRevoked(ctx, jti, userUUID, claims.Role)
+		if err != nil {
+			return verifiedToken{}, err
+		}
+		if revoked {
+			return verifiedToken{}, models.ErrTokenRevoked
+		}
 	}
 
-	expiresAt := time.Unix(int64(expires), 0)
-	if time.Now().After(expiresAt) {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: "Token is expired",
-		})
+	return verifiedToken{claims: claims, userID: userUUID, jti: jti}, nil
+}
+
+func (m *Middleware) sign(payload jwt.Claims, jti uuid.UUID) (string, error) {
+	if m.Keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+		token.Header["kid"] = jti.String()
+		return token.SignedString([]byte(m.SecretKey))
 	}
 
-	userStatus, ok := payload["Role"].(string)
-	if !ok {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: "Invalid status field",
-		})
+	key := m.Keys.SigningKey()
+	token := jwt.NewWithClaims(key.Method, payload)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// verificationKey выбирает ключ проверки по kid и не допускает подмены алгоритма:
+// HS256 принимается только на SecretKey, асимметричные подписи только ключом с тем же alg
+func (m *Middleware) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if !m.acceptsSecret(time.Now()) {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(m.SecretKey), nil
+	}
+
+	if m.Keys == nil {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
 
-	userID, ok := payload["UserID"].(string)
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.Keys.Lookup(kid)
 	if !ok {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{
-			Message: "Invalid status field",
-		})
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	if key.Method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 	}
 
-	userUUID, err := uuid.Parse(userID)
-	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(models.ErrorResp{})
+	return key.Public, nil
+}
+
+// acceptsSecret - принимаются ли сейчас токены HS256 на SecretKey
+func (m *Middleware) acceptsSecret(now time.Time) bool {
+	if m.SecretKey == "" {
+		return false
 	}
 
-	c.Locals("UserID", userUUID)
-	c.Locals("Role", models.UserRole(userStatus))
+	return m.Keys == nil || now.Before(m.SecretValidUntil)
+}
 
-	return c.Next()
+// JWKS - открытые ключи проверки для других сервисов
+func (m *Middleware) JWKS(c *fiber.Ctx) error {
+	set := JWKS{Keys: []JWK{}}
+	if m.Keys != nil {
+		set = m.Keys.JWKS()
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(http.StatusOK).JSON(set)
+}
+
+// tokenID - идентификатор токена: claim jti, а у токенов, выпущенных до его появления, заголовок kid
+func tokenID(token *jwt.Token, claims *tokenClaims) (uuid.UUID, error) {
+	if claims.ID != "" {
+		return uuid.Parse(claims.ID)
+	}
+	if !claims.legacy() {
+		return uuid.Nil, errMissingClaims
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	return uuid.Parse(kid)
 }
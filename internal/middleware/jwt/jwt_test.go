@@ -0,0 +1,237 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+const testSecret = "test-secret"
+
+var testValidation = Validation{Issuer: "avito-pvz", Audience: "avito-pvz-api", AllowLegacy: true}
+
+type stubSessions struct {
+	revoked map[uuid.UUID]bool
+	// roles - текущие роли пользователей; пользователь без записи считается активным с ролью из токена
+	roles map[uuid.UUID]models.UserRole
+}
+
+func (s *stubSessions) IssueRefreshToken(_ context.Context, _ uuid.UUID, _ models.UserRole) (string, error) {
+	return "refresh-token", nil
+}
+
+func (s *stubSessions) IsRevoked(_ context.Context, jti, userID uuid.UUID, role models.UserRole) (bool, error) {
+	if current, ok := s.roles[userID]; ok && current != role {
+		return true, nil
+	}
+	return s.revoked[jti], nil
+}
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	app      *fiber.App
+	sessions *stubSessions
+	mw       *Middleware
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	s.sessions = &stubSessions{revoked: map[uuid.UUID]bool{}, roles: map[uuid.UUID]models.UserRole{}}
+	m := NewMiddleware(testSecret, nil, time.Minute, testValidation, s.sessions)
+	s.mw = m
+
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.app.Post("/login", func(c *fiber.Ctx) error {
+		c.Locals("UserID", uuid.New())
+		c.Locals("Role", models.RoleEmployee)
+		return c.Next()
+	}, m.SignedToken)
+	s.app.Get("/protected", m.CompareToken, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"jti": c.Locals("TokenID")})
+	})
+}
+
+func (s *MiddlewareTestSuite) login() (string, string) {
+	resp, err := s.app.Test(httptest.NewRequest("POST", "/login", nil))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return body["Token"].(string), body["RefreshToken"].(string)
+}
+
+func (s *MiddlewareTestSuite) get(token string) *http.Response {
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set(models.AuthorizationToken, "Bearer "+token)
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *MiddlewareTestSuite) TestSignedTokenIssuesRefreshToken() {
+	token, refresh := s.login()
+	s.NotEmpty(token)
+	s.Equal("refresh-token", refresh)
+	s.Equal(http.StatusOK, s.get(token).StatusCode)
+}
+
+func (s *MiddlewareTestSuite) TestRevokedTokenRejected() {
+	token, _ := s.login()
+
+	resp := s.get(token)
+	var body map[string]string
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.sessions.revoked[uuid.MustParse(body["jti"])] = true
+
+	resp = s.get(token)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+
+	var errResp models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&errResp))
+	s.Equal(models.ErrTokenRevoked.Code, errResp.Code)
+}
+
+func (s *MiddlewareTestSuite) TestTokenRejectedAfterRoleChange() {
+	claims := s.validClaims()
+	token := s.sign(claims)
+	s.Equal(http.StatusOK, s.get(token).StatusCode)
+
+	s.sessions.roles[uuid.MustParse(claims.Subject)] = models.RoleModerator
+
+	resp := s.get(token)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(models.ErrTokenRevoked.Code, s.errorCode(resp))
+}
+
+func (s *MiddlewareTestSuite) TestLegacyTokenUsesKidAsID() {
+	kid := uuid.New()
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"ExpiresAt": jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		"IssuedAt":  jwt.NewNumericDate(time.Now()),
+		"UserID":    uuid.New(),
+		"Role":      models.RoleModerator,
+	})
+	legacy.Header["kid"] = kid.String()
+	token, err := legacy.SignedString([]byte(testSecret))
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusOK, s.get(token).StatusCode)
+
+	s.sessions.revoked[kid] = true
+	s.Equal(http.StatusUnauthorized, s.get(token).StatusCode)
+}
+
+func (s *MiddlewareTestSuite) sign(claims jwt.Claims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	s.Require().NoError(err)
+	return token
+}
+
+func (s *MiddlewareTestSuite) errorCode(resp *http.Response) string {
+	var errResp models.ErrorResp
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&errResp))
+	return errResp.Code
+}
+
+func (s *MiddlewareTestSuite) validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		Role: models.RoleEmployee,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   uuid.NewString(),
+			Issuer:    testValidation.Issuer,
+			Audience:  jwt.ClaimStrings{testValidation.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+	}
+}
+
+func (s *MiddlewareTestSuite) TestSignedTokenUsesRegisteredClaims() {
+	token, _ := s.login()
+
+	var claims Claims
+	_, _, err := new(jwt.Parser).ParseUnverified(token, &claims)
+	s.Require().NoError(err)
+
+	s.Equal(models.RoleEmployee, claims.Role)
+	s.Equal(testValidation.Issuer, claims.Issuer)
+	s.Equal(jwt.ClaimStrings{testValidation.Audience}, claims.Audience)
+	s.NotEmpty(claims.Subject)
+	s.NotEmpty(claims.ID)
+	s.NotNil(claims.ExpiresAt)
+	s.NotNil(claims.IssuedAt)
+	s.NotNil(claims.NotBefore)
+}
+
+func (s *MiddlewareTestSuite) TestExpiredTokenUnauthorized() {
+	claims := s.validClaims()
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	resp := s.get(s.sign(claims))
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(models.ErrTokenExpired.Code, s.errorCode(resp))
+}
+
+func (s *MiddlewareTestSuite) TestIssuerAndAudienceValidated() {
+	s.Equal(http.StatusOK, s.get(s.sign(s.validClaims())).StatusCode)
+
+	claims := s.validClaims()
+	claims.Issuer = "someone-else"
+	resp := s.get(s.sign(claims))
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(models.ErrInvalidToken.Code, s.errorCode(resp))
+
+	claims = s.validClaims()
+	claims.Audience = jwt.ClaimStrings{"other-api"}
+	s.Equal(http.StatusUnauthorized, s.get(s.sign(claims)).StatusCode)
+}
+
+func (s *MiddlewareTestSuite) TestMalformedTokenUnauthorized() {
+	resp := s.get("not-a-jwt")
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(models.ErrInvalidToken.Code, s.errorCode(resp))
+}
+
+func (s *MiddlewareTestSuite) TestLegacyTokenRejectedWhenDisabled() {
+	legacy := s.sign(jwt.MapClaims{
+		"ExpiresAt": jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		"UserID":    uuid.New(),
+		"Role":      models.RoleModerator,
+		"jti":       uuid.NewString(),
+	})
+	s.Equal(http.StatusOK, s.get(legacy).StatusCode)
+
+	s.mw.Validation.AllowLegacy = false
+	s.Equal(http.StatusUnauthorized, s.get(legacy).StatusCode)
+}
+
+func (s *MiddlewareTestSuite) TestExpiredLegacyTokenUnauthorized() {
+	legacy := s.sign(jwt.MapClaims{
+		"ExpiresAt": jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		"UserID":    uuid.New(),
+		"Role":      models.RoleModerator,
+		"jti":       uuid.NewString(),
+	})
+
+	resp := s.get(legacy)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(models.ErrTokenExpired.Code, s.errorCode(resp))
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
@@ -0,0 +1,181 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Key - ключ подписи RS256 или EdDSA. У ключа, оставленного только для проверки, Private == nil
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeyFile - расположение PEM-файлов ключа; достаточно одного из путей
+type KeyFile struct {
+	ID             string
+	PrivateKeyPath string
+	PublicKeyPath  string
+}
+
+// KeySet - набор действующих ключей, выбираемых по kid. Подписывается всё ключом signingID,
+// остальные ключи принимаются при проверке, пока не истекут выпущенные ими токены.
+type KeySet struct {
+	keys      map[string]Key
+	signingID string
+}
+
+func NewKeySet(signingID string, keys ...Key) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]Key, len(keys)), signingID: signingID}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("jwt key id is empty")
+		}
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate jwt key id %q", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	signing, ok := set.keys[signingID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not in the key set", signingID)
+	}
+	if signing.Private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingID)
+	}
+
+	return set, nil
+}
+
+// LoadKeySet читает ключи из PEM-файлов
+func LoadKeySet(signingID string, files []KeyFile) (*KeySet, error) {
+	keys := make([]Key, 0, len(files))
+	for _, file := range files {
+		path := file.PrivateKeyPath
+		if path == "" {
+			path = file.PublicKeyPath
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read jwt key %q: %w", file.ID, err)
+		}
+
+		key, err := ParsePEMKey(file.ID, data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(signingID, keys...)
+}
+
+// ParsePEMKey разбирает закрытый (PKCS#8, PKCS#1) или открытый (PKIX) ключ RSA либо Ed25519
+func ParsePEMKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("jwt key %q: no PEM block found", id)
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("jwt key %q: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("jwt key %q: %w", id, err)
+	}
+
+	key := Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.Private, key.Public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	default:
+		return Key{}, fmt.Errorf("jwt key %q: unsupported key type %T", id, parsed)
+	}
+
+	return key, nil
+}
+
+func (s *KeySet) SigningKey() Key {
+	return s.keys[s.signingID]
+}
+
+func (s *KeySet) Lookup(kid string) (Key, bool) {
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// JWK - открытый ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS - открытые части всех ключей набора, упорядоченные по kid
+func (s *KeySet) JWKS() JWKS {
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, id := range ids {
+		key := s.keys[id]
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
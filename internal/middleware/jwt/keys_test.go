@@ -0,0 +1,200 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+type KeySetTestSuite struct {
+	suite.Suite
+	rsaKey Key
+	edKey  Key
+}
+
+func (s *KeySetTestSuite) SetupSuite() {
+	rsaPriv, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.rsaKey = Key{ID: "rsa-1", Method: jwt.SigningMethodRS256, Private: rsaPriv, Public: &rsaPriv.PublicKey}
+
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	s.edKey = Key{ID: "ed-1", Method: jwt.SigningMethodEdDSA, Private: edPriv, Public: edPub}
+}
+
+func (s *KeySetTestSuite) app(m *Middleware) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	app.Post("/login", func(c *fiber.Ctx) error {
+		c.Locals("UserID", uuid.New())
+		c.Locals("Role", models.RoleEmployee)
+		return c.Next()
+	}, m.SignedToken)
+	app.Get("/protected", m.CompareToken, func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Get("/.well-known/jwks.json", m.JWKS)
+	return app
+}
+
+func (s *KeySetTestSuite) login(app *fiber.App) string {
+	resp, err := app.Test(httptest.NewRequest("POST", "/login", nil))
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+	return body["Token"].(string)
+}
+
+func (s *KeySetTestSuite) status(app *fiber.App, token string) int {
+	req := httptest.NewRequest("GET", "/protected", nil)
+	req.Header.Set(models.AuthorizationToken, "Bearer "+token)
+
+	resp, err := app.Test(req)
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+func (s *KeySetTestSuite) TestSignsWithActiveKey() {
+	for _, key := range []Key{s.rsaKey, s.edKey} {
+		keys, err := NewKeySet(key.ID, key)
+		s.Require().NoError(err)
+		app := s.app(NewMiddleware("", keys, time.Minute, Validation{}, nil))
+
+		token := s.login(app)
+		parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+		s.Require().NoError(err)
+		s.Equal(key.ID, parsed.Header["kid"])
+		s.Equal(key.Method.Alg(), parsed.Header["alg"])
+
+		s.Equal(http.StatusOK, s.status(app, token))
+	}
+}
+
+func (s *KeySetTestSuite) TestRotationKeepsOldKeyForVerification() {
+	oldKeys, err := NewKeySet(s.rsaKey.ID, s.rsaKey)
+	s.Require().NoError(err)
+	oldToken := s.login(s.app(NewMiddleware("", oldKeys, time.Minute, Validation{}, nil)))
+
+	verifyOnly := s.rsaKey
+	verifyOnly.Private = nil
+	keys, err := NewKeySet(s.edKey.ID, s.edKey, verifyOnly)
+	s.Require().NoError(err)
+	app := s.app(NewMiddleware("", keys, time.Minute, Validation{}, nil))
+
+	s.Equal(http.StatusOK, s.status(app, oldToken))
+	s.Equal(http.StatusOK, s.status(app, s.login(app)))
+
+	withoutOld, err := NewKeySet(s.edKey.ID, s.edKey)
+	s.Require().NoError(err)
+	s.NotEqual(http.StatusOK, s.status(s.app(NewMiddleware("", withoutOld, time.Minute, Validation{}, nil)), oldToken))
+}
+
+func (s *KeySetTestSuite) TestSecretAcceptedOnlyUntilSecretValidUntil() {
+	hsToken := s.login(s.app(NewMiddleware(testSecret, nil, time.Minute, Validation{}, nil)))
+
+	keys, err := NewKeySet(s.edKey.ID, s.edKey)
+	s.Require().NoError(err)
+
+	withoutDate := NewMiddleware(testSecret, keys, time.Minute, Validation{}, nil)
+	s.NotEqual(http.StatusOK, s.status(s.app(withoutDate), hsToken), "без secret_valid_until HS256 не принимается")
+
+	transition := NewMiddleware(testSecret, keys, time.Minute, Validation{}, nil)
+	transition.SecretValidUntil = time.Now().Add(time.Hour)
+	s.Equal(http.StatusOK, s.status(s.app(transition), hsToken))
+
+	expired := NewMiddleware(testSecret, keys, time.Minute, Validation{}, nil)
+	expired.SecretValidUntil = time.Now().Add(-time.Second)
+	s.NotEqual(http.StatusOK, s.status(s.app(expired), hsToken))
+}
+
+func (s *KeySetTestSuite) TestRejectsAlgorithmSubstitution() {
+	keys, err := NewKeySet(s.edKey.ID, s.edKey)
+	s.Require().NoError(err)
+	app := s.app(NewMiddleware("", keys, time.Minute, Validation{}, nil))
+
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"ExpiresAt": jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		"UserID":    uuid.New(),
+		"Role":      models.RoleModerator,
+		"jti":       uuid.NewString(),
+	})
+	hmac.Header["kid"] = s.edKey.ID
+	token, err := hmac.SignedString([]byte(s.edKey.Public.(ed25519.PublicKey)))
+	s.Require().NoError(err)
+
+	s.NotEqual(http.StatusOK, s.status(app, token))
+}
+
+func (s *KeySetTestSuite) TestNewKeySetRequiresPrivateSigningKey() {
+	verifyOnly := s.edKey
+	verifyOnly.Private = nil
+
+	_, err := NewKeySet(verifyOnly.ID, verifyOnly)
+	s.Error(err)
+
+	_, err = NewKeySet("missing", s.edKey)
+	s.Error(err)
+
+	_, err = NewKeySet(s.edKey.ID, s.edKey, s.edKey)
+	s.Error(err)
+}
+
+func (s *KeySetTestSuite) TestParsePEMKey() {
+	der, err := x509.MarshalPKCS8PrivateKey(s.edKey.Private)
+	s.Require().NoError(err)
+	key, err := ParsePEMKey("ed", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	s.Require().NoError(err)
+	s.Equal(jwt.SigningMethodEdDSA, key.Method)
+	s.NotNil(key.Private)
+
+	der, err = x509.MarshalPKIXPublicKey(s.rsaKey.Public)
+	s.Require().NoError(err)
+	key, err = ParsePEMKey("rsa", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	s.Require().NoError(err)
+	s.Equal(jwt.SigningMethodRS256, key.Method)
+	s.Nil(key.Private)
+
+	_, err = ParsePEMKey("bad", []byte("not a pem"))
+	s.Error(err)
+}
+
+func (s *KeySetTestSuite) TestJWKS() {
+	keys, err := NewKeySet(s.edKey.ID, s.edKey, s.rsaKey)
+	s.Require().NoError(err)
+	app := s.app(NewMiddleware("", keys, time.Minute, Validation{}, nil))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+	s.Require().NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var set JWKS
+	s.Require().NoError(json.NewDecoder(resp.Body).Decode(&set))
+	s.Require().Len(set.Keys, 2)
+
+	s.Equal(JWK{Kty: "OKP", Kid: "ed-1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: set.Keys[0].X}, set.Keys[0])
+	s.NotEmpty(set.Keys[0].X)
+	s.Equal("RSA", set.Keys[1].Kty)
+	s.Equal("RS256", set.Keys[1].Alg)
+	s.Equal("AQAB", set.Keys[1].E)
+	s.NotEmpty(set.Keys[1].N)
+}
+
+func TestKeySetTestSuite(t *testing.T) {
+	suite.Run(t, new(KeySetTestSuite))
+}
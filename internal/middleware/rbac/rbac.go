@@ -0,0 +1,117 @@
+// Package rbac - проверка прав доступа по роли пользователя.
+// Таблица роль → права задаётся в конфиге, маршруты объявляют только нужное им право,
+// поэтому новая роль добавляется без изменения кода.
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+
+	"AvitoPVZ/internal/models"
+)
+
+// Права, на которые ссылаются маршруты
+const (
+	PVZRead       = "pvz:read"
+	PVZCreate     = "pvz:create"
+	PVZUpdate     = "pvz:update"
+	PVZDeactivate = "pvz:deactivate"
+
+	ReceptionRead   = "reception:read"
+	ReceptionCreate = "reception:create"
+	ReceptionClose  = "reception:close"
+
+	ProductRead   = "product:read"
+	ProductCreate = "product:create"
+	ProductDelete = "product:delete"
+
+	ReferenceRead  = "reference:read"
+	ReferenceWrite = "reference:write"
+
+	AssignmentManage = "assignment:manage"
+
+	UserManage = "user:manage"
+)
+
+// permissions - все права, которые проверяют маршруты. Опечатка в конфиге иначе
+// молча оставила бы роль без права
+var permissions = map[string]struct{}{
+	PVZRead: {}, PVZCreate: {}, PVZUpdate: {}, PVZDeactivate: {},
+	ReceptionRead: {}, ReceptionCreate: {}, ReceptionClose: {},
+	ProductRead: {}, ProductCreate: {}, ProductDelete: {},
+	ReferenceRead: {}, ReferenceWrite: {},
+	AssignmentManage: {},
+	UserManage:       {},
+}
+
+type Policy struct {
+	roles map[models.UserRole]map[string]struct{}
+}
+
+// NewPolicy строит политику из таблицы роль → список прав. Роли из таблицы - единственные
+// допустимые роли пользователей, а неизвестное право считается ошибкой конфигурации
+func NewPolicy(table map[string][]string) (*Policy, error) {
+	if len(table) == 0 {
+		return nil, errors.New("rbac: role permissions are not configured")
+	}
+
+	p := &Policy{roles: make(map[models.UserRole]map[string]struct{}, len(table))}
+	for role, perms := range table {
+		if role == "" {
+			return nil, errors.New("rbac: empty role name")
+		}
+
+		set := make(map[string]struct{}, len(perms))
+		for _, perm := range perms {
+			if perm == "" {
+				return nil, fmt.Errorf("rbac: empty permission for role %q", role)
+			}
+			if _, ok := permissions[perm]; !ok {
+				return nil, fmt.Errorf("rbac: unknown permission %q for role %q", perm, role)
+			}
+			set[perm] = struct{}{}
+		}
+		p.roles[models.UserRole(role)] = set
+	}
+
+	return p, nil
+}
+
+// HasRole - задана ли роль в политике
+func (p *Policy) HasRole(role models.UserRole) bool {
+	_, ok := p.roles[role]
+	return ok
+}
+
+// Roles - все роли политики по алфавиту
+func (p *Policy) Roles() []models.UserRole {
+	roles := make([]models.UserRole, 0, len(p.roles))
+	for role := range p.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+
+	return roles
+}
+
+// Allowed - есть ли у роли право perm
+func (p *Policy) Allowed(role models.UserRole, perm string) bool {
+	_, ok := p.roles[role][perm]
+	return ok
+}
+
+// Require пропускает запрос дальше, только если у роли из токена есть право perm.
+// Должен стоять после jwt.CompareToken, который кладёт роль в Locals.
+func (p *Policy) Require(perm string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, ok := c.Locals("Role").(models.UserRole)
+		if !ok || !p.Allowed(role, perm) {
+			return models.ErrForbidden
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,105 @@
+package rbac
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+	app *fiber.App
+}
+
+func (s *PolicyTestSuite) SetupTest() {
+	policy, err := NewPolicy(map[string][]string{
+		"moderator": {PVZCreate, PVZRead},
+		"employee":  {PVZRead, ReceptionCreate},
+		"auditor":   {PVZRead},
+	})
+	s.Require().NoError(err)
+
+	s.app = fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	s.app.Use(func(c *fiber.Ctx) error {
+		if role := c.Get("X-Role"); role != "" {
+			c.Locals("Role", models.UserRole(role))
+		}
+		return c.Next()
+	})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) }
+	s.app.Post("/pvz", policy.Require(PVZCreate), ok)
+	s.app.Get("/pvz", policy.Require(PVZRead), ok)
+	s.app.Post("/receptions", policy.Require(ReceptionCreate), ok)
+}
+
+func (s *PolicyTestSuite) do(method, path, role string) *http.Response {
+	req := httptest.NewRequest(method, path, nil)
+	if role != "" {
+		req.Header.Set("X-Role", role)
+	}
+
+	resp, err := s.app.Test(req)
+	s.Require().NoError(err)
+	return resp
+}
+
+func (s *PolicyTestSuite) TestAllowed() {
+	s.Equal(http.StatusOK, s.do("POST", "/pvz", "moderator").StatusCode)
+	s.Equal(http.StatusOK, s.do("POST", "/receptions", "employee").StatusCode)
+	s.Equal(http.StatusOK, s.do("GET", "/pvz", "auditor").StatusCode)
+}
+
+func (s *PolicyTestSuite) TestForbidden() {
+	for _, tc := range []struct{ method, path, role string }{
+		{"POST", "/pvz", "employee"},
+		{"POST", "/receptions", "moderator"},
+		{"POST", "/receptions", "auditor"},
+		{"GET", "/pvz", "unknown"},
+		{"GET", "/pvz", ""},
+	} {
+		resp := s.do(tc.method, tc.path, tc.role)
+		s.Equal(http.StatusForbidden, resp.StatusCode, tc)
+
+		var body models.ErrorResp
+		s.Require().NoError(json.NewDecoder(resp.Body).Decode(&body))
+		s.Equal(models.CodeForbidden, body.Code)
+		s.Equal("access denied", body.Message)
+	}
+}
+
+func (s *PolicyTestSuite) TestNewPolicyRejectsEmptyTable() {
+	_, err := NewPolicy(nil)
+	s.Error(err)
+
+	_, err = NewPolicy(map[string][]string{"employee": {""}})
+	s.Error(err)
+}
+
+func (s *PolicyTestSuite) TestNewPolicyRejectsUnknownPermission() {
+	_, err := NewPolicy(map[string][]string{"employee": {PVZRead, "pvz:raed"}})
+	s.ErrorContains(err, `unknown permission "pvz:raed"`)
+}
+
+func (s *PolicyTestSuite) TestRoles() {
+	policy, err := NewPolicy(map[string][]string{
+		"moderator": {PVZRead},
+		"auditor":   {PVZRead},
+		"employee":  {},
+	})
+	s.Require().NoError(err)
+
+	s.Equal([]models.UserRole{"auditor", "employee", "moderator"}, policy.Roles())
+	s.True(policy.HasRole("auditor"))
+	s.False(policy.HasRole("admin"))
+}
+
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
@@ -0,0 +1,46 @@
+// Package route - шаблон маршрута, обработавшего запрос, для меток метрик и имён span'ов.
+package route
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Unmatched - шаблон для запросов, не попавших ни в один маршрут: без него
+// произвольные пути сканеров раздували бы число временных рядов и имён span'ов
+const Unmatched = "unmatched"
+
+// handled - маршруты приложений без middleware из app.Use, ключ - метод и путь.
+// Считаются при первом запросе, поэтому все маршруты регистрируются до запуска сервера
+var handled sync.Map // *fiber.App -> map[string]struct{}
+
+// Template возвращает шаблон маршрута (/pvz/:pvzId), а не фактический путь.
+// Вызывается после c.Next(): если запрос не дошёл ни до одного обработчика,
+// c.Route() указывает на последний middleware из app.Use, и результат - Unmatched
+func Template(c *fiber.Ctx) string {
+	r := c.Route()
+	if r == nil {
+		return Unmatched
+	}
+
+	if _, ok := routes(c.App())[r.Method+" "+r.Path]; !ok {
+		return Unmatched
+	}
+
+	return r.Path
+}
+
+func routes(app *fiber.App) map[string]struct{} {
+	if set, ok := handled.Load(app); ok {
+		return set.(map[string]struct{})
+	}
+
+	set := make(map[string]struct{})
+	for _, r := range app.GetRoutes(true) {
+		set[r.Method+" "+r.Path] = struct{}{}
+	}
+
+	actual, _ := handled.LoadOrStore(app, set)
+	return actual.(map[string]struct{})
+}
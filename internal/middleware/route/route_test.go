@@ -0,0 +1,38 @@
+package route
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestTemplate(t *testing.T) {
+	var got string
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		got = Template(c)
+		return err
+	})
+	app.Use(func(c *fiber.Ctx) error { return c.Next() })
+	app.Get("/pvz/:pvzId", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	cases := []struct {
+		method, path, want string
+	}{
+		{"GET", "/pvz/42", "/pvz/:pvzId"},
+		{"HEAD", "/pvz/42", "/pvz/:pvzId"},
+		{"POST", "/pvz/42", Unmatched},
+		{"GET", "/wp-admin/setup.php", Unmatched},
+	}
+
+	for _, tc := range cases {
+		if _, err := app.Test(httptest.NewRequest(tc.method, tc.path, nil)); err != nil {
+			t.Fatalf("%s %s: %v", tc.method, tc.path, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s %s: ожидался шаблон %q, получен %q", tc.method, tc.path, tc.want, got)
+		}
+	}
+}
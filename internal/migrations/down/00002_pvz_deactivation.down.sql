@@ -0,0 +1,2 @@
+ALTER TABLE pickup_point
+    DROP COLUMN deactivated_at;
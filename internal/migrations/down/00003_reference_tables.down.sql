@@ -0,0 +1,20 @@
+DROP TRIGGER product_types_changed ON product_types;
+DROP TRIGGER cities_changed ON cities;
+DROP FUNCTION notify_reference_changed();
+
+ALTER TABLE goods
+    DROP CONSTRAINT fk_product_type;
+
+ALTER TABLE goods
+    ADD CONSTRAINT goods_type_check
+        CHECK (product_type IN ('электроника', 'одежда', 'обувь'));
+
+ALTER TABLE pickup_point
+    DROP CONSTRAINT fk_city;
+
+ALTER TABLE pickup_point
+    ADD CONSTRAINT city_check
+        CHECK (city IN ('Москва', 'Санкт-Петербург', 'Казань'));
+
+DROP TABLE product_types;
+DROP TABLE cities;
@@ -0,0 +1,8 @@
+DROP INDEX goods_barcode_idx;
+
+ALTER TABLE goods
+    DROP COLUMN barcode,
+    DROP COLUMN weight_grams,
+    DROP COLUMN length_mm,
+    DROP COLUMN width_mm,
+    DROP COLUMN height_mm;
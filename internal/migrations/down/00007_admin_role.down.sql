@@ -0,0 +1,11 @@
+DELETE FROM "users" WHERE role = 'admin';
+
+ALTER TABLE "users"
+    DROP COLUMN disabled_at;
+
+ALTER TABLE "users"
+    DROP CONSTRAINT users_role_check;
+
+ALTER TABLE "users"
+    ADD CONSTRAINT city_check
+        CHECK (role IN ('employee', 'moderator'));
@@ -0,0 +1,2 @@
+-- Закрепления из миграции не отличить от выданных вручную, поэтому откат их не удаляет.
+SELECT 1;
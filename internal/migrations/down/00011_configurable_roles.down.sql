@@ -0,0 +1,13 @@
+DELETE FROM refresh_tokens WHERE role NOT IN ('employee', 'moderator', 'admin');
+
+DELETE FROM "users" WHERE role NOT IN ('employee', 'moderator', 'admin');
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN role TYPE VARCHAR(10);
+
+ALTER TABLE "users"
+    ALTER COLUMN role TYPE VARCHAR(10);
+
+ALTER TABLE "users"
+    ADD CONSTRAINT users_role_check
+        CHECK (role IN ('employee', 'moderator', 'admin'));
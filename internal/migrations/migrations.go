@@ -0,0 +1,86 @@
+// Package migrations - SQL-миграции схемы, встроенные в бинарник.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+)
+
+// Dir - каталог миграций наката внутри FS
+const Dir = "up"
+
+//go:embed up/*.sql
+var FS embed.FS
+
+// Source - источник миграций для golang-migrate
+func Source() (source.Driver, error) {
+	d, err := iofs.New(FS, Dir)
+	if err != nil {
+		return nil, fmt.Errorf("open embedded migrations: %w", err)
+	}
+
+	return d, nil
+}
+
+// Latest - версия последней встроенной миграции
+func Latest() (uint, error) {
+	d, err := Source()
+	if err != nil {
+		return 0, err
+	}
+	defer d.Close()
+
+	version, err := d.First()
+	if err != nil {
+		return 0, fmt.Errorf("read first migration: %w", err)
+	}
+	for {
+		next, err := d.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		} else if err != nil {
+			return 0, fmt.Errorf("read migration after %d: %w", version, err)
+		}
+		version = next
+	}
+}
+
+type DB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Verify сверяет версию схемы, записанную golang-migrate, с последней встроенной миграцией.
+// Ошибка означает, что миграции не применены, применены частично (dirty) или база новее бинарника
+func Verify(ctx context.Context, db DB) error {
+	latest, err := Latest()
+	if err != nil {
+		return err
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err = db.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("no migrations applied, expected version %d", latest)
+	} else if err != nil {
+		return fmt.Errorf("query schema version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+	if version != int64(latest) {
+		return fmt.Errorf("schema version %d, expected %d", version, latest)
+	}
+
+	return nil
+}
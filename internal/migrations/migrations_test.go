@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type fakeRow struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	*(dest[0].(*int64)) = r.version
+	*(dest[1].(*bool)) = r.dirty
+	return nil
+}
+
+type fakeDB struct {
+	row fakeRow
+}
+
+func (db fakeDB) QueryRow(_ context.Context, _ string, _ ...any) pgx.Row {
+	return db.row
+}
+
+// TestLatest проверяет, что последняя версия совпадает с числом последовательных миграций.
+func TestLatest(t *testing.T) {
+	entries, err := FS.ReadDir(Dir)
+	if err != nil {
+		t.Fatalf("read embedded migrations: %v", err)
+	}
+
+	latest, err := Latest()
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if latest != uint(len(entries)) {
+		t.Fatalf("ожидалась версия %d, получено %d", len(entries), latest)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	latest, err := Latest()
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		row     fakeRow
+		wantErr string
+	}{
+		{"актуальная схема", fakeRow{version: int64(latest)}, ""},
+		{"отстающая схема", fakeRow{version: int64(latest) - 1}, "expected"},
+		{"dirty", fakeRow{version: int64(latest), dirty: true}, "dirty"},
+		{"нет миграций", fakeRow{err: pgx.ErrNoRows}, "no migrations applied"},
+		{"ошибка базы", fakeRow{err: errors.New("connection refused")}, "connection refused"},
+	}
+
+	for _, tc := range cases {
+		err := Verify(context.Background(), fakeDB{row: tc.row})
+		if tc.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: неожиданная ошибка: %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: ожидалась ошибка с %q, получено %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE pickup_point
+    ADD COLUMN deactivated_at TIMESTAMP NULL;
@@ -0,0 +1,53 @@
+CREATE TABLE cities
+(
+    name VARCHAR(255) PRIMARY KEY
+);
+
+INSERT INTO cities (name)
+VALUES ('Москва'),
+       ('Санкт-Петербург'),
+       ('Казань');
+
+CREATE TABLE product_types
+(
+    name VARCHAR(50) PRIMARY KEY
+);
+
+INSERT INTO product_types (name)
+VALUES ('электроника'),
+       ('одежда'),
+       ('обувь');
+
+ALTER TABLE pickup_point
+    DROP CONSTRAINT city_check;
+
+ALTER TABLE pickup_point
+    ADD CONSTRAINT fk_city
+        FOREIGN KEY (city) REFERENCES cities (name);
+
+ALTER TABLE goods
+    DROP CONSTRAINT goods_type_check;
+
+ALTER TABLE goods
+    ADD CONSTRAINT fk_product_type
+        FOREIGN KEY (product_type) REFERENCES product_types (name);
+
+CREATE FUNCTION notify_reference_changed() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('reference_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cities_changed
+    AFTER INSERT OR UPDATE OR DELETE
+    ON cities
+    FOR EACH STATEMENT
+EXECUTE FUNCTION notify_reference_changed();
+
+CREATE TRIGGER product_types_changed
+    AFTER INSERT OR UPDATE OR DELETE
+    ON product_types
+    FOR EACH STATEMENT
+EXECUTE FUNCTION notify_reference_changed();
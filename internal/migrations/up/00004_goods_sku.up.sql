@@ -0,0 +1,8 @@
+ALTER TABLE goods
+    ADD COLUMN barcode      VARCHAR(64) NULL,
+    ADD COLUMN weight_grams INTEGER     NULL CHECK (weight_grams > 0),
+    ADD COLUMN length_mm    INTEGER     NULL CHECK (length_mm > 0),
+    ADD COLUMN width_mm     INTEGER     NULL CHECK (width_mm > 0),
+    ADD COLUMN height_mm    INTEGER     NULL CHECK (height_mm > 0);
+
+CREATE INDEX goods_barcode_idx ON goods (barcode);
@@ -0,0 +1,20 @@
+CREATE TABLE refresh_tokens
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID        NOT NULL,
+    role       VARCHAR(10) NOT NULL,
+    token_hash CHAR(64)    NOT NULL UNIQUE,
+    created_at TIMESTAMP   NOT NULL,
+    expires_at TIMESTAMP   NOT NULL,
+    revoked_at TIMESTAMP   NULL
+);
+
+CREATE INDEX refresh_tokens_user_idx ON refresh_tokens (user_id);
+
+CREATE TABLE revoked_tokens
+(
+    jti        UUID PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX revoked_tokens_expires_idx ON revoked_tokens (expires_at);
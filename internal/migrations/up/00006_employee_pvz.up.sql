@@ -0,0 +1,13 @@
+CREATE TABLE employee_pvz
+(
+    user_id         UUID      NOT NULL,
+    pickup_point_id UUID      NOT NULL,
+    assigned_at     TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, pickup_point_id),
+    CONSTRAINT fk_employee_pvz_user
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_employee_pvz_pickup_point
+        FOREIGN KEY (pickup_point_id) REFERENCES pickup_point (id) ON DELETE CASCADE
+);
+
+CREATE INDEX employee_pvz_pickup_point_idx ON employee_pvz (pickup_point_id);
@@ -0,0 +1,9 @@
+ALTER TABLE "users"
+    DROP CONSTRAINT city_check;
+
+ALTER TABLE "users"
+    ADD CONSTRAINT users_role_check
+        CHECK (role IN ('employee', 'moderator', 'admin'));
+
+ALTER TABLE "users"
+    ADD COLUMN disabled_at TIMESTAMP NULL;
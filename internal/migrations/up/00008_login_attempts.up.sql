@@ -0,0 +1,9 @@
+CREATE TABLE login_attempts
+(
+    key             VARCHAR(320) PRIMARY KEY,
+    failures        INTEGER   NOT NULL,
+    last_failure_at TIMESTAMP NOT NULL,
+    locked_until    TIMESTAMP NULL
+);
+
+CREATE INDEX login_attempts_last_failure_idx ON login_attempts (last_failure_at);
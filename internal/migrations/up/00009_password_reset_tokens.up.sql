@@ -0,0 +1,12 @@
+CREATE TABLE password_reset_tokens
+(
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id    UUID      NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at    TIMESTAMP NULL,
+    CONSTRAINT fk_password_reset_tokens_user
+        FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX password_reset_tokens_user_idx ON password_reset_tokens (user_id);
@@ -0,0 +1,11 @@
+-- До закрепления за ПВЗ любой сотрудник мог менять данные любого ПВЗ. Сотрудникам, которых
+-- ещё ни разу не закрепляли, сохраняется прежний доступ: они закрепляются за всеми действующими
+-- ПВЗ. Лишние закрепления снимаются через DELETE /employees/{userId}/pvz/{pvzId}.
+INSERT INTO employee_pvz (user_id, pickup_point_id, assigned_at)
+SELECT u.id, p.id, now() AT TIME ZONE 'UTC'
+FROM users u
+         CROSS JOIN pickup_point p
+WHERE u.role = 'employee'
+  AND p.deactivated_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM employee_pvz e WHERE e.user_id = u.id)
+ON CONFLICT (user_id, pickup_point_id) DO NOTHING;
@@ -0,0 +1,9 @@
+-- набор ролей задаётся в конфиге rbac, поэтому БД больше не ограничивает их значения
+ALTER TABLE "users"
+    DROP CONSTRAINT users_role_check;
+
+ALTER TABLE "users"
+    ALTER COLUMN role TYPE VARCHAR(64);
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN role TYPE VARCHAR(64);
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Assignment - закрепление сотрудника за ПВЗ. Сотрудник ведёт приёмки только в закреплённых ПВЗ
+type Assignment struct {
+	UserID     uuid.UUID `json:"userId"`
+	PvzID      uuid.UUID `json:"pvzId"`
+	AssignedAt time.Time `json:"assignedAt"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "fmt"
+
+// BatchItemError - ошибка конкретного элемента пакетной операции
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %s", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -1,14 +1,17 @@
 package models
 
 import (
-	"errors"
+	"net/http"
 	"time"
 )
 
 const (
-	AuthorizationToken = "Authorization"
-	DurationJwtToken   = time.Hour * 24
-	MinEntropyBits     = 50
+	AuthorizationToken   = "Authorization"
+	DurationJwtToken     = time.Minute * 15
+	DurationRefreshToken = time.Hour * 24 * 30
+	MinEntropyBits       = 50
+	// MaxPasswordBytes - предел bcrypt: длиннее GenerateFromPassword возвращает ErrPasswordTooLong
+	MaxPasswordBytes = 72
 )
 
 type PVZCity string
@@ -35,6 +38,41 @@ const (
 )
 
 var (
-	ErrAuthUser   = errors.New("user is not authorized")
-	ErrValidation = errors.New("validation error")
+	ErrAuthUser   = NewDomainError(http.StatusUnauthorized, CodeUnauthorized, "user is not authorized")
+	ErrForbidden  = NewDomainError(http.StatusForbidden, CodeForbidden, "access denied")
+	ErrValidation = NewDomainError(http.StatusBadRequest, CodeValidation, "validation error")
+
+	ErrUserNotFound       = NewDomainError(http.StatusNotFound, "USER_NOT_FOUND", "user not found")
+	ErrInvalidCredentials = NewDomainError(http.StatusUnauthorized, "INVALID_CREDENTIALS", "invalid email or password")
+	ErrEmailTaken         = NewDomainError(http.StatusConflict, "EMAIL_TAKEN", "user with this email already exists")
+	ErrUserDisabled       = NewDomainError(http.StatusForbidden, "USER_DISABLED", "user is disabled")
+	ErrLastAdmin          = NewDomainError(http.StatusConflict, "LAST_ADMIN", "at least one active admin must remain")
+	ErrAdminExists        = NewDomainError(http.StatusConflict, "ADMIN_EXISTS", "admin already exists")
+	ErrTooManyAttempts    = NewDomainError(http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", "too many failed login attempts, try again later")
+
+	ErrInvalidToken        = NewDomainError(http.StatusUnauthorized, "INVALID_TOKEN", "token is not valid")
+	ErrTokenExpired        = NewDomainError(http.StatusUnauthorized, "TOKEN_EXPIRED", "token is expired")
+	ErrTokenRevoked        = NewDomainError(http.StatusUnauthorized, "TOKEN_REVOKED", "token is revoked")
+	ErrInvalidRefreshToken = NewDomainError(http.StatusUnauthorized, "INVALID_REFRESH_TOKEN", "refresh token is invalid, expired or revoked")
+	ErrInvalidResetToken   = NewDomainError(http.StatusBadRequest, "INVALID_RESET_TOKEN", "password reset token is invalid, expired or already used")
+
+	ErrPVZNotFound    = NewDomainError(http.StatusNotFound, "PVZ_NOT_FOUND", "pvz not found")
+	ErrPVZDeactivated = NewDomainError(http.StatusUnprocessableEntity, "PVZ_DEACTIVATED", "pvz is deactivated")
+
+	ErrPVZNotAssigned     = NewDomainError(http.StatusForbidden, "PVZ_NOT_ASSIGNED", "pvz is not assigned to the employee")
+	ErrAssignmentNotFound = NewDomainError(http.StatusNotFound, "ASSIGNMENT_NOT_FOUND", "assignment not found")
+	ErrNotEmployee        = NewDomainError(http.StatusUnprocessableEntity, "NOT_EMPLOYEE", "user is not an employee")
+
+	ErrReceptionNotFound    = NewDomainError(http.StatusNotFound, "RECEPTION_NOT_FOUND", "reception not found")
+	ErrReceptionClosed      = NewDomainError(http.StatusConflict, "RECEPTION_CLOSED", "reception is closed")
+	ErrReceptionAlreadyOpen = NewDomainError(http.StatusConflict, "RECEPTION_ALREADY_OPEN", "active reception already exists")
+	ErrNoActiveReception    = NewDomainError(http.StatusUnprocessableEntity, "NO_ACTIVE_RECEPTION", "pvz has no reception in progress")
+
+	ErrProductNotFound    = NewDomainError(http.StatusNotFound, "PRODUCT_NOT_FOUND", "product not found")
+	ErrNoProductsToDelete = NewDomainError(http.StatusUnprocessableEntity, "NO_PRODUCTS_TO_DELETE", "reception has no products to delete")
+	ErrDuplicateBarcode   = NewDomainError(http.StatusConflict, "DUPLICATE_BARCODE", "product with this barcode is already in an open reception")
+
+	ErrReferenceNotFound = NewDomainError(http.StatusNotFound, "REFERENCE_NOT_FOUND", "reference value not found")
+	ErrReferenceExists   = NewDomainError(http.StatusConflict, "REFERENCE_EXISTS", "reference value already exists")
+	ErrReferenceInUse    = NewDomainError(http.StatusConflict, "REFERENCE_IN_USE", "reference value is in use")
 )
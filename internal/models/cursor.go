@@ -0,0 +1,34 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PVZCursor - позиция в выдаче ПВЗ для keyset-пагинации по (registration_date, id)
+type PVZCursor struct {
+	RegistrationDate time.Time `json:"d"`
+	ID               string    `json:"i"`
+}
+
+// Encode - непрозрачное представление курсора для передачи клиенту
+func (c PVZCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.URLEncoding.EncodeToString(raw)
+}
+
+func DecodePVZCursor(s string) (PVZCursor, error) {
+	raw, err := base64.URLEncoding.DecodeString(s)
+	if err != nil {
+		return PVZCursor{}, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+
+	var c PVZCursor
+	if err = json.Unmarshal(raw, &c); err != nil || c.ID == "" || c.RegistrationDate.IsZero() {
+		return PVZCursor{}, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+
+	return c, nil
+}
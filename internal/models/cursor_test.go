@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPVZCursor_EncodeDecode(t *testing.T) {
+	cursor := PVZCursor{
+		RegistrationDate: time.Date(2025, 4, 13, 10, 30, 0, 123456000, time.UTC),
+		ID:               "6f1c1c1e-7a37-4c8a-9d7a-1d3c1b0c2f11",
+	}
+
+	got, err := DecodePVZCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("DecodePVZCursor() error = %v", err)
+	}
+	if !got.RegistrationDate.Equal(cursor.RegistrationDate) || got.ID != cursor.ID {
+		t.Errorf("DecodePVZCursor() = %v, want %v", got, cursor)
+	}
+}
+
+func TestDecodePVZCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "not json", cursor: "bm90LWpzb24="},
+		{name: "empty object", cursor: "e30="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodePVZCursor(tt.cursor); !errors.Is(err, ErrValidation) {
+				t.Errorf("DecodePVZCursor() error = %v, want %v", err, ErrValidation)
+			}
+		})
+	}
+}
@@ -1,5 +1,65 @@
 package models
 
+import (
+	"errors"
+	"time"
+)
+
+// ErrorResp - тело ответа с ошибкой: стабильный машинно-читаемый код и описание для человека
 type ErrorResp struct {
-	Message string "json:message"
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+const (
+	CodeValidation   = "VALIDATION_ERROR"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeForbidden    = "ACCESS_DENIED"
+	CodeNotFound     = "NOT_FOUND"
+	CodeInternal     = "INTERNAL_ERROR"
+)
+
+// DomainError - доменная ошибка с HTTP-статусом и стабильным кодом.
+// Ошибки сравниваются по коду, поэтому errors.Is работает и для экземпляров с другим текстом.
+type DomainError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func NewDomainError(status int, code, message string) *DomainError {
+	return &DomainError{Status: status, Code: code, Message: message}
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func (e *DomainError) Is(target error) bool {
+	var t *DomainError
+	if !errors.As(target, &t) {
+		return false
+	}
+
+	return t.Code == e.Code
+}
+
+// WithMessage возвращает ошибку с тем же кодом и статусом, но с уточнённым текстом
+func (e *DomainError) WithMessage(message string) *DomainError {
+	return &DomainError{Status: e.Status, Code: e.Code, Message: message}
+}
+
+// RetryAfterError - ошибка, после которой клиенту стоит повторить запрос не раньше чем через RetryAfter.
+// Обработчик ошибок выставляет по ней заголовок Retry-After.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
 }
@@ -7,9 +7,11 @@ import (
 )
 
 type PVZ struct {
-	ID               string    `json:"id"`
-	RegistrationDate time.Time `json:"registrationDate"`
-	City             string    `json:"city"`
+	ID               string     `json:"id"`
+	RegistrationDate time.Time  `json:"registrationDate"`
+	City             string     `json:"city"`
+	Active           bool       `json:"active"`
+	DeactivatedAt    *time.Time `json:"deactivatedAt,omitempty"`
 }
 
 type Reception struct {
@@ -24,6 +26,23 @@ type Product struct {
 	DateTime    time.Time   `json:"dateTime"`
 	Type        TypeProduct `json:"type"`
 	ReceptionID uuid.UUID   `json:"receptionId"`
+	Barcode     *string     `json:"barcode,omitempty"`
+	WeightGrams *int        `json:"weightGrams,omitempty"`
+	Dimensions  *Dimensions `json:"dimensions,omitempty"`
+}
+
+// Dimensions - габариты посылки в миллиметрах
+type Dimensions struct {
+	LengthMM int `json:"lengthMm"`
+	WidthMM  int `json:"widthMm"`
+	HeightMM int `json:"heightMm"`
+}
+
+// ProductLocation - товар вместе с приёмкой и ПВЗ, в которые он был принят
+type ProductLocation struct {
+	Product   Product   `json:"product"`
+	Reception Reception `json:"reception"`
+	PVZ       PVZ       `json:"pvz"`
 }
 
 type ReceptionData struct {
@@ -31,28 +50,23 @@ type ReceptionData struct {
 	Products  []Product `json:"products"`
 }
 
-type PVZData struct {
-	PVZ        PVZ             `json:"pvz"`
-	Receptions []ReceptionData `json:"receptions"`
+// ReceptionDetails - приёмка вместе с товарами для выдачи отдельно от дерева ПВЗ
+type ReceptionDetails struct {
+	Reception    Reception `json:"reception"`
+	ProductCount int       `json:"productCount"`
+	Products     []Product `json:"products"`
 }
 
-func IsPVZCity(city PVZCity) bool {
-	allowedCities := map[PVZCity]bool{
-		CityMoscow: true,
-		CitySPB:    true,
-		CityKazan:  true,
-	}
-
-	return allowedCities[city]
+// ReceptionFilter - фильтры и пагинация списка приёмок ПВЗ
+type ReceptionFilter struct {
+	Status    *StatusReception
+	StartDate *time.Time
+	EndDate   *time.Time
+	Page      int
+	Limit     int
 }
 
-func IsTypeProduct(product string) bool {
-	data := TypeProduct(product)
-	allowedTypes := map[TypeProduct]bool{
-		TypeElectronic: true,
-		TypeClothes:    true,
-		TypeShoes:      true,
-	}
-
-	return allowedTypes[data]
+type PVZData struct {
+	PVZ        PVZ             `json:"pvz"`
+	Receptions []ReceptionData `json:"receptions"`
 }
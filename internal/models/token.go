@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken - сессия обновления access-токена. Сам токен не хранится, только его SHA-256
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Role      UserRole
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
+
+// PasswordResetToken - одноразовый токен сброса пароля. Как и для refresh-токена, хранится только SHA-256
+type PasswordResetToken struct {
+	TokenHash string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
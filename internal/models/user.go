@@ -1,21 +1,23 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserRole string
 
 const (
 	RoleEmployee  UserRole = "employee"
 	RoleModerator UserRole = "moderator"
+	RoleAdmin     UserRole = "admin"
 )
 
 type User struct {
-	ID       uuid.UUID
-	Email    string
-	Password string
-	Role     UserRole
-}
-
-func IsUserRole(role UserRole) bool {
-	return role == RoleEmployee || role == RoleModerator
+	ID         uuid.UUID
+	Email      string
+	Password   string
+	Role       UserRole
+	DisabledAt *time.Time
 }
@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Discard не доставляет токен сброса, а только отмечает в журнале, что сброс запрошен.
+// Используется вне production без reset_outbox: сам токен в журнал сервиса не попадает
+type Discard struct {
+	Logger *slog.Logger
+}
+
+func NewDiscard(logger *slog.Logger) *Discard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Discard{Logger: logger}
+}
+
+func (d *Discard) SendPasswordReset(ctx context.Context, email, _ string, expiresAt time.Time) error {
+	d.Logger.WarnContext(ctx, "password reset token discarded, configure password.reset_outbox to deliver it",
+		slog.String("email", email), slog.Time("expires_at", expiresAt))
+
+	return nil
+}
@@ -0,0 +1,35 @@
+// Package notifier - доставка пользователю одноразовых секретов (токенов сброса пароля).
+// Письма не отправляются: токены дописываются в файл-outbox, который забирает почтовый шлюз.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Log записывает каждое уведомление строкой в w. Подходит только для разработки:
+// токен сброса оказывается в открытом виде там, куда пишет w
+type Log struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLog(w io.Writer) *Log {
+	return &Log{w: w}
+}
+
+func (l *Log) SendPasswordReset(_ context.Context, email, token string, expiresAt time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := fmt.Fprintf(l.w, "%s password reset for %s: token=%s expires_at=%s\n",
+		time.Now().UTC().Format(time.RFC3339), email, token, expiresAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("write password reset notification: %w", err)
+	}
+
+	return nil
+}
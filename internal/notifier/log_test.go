@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog_SendPasswordReset(t *testing.T) {
+	var buf bytes.Buffer
+	expiresAt := time.Date(2026, 4, 1, 12, 30, 0, 0, time.UTC)
+
+	if err := NewLog(&buf).SendPasswordReset(context.Background(), "user@example.com", "secret-token", expiresAt); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	line := buf.String()
+	for _, want := range []string{"user@example.com", "token=secret-token", "expires_at=2026-04-01T12:30:00Z"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("в строке %q нет %q", line, want)
+		}
+	}
+	if !strings.HasSuffix(line, "\n") {
+		t.Error("уведомление должно заканчиваться переводом строки")
+	}
+}
+
+func TestDiscard_SendPasswordReset(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	if err := NewDiscard(logger).SendPasswordReset(context.Background(), "user@example.com", "secret-token", time.Now()); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("токен сброса попал в журнал: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "user@example.com") {
+		t.Errorf("в журнале нет адреса: %q", buf.String())
+	}
+}
@@ -0,0 +1,129 @@
+//go:generate mockgen -source=assignments.go -destination=mocks/assignments.go -package=mocks $GOPACKAGE
+package assignments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/models"
+)
+
+const foreignKeyViolation = "23503"
+
+type DB interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+type Repository struct {
+	db DB
+}
+
+func NewRepository(db DB) *Repository {
+	return &Repository{db: db}
+}
+
+// Assign закрепляет сотрудника за ПВЗ. Повторное закрепление не меняет дату исходного
+func (r *Repository) Assign(ctx context.Context, userID, pvzID uuid.UUID, now time.Time) (models.Assignment, error) {
+	var role models.UserRole
+	err := r.db.QueryRow(ctx, `SELECT role FROM users WHERE id = $1`, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Assignment{}, models.ErrUserNotFound
+	} else if err != nil {
+		return models.Assignment{}, fmt.Errorf("query user role: %w", err)
+	}
+	if role != models.RoleEmployee {
+		return models.Assignment{}, models.ErrNotEmployee
+	}
+
+	query := `
+		INSERT INTO employee_pvz (user_id, pickup_point_id, assigned_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, pickup_point_id) DO UPDATE SET assigned_at = employee_pvz.assigned_at
+		RETURNING assigned_at
+	`
+	assignment := models.Assignment{UserID: userID, PvzID: pvzID}
+	err = r.db.QueryRow(ctx, query, userID, pvzID, now).Scan(&assignment.AssignedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		if pgErr.ConstraintName == "fk_employee_pvz_user" {
+			return models.Assignment{}, models.ErrUserNotFound
+		}
+		return models.Assignment{}, models.ErrPVZNotFound
+	} else if err != nil {
+		return models.Assignment{}, fmt.Errorf("insert assignment: %w", err)
+	}
+
+	return assignment, nil
+}
+
+// AssignAllActive закрепляет сотрудника за всеми действующими ПВЗ. Используется только /dummyLogin в dev и test
+func (r *Repository) AssignAllActive(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	query := `
+		INSERT INTO employee_pvz (user_id, pickup_point_id, assigned_at)
+		SELECT $1, id, $2 FROM pickup_point WHERE deactivated_at IS NULL
+		ON CONFLICT (user_id, pickup_point_id) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, userID, now); err != nil {
+		return fmt.Errorf("assign all pvz: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Unassign(ctx context.Context, userID, pvzID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM employee_pvz WHERE user_id = $1 AND pickup_point_id = $2`, userID, pvzID)
+	if err != nil {
+		return fmt.Errorf("delete assignment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrAssignmentNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Assignment, error) {
+	query := `
+		SELECT user_id, pickup_point_id, assigned_at
+		FROM employee_pvz
+		WHERE user_id = $1
+		ORDER BY assigned_at, pickup_point_id
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := make([]models.Assignment, 0)
+	for rows.Next() {
+		var a models.Assignment
+		if err := rows.Scan(&a.UserID, &a.PvzID, &a.AssignedAt); err != nil {
+			return nil, fmt.Errorf("scan assignment: %w", err)
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+func (r *Repository) IsAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	var assigned bool
+	query := `SELECT EXISTS (SELECT 1 FROM employee_pvz WHERE user_id = $1 AND pickup_point_id = $2)`
+	if err := r.db.QueryRow(ctx, query, userID, pvzID).Scan(&assigned); err != nil {
+		return false, fmt.Errorf("query assignment: %w", err)
+	}
+
+	return assigned, nil
+}
@@ -0,0 +1,180 @@
+package assignments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/assignments/mocks"
+)
+
+type containsMatcher struct {
+	substr string
+}
+
+func (m *containsMatcher) Matches(x interface{}) bool {
+	s, ok := x.(string)
+	return ok && strings.Contains(s, m.substr)
+}
+
+func (m *containsMatcher) String() string {
+	return fmt.Sprintf("contains substring %q", m.substr)
+}
+
+func Contains(substr string) gomock.Matcher {
+	return &containsMatcher{substr: substr}
+}
+
+// fakeRow присваивает значения по порядку через reflect
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r *fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(dest) != len(r.values) {
+		return fmt.Errorf("ожидалось %d аргументов для Scan, получено %d", len(r.values), len(dest))
+	}
+	for i, v := range r.values {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+// TestAssign_Success проверяет закрепление сотрудника за ПВЗ.
+func TestAssign_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	userID, pvzID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("FROM users"), userID).
+		Return(&fakeRow{values: []interface{}{models.RoleEmployee}})
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("INSERT INTO employee_pvz"), userID, pvzID, now).
+		Return(&fakeRow{values: []interface{}{now}})
+
+	assignment, err := NewRepository(mockDB).Assign(ctx, userID, pvzID, now)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if assignment.UserID != userID || assignment.PvzID != pvzID || !assignment.AssignedAt.Equal(now) {
+		t.Errorf("неожиданное закрепление: %+v", assignment)
+	}
+}
+
+// TestAssignAllActive проверяет закрепление за всеми действующими ПВЗ без дублей.
+func TestAssignAllActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	userID := uuid.New()
+	now := time.Now()
+
+	mockDB.EXPECT().
+		Exec(ctx, Contains("WHERE deactivated_at IS NULL"), userID, now).
+		Return(pgconn.NewCommandTag("INSERT 0 2"), nil)
+
+	if err := NewRepository(mockDB).AssignAllActive(ctx, userID, now); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+// TestAssign_NotEmployee проверяет, что модератора нельзя закрепить за ПВЗ.
+func TestAssign_NotEmployee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	userID := uuid.New()
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("FROM users"), userID).
+		Return(&fakeRow{values: []interface{}{models.RoleModerator}})
+
+	_, err := NewRepository(mockDB).Assign(ctx, userID, uuid.New(), time.Now())
+	if !errors.Is(err, models.ErrNotEmployee) {
+		t.Errorf("ожидалась ErrNotEmployee, получено: %v", err)
+	}
+}
+
+// TestAssign_UnknownUser проверяет ответ на несуществующего пользователя.
+func TestAssign_UnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	userID := uuid.New()
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("FROM users"), userID).
+		Return(&fakeRow{err: pgx.ErrNoRows})
+
+	_, err := NewRepository(mockDB).Assign(ctx, userID, uuid.New(), time.Now())
+	if !errors.Is(err, models.ErrUserNotFound) {
+		t.Errorf("ожидалась ErrUserNotFound, получено: %v", err)
+	}
+}
+
+// TestAssign_UnknownPVZ проверяет, что нарушение внешнего ключа на ПВЗ превращается в ErrPVZNotFound.
+func TestAssign_UnknownPVZ(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	userID, pvzID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("FROM users"), userID).
+		Return(&fakeRow{values: []interface{}{models.RoleEmployee}})
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("INSERT INTO employee_pvz"), userID, pvzID, now).
+		Return(&fakeRow{err: &pgconn.PgError{Code: foreignKeyViolation, ConstraintName: "fk_employee_pvz_pickup_point"}})
+
+	_, err := NewRepository(mockDB).Assign(ctx, userID, pvzID, now)
+	if !errors.Is(err, models.ErrPVZNotFound) {
+		t.Errorf("ожидалась ErrPVZNotFound, получено: %v", err)
+	}
+}
+
+// TestUnassign_NotFound проверяет удаление несуществующего закрепления.
+func TestUnassign_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	userID, pvzID := uuid.New(), uuid.New()
+
+	mockDB.EXPECT().
+		Exec(ctx, Contains("DELETE FROM employee_pvz"), userID, pvzID).
+		Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := NewRepository(mockDB).Unassign(ctx, userID, pvzID)
+	if !errors.Is(err, models.ErrAssignmentNotFound) {
+		t.Errorf("ожидалась ErrAssignmentNotFound, получено: %v", err)
+	}
+}
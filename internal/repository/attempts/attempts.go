@@ -0,0 +1,115 @@
+//go:generate mockgen -source=attempts.go -destination=mocks/attempts.go -package=mocks $GOPACKAGE
+package attempts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxKeyLength - длина столбца login_attempts.key
+const maxKeyLength = 320
+
+// storedKey - ключ в пределах maxKeyLength. Более длинный ключ (например, email длиннее
+// допустимого, ещё не отвергнутый валидацией) заменяется хешем, чтобы запись в базу не падала
+func storedKey(key string) string {
+	if len(key) <= maxKeyLength {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func storedKeys(keys []string) []string {
+	stored := make([]string, len(keys))
+	for i, key := range keys {
+		stored[i] = storedKey(key)
+	}
+
+	return stored
+}
+
+type DB interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Repository - счётчики неудачных попыток входа в Postgres, общие для всех экземпляров сервиса
+type Repository struct {
+	db DB
+}
+
+func NewRepository(db DB) *Repository {
+	return &Repository{db: db}
+}
+
+// LockedUntil возвращает самый поздний срок блокировки среди ключей или нулевое время, если блокировки нет
+func (r *Repository) LockedUntil(ctx context.Context, keys []string, now time.Time) (time.Time, error) {
+	var lockedUntil *time.Time
+	query := `SELECT MAX(locked_until) FROM login_attempts WHERE key = ANY($1) AND locked_until > $2`
+	if err := r.db.QueryRow(ctx, query, storedKeys(keys), now).Scan(&lockedUntil); err != nil {
+		return time.Time{}, fmt.Errorf("query login lockout: %w", err)
+	}
+	if lockedUntil == nil {
+		return time.Time{}, nil
+	}
+
+	return *lockedUntil, nil
+}
+
+// RegisterFailure атомарно увеличивает счётчик неудач и возвращает его новое значение.
+// Если с прошлой неудачи прошло больше window, счёт начинается заново.
+func (r *Repository) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	var failures int
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+				WHEN login_attempts.last_failure_at < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures
+	`
+	if err := r.db.QueryRow(ctx, query, storedKey(key), now, now.Add(-window)).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("register login failure: %w", err)
+	}
+
+	// Заодно удаляются счётчики, которые уже не могут ни заблокировать вход, ни продолжить серию
+	query = `DELETE FROM login_attempts WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $2)`
+	if _, err := r.db.Exec(ctx, query, now.Add(-window), now); err != nil {
+		return 0, fmt.Errorf("cleanup login attempts: %w", err)
+	}
+
+	return failures, nil
+}
+
+// Lock блокирует ключ до until; более поздняя существующая блокировка не сокращается
+func (r *Repository) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `
+		UPDATE login_attempts
+		SET locked_until = GREATEST(COALESCE(locked_until, $2), $2)
+		WHERE key = $1
+	`
+	if _, err := r.db.Exec(ctx, query, storedKey(key), until); err != nil {
+		return fmt.Errorf("lock login: %w", err)
+	}
+
+	return nil
+}
+
+// Reset сбрасывает счётчик после успешного входа
+func (r *Repository) Reset(ctx context.Context, key string) error {
+	if _, err := r.db.Exec(ctx, `DELETE FROM login_attempts WHERE key = $1`, storedKey(key)); err != nil {
+		return fmt.Errorf("reset login attempts: %w", err)
+	}
+
+	return nil
+}
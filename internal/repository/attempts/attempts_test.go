@@ -0,0 +1,249 @@
+package attempts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/repository/attempts/mocks"
+)
+
+type containsMatcher struct {
+	substr string
+}
+
+func (m *containsMatcher) Matches(x interface{}) bool {
+	s, ok := x.(string)
+	return ok && strings.Contains(s, m.substr)
+}
+
+func (m *containsMatcher) String() string {
+	return fmt.Sprintf("contains substring %q", m.substr)
+}
+
+func Contains(substr string) gomock.Matcher {
+	return &containsMatcher{substr: substr}
+}
+
+// fakeRow присваивает значения по порядку через reflect
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r *fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(dest) != len(r.values) {
+		return fmt.Errorf("ожидалось %d аргументов для Scan, получено %d", len(r.values), len(dest))
+	}
+	for i, v := range r.values {
+		target := reflect.ValueOf(dest[i]).Elem()
+		if v == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		target.Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+// TestRegisterFailure проверяет, что счётчик возвращается из upsert, а устаревшие записи чистятся.
+func TestRegisterFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	now := time.Now()
+	window := 15 * time.Minute
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("ON CONFLICT (key)"), "email:user@example.com", now, now.Add(-window)).
+		Return(&fakeRow{values: []interface{}{3}})
+	mockDB.EXPECT().
+		Exec(ctx, Contains("DELETE FROM login_attempts"), now.Add(-window), now).
+		Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	failures, err := NewRepository(mockDB).RegisterFailure(ctx, "email:user@example.com", now, window)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if failures != 3 {
+		t.Fatalf("ожидалось 3 неудачи, получено %d", failures)
+	}
+}
+
+// TestRegisterFailure_LongKey проверяет, что ключ длиннее столбца заменяется хешем фиксированной длины.
+func TestRegisterFailure_LongKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	now := time.Now()
+	longKey := "email:" + strings.Repeat("a", 400) + "@example.com"
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("ON CONFLICT (key)"), gomock.Any(), now, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, args ...any) *fakeRow {
+			key := args[0].(string)
+			if len(key) > maxKeyLength || !strings.HasPrefix(key, "sha256:") {
+				t.Errorf("ключ не укорочен: %q", key)
+			}
+			if key != storedKey(longKey) {
+				t.Errorf("ключ должен быть стабильным: %q", key)
+			}
+			return &fakeRow{values: []interface{}{1}}
+		})
+	mockDB.EXPECT().Exec(ctx, Contains("DELETE FROM login_attempts"), gomock.Any(), now).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	if _, err := NewRepository(mockDB).RegisterFailure(ctx, longKey, now, time.Minute); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+// TestLockedUntil_NoLock проверяет, что отсутствие блокировки возвращается нулевым временем.
+func TestLockedUntil_NoLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	now := time.Now()
+	keys := []string{"email:user@example.com", "ip:10.0.0.1"}
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("MAX(locked_until)"), keys, now).
+		Return(&fakeRow{values: []interface{}{nil}})
+
+	lockedUntil, err := NewRepository(mockDB).LockedUntil(ctx, keys, now)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !lockedUntil.IsZero() {
+		t.Fatalf("ожидалось нулевое время, получено %v", lockedUntil)
+	}
+}
+
+// TestLockedUntil_Locked проверяет возврат срока блокировки.
+func TestLockedUntil_Locked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	now := time.Now()
+	until := now.Add(time.Minute)
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("MAX(locked_until)"), gomock.Any(), now).
+		Return(&fakeRow{values: []interface{}{&until}})
+
+	lockedUntil, err := NewRepository(mockDB).LockedUntil(ctx, []string{"ip:10.0.0.1"}, now)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !lockedUntil.Equal(until) {
+		t.Fatalf("ожидалось %v, получено %v", until, lockedUntil)
+	}
+}
+
+// TestLock_DBError проверяет, что ошибка базы пробрасывается с контекстом.
+func TestLock_DBError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	dbErr := errors.New("connection reset")
+
+	mockDB.EXPECT().
+		Exec(ctx, Contains("GREATEST"), "ip:10.0.0.1", gomock.Any()).
+		Return(pgconn.CommandTag{}, dbErr)
+
+	err := NewRepository(mockDB).Lock(ctx, "ip:10.0.0.1", time.Now())
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("ожидалась ошибка базы, получено %v", err)
+	}
+}
+
+// TestMemory_FailuresAndWindow проверяет счёт неудач и его сброс после окна.
+func TestMemory_FailuresAndWindow(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	now := time.Now()
+	window := time.Minute
+
+	for i := 1; i <= 3; i++ {
+		failures, _ := m.RegisterFailure(ctx, "email:a", now, window)
+		if failures != i {
+			t.Fatalf("ожидалось %d неудач, получено %d", i, failures)
+		}
+	}
+
+	failures, _ := m.RegisterFailure(ctx, "email:a", now.Add(2*window), window)
+	if failures != 1 {
+		t.Fatalf("после окна счёт должен начаться заново, получено %d", failures)
+	}
+}
+
+// TestMemory_SweepsOncePerWindow проверяет, что устаревшие записи удаляются, но не на каждой неудаче.
+func TestMemory_SweepsOncePerWindow(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	now := time.Now()
+	window := time.Minute
+
+	_, _ = m.RegisterFailure(ctx, "email:x", now, window)
+	_, _ = m.RegisterFailure(ctx, "email:a", now.Add(window/2), window)
+	_, _ = m.RegisterFailure(ctx, "email:y", now.Add(window), window)
+
+	// email:x и email:a уже устарели, но с последней очистки окно ещё не прошло
+	_, _ = m.RegisterFailure(ctx, "email:z", now.Add(window*8/5), window)
+	if len(m.entries) != 4 {
+		t.Fatalf("очистка внутри окна не ожидалась, записей %d", len(m.entries))
+	}
+
+	_, _ = m.RegisterFailure(ctx, "email:z", now.Add(2*window), window)
+	if _, ok := m.entries[storedKey("email:a")]; ok || len(m.entries) != 2 {
+		t.Fatalf("устаревшие записи не удалены после окна, записей %d", len(m.entries))
+	}
+}
+
+// TestMemory_LockAndReset проверяет блокировку, выбор самого позднего срока и сброс.
+func TestMemory_LockAndReset(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	now := time.Now()
+
+	_, _ = m.RegisterFailure(ctx, "email:a", now, time.Minute)
+	_, _ = m.RegisterFailure(ctx, "ip:1", now, time.Minute)
+	_ = m.Lock(ctx, "email:a", now.Add(time.Minute))
+	_ = m.Lock(ctx, "ip:1", now.Add(time.Hour))
+	_ = m.Lock(ctx, "ip:1", now.Add(time.Second))
+
+	lockedUntil, _ := m.LockedUntil(ctx, []string{"email:a", "ip:1"}, now)
+	if !lockedUntil.Equal(now.Add(time.Hour)) {
+		t.Fatalf("ожидался самый поздний срок, получено %v", lockedUntil)
+	}
+
+	_ = m.Reset(ctx, "ip:1")
+	lockedUntil, _ = m.LockedUntil(ctx, []string{"email:a", "ip:1"}, now)
+	if !lockedUntil.Equal(now.Add(time.Minute)) {
+		t.Fatalf("после сброса должна остаться блокировка email, получено %v", lockedUntil)
+	}
+
+	lockedUntil, _ = m.LockedUntil(ctx, []string{"email:a"}, now.Add(2*time.Minute))
+	if !lockedUntil.IsZero() {
+		t.Fatalf("истёкшая блокировка не должна учитываться, получено %v", lockedUntil)
+	}
+}
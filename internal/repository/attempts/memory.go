@@ -0,0 +1,95 @@
+package attempts
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	failures      int
+	lastFailureAt time.Time
+	lockedUntil   time.Time
+}
+
+// Memory - счётчики в памяти процесса. Подходит для одного экземпляра сервиса и тестов:
+// при нескольких экземплярах каждый считает попытки отдельно.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	// sweptAt - время последней очистки устаревших записей. Очистка обходит всю карту,
+	// поэтому выполняется не чаще раза за окно, а не на каждую неудачу
+	sweptAt time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]*entry)}
+}
+
+func (m *Memory) LockedUntil(_ context.Context, keys []string, now time.Time) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var lockedUntil time.Time
+	for _, key := range storedKeys(keys) {
+		e, ok := m.entries[key]
+		if ok && e.lockedUntil.After(now) && e.lockedUntil.After(lockedUntil) {
+			lockedUntil = e.lockedUntil
+		}
+	}
+
+	return lockedUntil, nil
+}
+
+func (m *Memory) RegisterFailure(_ context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key = storedKey(key)
+	staleBefore := now.Add(-window)
+	e, ok := m.entries[key]
+	if !ok || e.lastFailureAt.Before(staleBefore) {
+		if !ok {
+			e = &entry{}
+			m.entries[key] = e
+		}
+		e.failures = 0
+	}
+	e.failures++
+	e.lastFailureAt = now
+
+	if now.Sub(m.sweptAt) >= window {
+		m.sweep(staleBefore, now)
+		m.sweptAt = now
+	}
+
+	return e.failures, nil
+}
+
+// sweep удаляет записи без неудач за окно и без действующей блокировки
+func (m *Memory) sweep(staleBefore, now time.Time) {
+	for k, e := range m.entries {
+		if e.lastFailureAt.Before(staleBefore) && e.lockedUntil.Before(now) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+func (m *Memory) Lock(_ context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[storedKey(key)]; ok && until.After(e.lockedUntil) {
+		e.lockedUntil = until
+	}
+
+	return nil
+}
+
+func (m *Memory) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, storedKey(key))
+	return nil
+}
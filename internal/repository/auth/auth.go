@@ -4,14 +4,25 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/transaction"
 )
 
+const uniqueViolation = "23505"
+
 type pool interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
 }
 
 type Repository struct {
@@ -24,11 +35,13 @@ func NewInsertRepo(pool pool) *Repository {
 
 func (r *Repository) GetUserEmail(ctx context.Context, email string) (models.User, error) {
 	var dbUser models.User
-	query := `SELECT id, email, password, role FROM users WHERE email = $1 LIMIT 1`
+	query := `SELECT id, email, password, role, disabled_at FROM users WHERE email = $1 LIMIT 1`
 
 	row := r.pool.QueryRow(ctx, query, email)
-	err := row.Scan(&dbUser.ID, &dbUser.Email, &dbUser.Password, &dbUser.Role)
-	if err != nil {
+	err := row.Scan(&dbUser.ID, &dbUser.Email, &dbUser.Password, &dbUser.Role, &dbUser.DisabledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dbUser, models.ErrUserNotFound
+	} else if err != nil {
 		return dbUser, fmt.Errorf("failed to scan user: %w", err)
 	}
 
@@ -43,9 +56,338 @@ func (r *Repository) InsertUser(ctx context.Context, user models.User) (string,
         RETURNING id
     `
 	err := r.pool.QueryRow(ctx, query, user.ID, user.Email, user.Password, user.Role).Scan(&userID)
-	if err != nil {
+	if isUniqueViolation(err) {
+		return "", models.ErrEmailTaken
+	} else if err != nil {
 		return "", fmt.Errorf("failed to insert user: %w", err)
 	}
 
 	return userID, nil
 }
+
+func (r *Repository) GetUserByID(ctx context.Context, id uuid.UUID) (models.User, error) {
+	var dbUser models.User
+	query := `SELECT id, email, role, disabled_at FROM users WHERE id = $1`
+
+	err := r.pool.QueryRow(ctx, query, id).Scan(&dbUser.ID, &dbUser.Email, &dbUser.Role, &dbUser.DisabledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dbUser, models.ErrUserNotFound
+	} else if err != nil {
+		return dbUser, fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	return dbUser, nil
+}
+
+// ListUsers - пользователи без хэшей паролей, упорядоченные по email
+func (r *Repository) ListUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
+	query := `
+		SELECT id, email, role, disabled_at
+		FROM users
+		ORDER BY email
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0, limit)
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Email, &u.Role, &u.DisabledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateRole меняет роль пользователя и отзывает его refresh-токены: в них записана прежняя роль
+func (r *Repository) UpdateRole(ctx context.Context, id uuid.UUID, role models.UserRole, now time.Time) (models.User, error) {
+	var user models.User
+	err := transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		user, err = lockUser(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if user.Role == models.RoleAdmin && role != models.RoleAdmin && user.DisabledAt == nil {
+			if err := ensureOtherAdmin(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE users SET role = $2 WHERE id = $1`, id, role); err != nil {
+			return fmt.Errorf("failed to update role: %w", err)
+		}
+		user.Role = role
+
+		return revokeSessions(ctx, tx, id, now)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// SetDisabled блокирует или разблокирует учётную запись. При блокировке отзываются refresh-токены
+func (r *Repository) SetDisabled(ctx context.Context, id uuid.UUID, disabled bool, now time.Time) (models.User, error) {
+	var user models.User
+	err := transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		user, err = lockUser(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !disabled {
+			if _, err := tx.Exec(ctx, `UPDATE users SET disabled_at = NULL WHERE id = $1`, id); err != nil {
+				return fmt.Errorf("failed to enable user: %w", err)
+			}
+			user.DisabledAt = nil
+			return nil
+		}
+
+		if user.DisabledAt != nil {
+			return nil
+		}
+		if user.Role == models.RoleAdmin {
+			if err := ensureOtherAdmin(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE users SET disabled_at = $2 WHERE id = $1`, id, now); err != nil {
+			return fmt.Errorf("failed to disable user: %w", err)
+		}
+		user.DisabledAt = &now
+
+		return revokeSessions(ctx, tx, id, now)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// UpdatePassword сохраняет новый хэш пароля и завершает все сессии пользователя
+func (r *Repository) UpdatePassword(ctx context.Context, id uuid.UUID, hash string, now time.Time) error {
+	return transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `UPDATE users SET password = $2 WHERE id = $1`, id, hash)
+		if err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return models.ErrUserNotFound
+		}
+
+		return revokeSessions(ctx, tx, id, now)
+	})
+}
+
+// GetPasswordHash - хеш пароля пользователя для проверки текущего пароля
+func (r *Repository) GetPasswordHash(ctx context.Context, id uuid.UUID) (string, error) {
+	var hash string
+	err := r.pool.QueryRow(ctx, `SELECT password FROM users WHERE id = $1`, id).Scan(&hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", models.ErrUserNotFound
+	} else if err != nil {
+		return "", fmt.Errorf("failed to query password: %w", err)
+	}
+
+	return hash, nil
+}
+
+// RehashPassword заменяет хеш того же пароля на более стойкий. Сессии не отзываются, а если пароль
+// успели сменить параллельно, новый хеш не записывается
+func (r *Repository) RehashPassword(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	query := `UPDATE users SET password = $3 WHERE id = $1 AND password = $2`
+	if _, err := r.pool.Exec(ctx, query, id, oldHash, newHash); err != nil {
+		return fmt.Errorf("failed to rehash password: %w", err)
+	}
+
+	return nil
+}
+
+// CreateResetToken сохраняет токен сброса пароля; выданные ранее неиспользованные токены пользователя удаляются
+func (r *Repository) CreateResetToken(ctx context.Context, token models.PasswordResetToken) error {
+	return transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		query := `DELETE FROM password_reset_tokens WHERE user_id = $1 AND used_at IS NULL`
+		if _, err := tx.Exec(ctx, query, token.UserID); err != nil {
+			return fmt.Errorf("failed to delete reset tokens: %w", err)
+		}
+
+		query = `
+			INSERT INTO password_reset_tokens (token_hash, user_id, created_at, expires_at)
+			VALUES ($1, $2, $3, $4)
+		`
+		if _, err := tx.Exec(ctx, query, token.TokenHash, token.UserID, token.CreatedAt, token.ExpiresAt); err != nil {
+			return fmt.Errorf("failed to insert reset token: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ResetTokenValid - есть ли неиспользованный и неистёкший токен сброса с таким хешем.
+// Окончательно токен проверяется и гасится в ResetPassword
+func (r *Repository) ResetTokenValid(ctx context.Context, tokenHash string, now time.Time) error {
+	var valid bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM password_reset_tokens
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		)
+	`
+	if err := r.pool.QueryRow(ctx, query, tokenHash, now).Scan(&valid); err != nil {
+		return fmt.Errorf("failed to query reset token: %w", err)
+	}
+	if !valid {
+		return models.ErrInvalidResetToken
+	}
+
+	return nil
+}
+
+// ResetPassword гасит токен сброса и устанавливает новый пароль в одной транзакции.
+// Все сессии пользователя отзываются
+func (r *Repository) ResetPassword(ctx context.Context, tokenHash, hash string, now time.Time) error {
+	return transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		var (
+			userID    uuid.UUID
+			expiresAt time.Time
+			usedAt    *time.Time
+		)
+		query := `
+			SELECT user_id, expires_at, used_at
+			FROM password_reset_tokens
+			WHERE token_hash = $1
+			FOR UPDATE
+		`
+		err := tx.QueryRow(ctx, query, tokenHash).Scan(&userID, &expiresAt, &usedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrInvalidResetToken
+		} else if err != nil {
+			return fmt.Errorf("failed to query reset token: %w", err)
+		}
+		if usedAt != nil || !expiresAt.After(now) {
+			return models.ErrInvalidResetToken
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = $2 WHERE token_hash = $1`, tokenHash, now); err != nil {
+			return fmt.Errorf("failed to use reset token: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx, `UPDATE users SET password = $2 WHERE id = $1`, userID, hash)
+		if err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return models.ErrInvalidResetToken
+		}
+
+		return revokeSessions(ctx, tx, userID, now)
+	})
+}
+
+// UpsertUser создаёт пользователя, а если email уже занят - возвращает существующего,
+// выставив ему роль user.Role и сняв блокировку. Используется только /dummyLogin в dev и test
+func (r *Repository) UpsertUser(ctx context.Context, user models.User) (uuid.UUID, error) {
+	var userID uuid.UUID
+	query := `
+		INSERT INTO users (id, email, password, role)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (email) DO UPDATE SET role = EXCLUDED.role, disabled_at = NULL
+		RETURNING id
+	`
+	err := r.pool.QueryRow(ctx, query, user.ID, user.Email, user.Password, user.Role).Scan(&userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to upsert user: %w", err)
+	}
+
+	return userID, nil
+}
+
+// InsertFirstAdmin создаёт администратора, только если в системе ещё нет ни одного
+func (r *Repository) InsertFirstAdmin(ctx context.Context, user models.User) (string, error) {
+	var userID string
+	err := transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE role = $1)`, models.RoleAdmin).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to query admins: %w", err)
+		}
+		if exists {
+			return models.ErrAdminExists
+		}
+
+		query := `
+			INSERT INTO users (id, email, password, role)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`
+		err := tx.QueryRow(ctx, query, user.ID, user.Email, user.Password, models.RoleAdmin).Scan(&userID)
+		if isUniqueViolation(err) {
+			return models.ErrEmailTaken
+		} else if err != nil {
+			return fmt.Errorf("failed to insert admin: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return userID, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+func lockUser(ctx context.Context, tx pgx.Tx, id uuid.UUID) (models.User, error) {
+	var user models.User
+	query := `SELECT id, email, role, disabled_at FROM users WHERE id = $1 FOR UPDATE`
+
+	err := tx.QueryRow(ctx, query, id).Scan(&user.ID, &user.Email, &user.Role, &user.DisabledAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return user, models.ErrUserNotFound
+	} else if err != nil {
+		return user, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	return user, nil
+}
+
+// ensureOtherAdmin не даёт снять роль или заблокировать последнего активного администратора
+func ensureOtherAdmin(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	var others int
+	query := `SELECT count(*) FROM users WHERE role = $1 AND disabled_at IS NULL AND id <> $2`
+	if err := tx.QueryRow(ctx, query, models.RoleAdmin, id).Scan(&others); err != nil {
+		return fmt.Errorf("failed to count admins: %w", err)
+	}
+	if others == 0 {
+		return models.ErrLastAdmin
+	}
+
+	return nil
+}
+
+func revokeSessions(ctx context.Context, tx pgx.Tx, userID uuid.UUID, now time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err := tx.Exec(ctx, query, userID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
@@ -3,11 +3,15 @@ package auth
 import (
 	"context"
 	"fmt"
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/suite"
-	"testing"
 
 	"AvitoPVZ/internal/models"
 	"AvitoPVZ/internal/repository/auth/mocks"
@@ -21,19 +25,69 @@ func (f fakeRow) Scan(dest ...interface{}) error {
 	return f.scanFunc(dest...)
 }
 
+type containsMatcher struct {
+	substr string
+}
+
+func (m *containsMatcher) Matches(x interface{}) bool {
+	s, ok := x.(string)
+	return ok && strings.Contains(s, m.substr)
+}
+
+func (m *containsMatcher) String() string {
+	return fmt.Sprintf("contains substring %q", m.substr)
+}
+
+func Contains(substr string) gomock.Matcher {
+	return &containsMatcher{substr: substr}
+}
+
+func userRow(user models.User) fakeRow {
+	return fakeRow{
+		scanFunc: func(dest ...interface{}) error {
+			*(dest[0].(*uuid.UUID)) = user.ID
+			*(dest[1].(*string)) = user.Email
+			*(dest[2].(*models.UserRole)) = user.Role
+			*(dest[3].(**time.Time)) = user.DisabledAt
+			return nil
+		},
+	}
+}
+
+func intRow(n int) fakeRow {
+	return fakeRow{
+		scanFunc: func(dest ...interface{}) error {
+			*(dest[0].(*int)) = n
+			return nil
+		},
+	}
+}
+
 type RepositorySuite struct {
 	suite.Suite
 	ctrl *gomock.Controller
 	pool *mocks.Mockpool
+	tx   *mocks.MockTx
 	repo *Repository
 }
 
 func (s *RepositorySuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.pool = mocks.NewMockpool(s.ctrl)
+	s.tx = mocks.NewMockTx(s.ctrl)
 	s.repo = NewInsertRepo(s.pool)
 }
 
+func (s *RepositorySuite) expectTx(ctx context.Context) {
+	s.pool.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(s.tx, nil)
+	s.tx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+}
+
 func (s *RepositorySuite) TearDownTest() {
 	s.ctrl.Finish()
 }
@@ -46,7 +100,7 @@ func (s *RepositorySuite) TestGetUserEmail_Success() {
 		Role:     "user",
 	}
 	ctx := context.Background()
-	query := `SELECT id, email, password, role FROM users WHERE email = $1 LIMIT 1`
+	query := `SELECT id, email, password, role, disabled_at FROM users WHERE email = $1 LIMIT 1`
 
 	s.pool.EXPECT().
 		QueryRow(ctx, query, expectedUser.Email).
@@ -56,6 +110,7 @@ func (s *RepositorySuite) TestGetUserEmail_Success() {
 				*(dest[1].(*string)) = expectedUser.Email
 				*(dest[2].(*string)) = expectedUser.Password
 				*(dest[3].(*models.UserRole)) = expectedUser.Role
+				*(dest[4].(**time.Time)) = nil
 				return nil
 			},
 		})
@@ -69,7 +124,7 @@ func (s *RepositorySuite) TestGetUserEmail_Error() {
 	ctx := context.Background()
 	email := "missing@example.com"
 	expectedError := fmt.Errorf("scan error")
-	query := `SELECT id, email, password, role FROM users WHERE email = $1 LIMIT 1`
+	query := `SELECT id, email, password, role, disabled_at FROM users WHERE email = $1 LIMIT 1`
 
 	s.pool.EXPECT().
 		QueryRow(ctx, query, email).
@@ -141,6 +196,185 @@ func (s *RepositorySuite) TestInsertUser_Error() {
 	s.Contains(err.Error(), "failed to insert user")
 }
 
+func (s *RepositorySuite) TestInsertUser_DuplicateEmail() {
+	ctx := context.Background()
+	newUser := models.User{ID: uuid.New(), Email: "taken@example.com", Password: "hash", Role: models.RoleEmployee}
+
+	s.pool.EXPECT().
+		QueryRow(ctx, Contains("INSERT INTO users"), newUser.ID, newUser.Email, newUser.Password, newUser.Role).
+		Return(fakeRow{
+			scanFunc: func(dest ...interface{}) error {
+				return &pgconn.PgError{Code: uniqueViolation, ConstraintName: "users_email_key"}
+			},
+		})
+
+	_, err := s.repo.InsertUser(ctx, newUser)
+	s.Require().ErrorIs(err, models.ErrEmailTaken)
+	s.NotContains(err.Error(), "users_email_key")
+}
+
+func (s *RepositorySuite) TestUpdateRole_LastAdmin() {
+	ctx := context.Background()
+	admin := models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().QueryRow(ctx, Contains("FOR UPDATE"), admin.ID).Return(userRow(admin))
+	s.tx.EXPECT().QueryRow(ctx, Contains("count(*)"), models.RoleAdmin, admin.ID).Return(intRow(0))
+
+	_, err := s.repo.UpdateRole(ctx, admin.ID, models.RoleEmployee, time.Now())
+	s.ErrorIs(err, models.ErrLastAdmin)
+}
+
+func (s *RepositorySuite) TestUpdateRole_RevokesSessions() {
+	ctx := context.Background()
+	now := time.Now()
+	user := models.User{ID: uuid.New(), Email: "user@example.com", Role: models.RoleEmployee}
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().QueryRow(ctx, Contains("FOR UPDATE"), user.ID).Return(userRow(user))
+	s.tx.EXPECT().Exec(ctx, Contains("SET role"), user.ID, models.RoleModerator).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	s.tx.EXPECT().Exec(ctx, Contains("UPDATE refresh_tokens"), user.ID, now).Return(pgconn.NewCommandTag("UPDATE 2"), nil)
+	s.tx.EXPECT().Commit(ctx).Return(nil)
+
+	updated, err := s.repo.UpdateRole(ctx, user.ID, models.RoleModerator, now)
+	s.Require().NoError(err)
+	s.Equal(models.RoleModerator, updated.Role)
+}
+
+func (s *RepositorySuite) TestSetDisabled_NotFound() {
+	ctx := context.Background()
+	id := uuid.New()
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().QueryRow(ctx, Contains("FOR UPDATE"), id).Return(fakeRow{
+		scanFunc: func(dest ...interface{}) error { return pgx.ErrNoRows },
+	})
+
+	_, err := s.repo.SetDisabled(ctx, id, true, time.Now())
+	s.ErrorIs(err, models.ErrUserNotFound)
+}
+
+func (s *RepositorySuite) TestSetDisabled_Disables() {
+	ctx := context.Background()
+	now := time.Now()
+	user := models.User{ID: uuid.New(), Email: "user@example.com", Role: models.RoleEmployee}
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().QueryRow(ctx, Contains("FOR UPDATE"), user.ID).Return(userRow(user))
+	s.tx.EXPECT().Exec(ctx, Contains("SET disabled_at = $2"), user.ID, now).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	s.tx.EXPECT().Exec(ctx, Contains("UPDATE refresh_tokens"), user.ID, now).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+	s.tx.EXPECT().Commit(ctx).Return(nil)
+
+	updated, err := s.repo.SetDisabled(ctx, user.ID, true, now)
+	s.Require().NoError(err)
+	s.Require().NotNil(updated.DisabledAt)
+	s.Equal(now, *updated.DisabledAt)
+}
+
+func (s *RepositorySuite) TestInsertFirstAdmin_AdminExists() {
+	ctx := context.Background()
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().QueryRow(ctx, Contains("SELECT EXISTS"), models.RoleAdmin).Return(fakeRow{
+		scanFunc: func(dest ...interface{}) error {
+			*(dest[0].(*bool)) = true
+			return nil
+		},
+	})
+
+	_, err := s.repo.InsertFirstAdmin(ctx, models.User{ID: uuid.New(), Email: "root@example.com"})
+	s.ErrorIs(err, models.ErrAdminExists)
+}
+
+func resetTokenRow(userID uuid.UUID, expiresAt time.Time, usedAt *time.Time) fakeRow {
+	return fakeRow{
+		scanFunc: func(dest ...interface{}) error {
+			*(dest[0].(*uuid.UUID)) = userID
+			*(dest[1].(*time.Time)) = expiresAt
+			*(dest[2].(**time.Time)) = usedAt
+			return nil
+		},
+	}
+}
+
+func (s *RepositorySuite) TestResetPassword_Success() {
+	ctx := context.Background()
+	now := time.Now()
+	userID := uuid.New()
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().QueryRow(ctx, Contains("FROM password_reset_tokens"), "hash").Return(resetTokenRow(userID, now.Add(time.Minute), nil))
+	s.tx.EXPECT().Exec(ctx, Contains("SET used_at"), "hash", now).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	s.tx.EXPECT().Exec(ctx, Contains("SET password"), userID, "new-hash").Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	s.tx.EXPECT().Exec(ctx, Contains("UPDATE refresh_tokens"), userID, now).Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	s.tx.EXPECT().Commit(ctx).Return(nil)
+
+	s.Require().NoError(s.repo.ResetPassword(ctx, "hash", "new-hash", now))
+}
+
+func (s *RepositorySuite) TestResetPassword_UsedOrExpired() {
+	ctx := context.Background()
+	now := time.Now()
+	usedAt := now.Add(-time.Minute)
+
+	rows := []fakeRow{
+		resetTokenRow(uuid.New(), now.Add(time.Minute), &usedAt),
+		resetTokenRow(uuid.New(), now.Add(-time.Second), nil),
+		{scanFunc: func(dest ...interface{}) error { return pgx.ErrNoRows }},
+	}
+	for _, row := range rows {
+		s.expectTx(ctx)
+		s.tx.EXPECT().QueryRow(ctx, Contains("FROM password_reset_tokens"), "hash").Return(row)
+
+		err := s.repo.ResetPassword(ctx, "hash", "new-hash", now)
+		s.ErrorIs(err, models.ErrInvalidResetToken)
+	}
+}
+
+func (s *RepositorySuite) TestResetTokenValid() {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, valid := range []bool{true, false} {
+		s.pool.EXPECT().QueryRow(ctx, Contains("used_at IS NULL AND expires_at > $2"), "hash", now).Return(fakeRow{
+			scanFunc: func(dest ...interface{}) error {
+				*(dest[0].(*bool)) = valid
+				return nil
+			},
+		})
+
+		err := s.repo.ResetTokenValid(ctx, "hash", now)
+		if valid {
+			s.NoError(err)
+		} else {
+			s.ErrorIs(err, models.ErrInvalidResetToken)
+		}
+	}
+}
+
+func (s *RepositorySuite) TestCreateResetToken_ReplacesUnused() {
+	ctx := context.Background()
+	now := time.Now()
+	token := models.PasswordResetToken{TokenHash: "hash", UserID: uuid.New(), CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	s.expectTx(ctx)
+	s.tx.EXPECT().Exec(ctx, Contains("DELETE FROM password_reset_tokens"), token.UserID).Return(pgconn.NewCommandTag("DELETE 1"), nil)
+	s.tx.EXPECT().Exec(ctx, Contains("INSERT INTO password_reset_tokens"), "hash", token.UserID, now, token.ExpiresAt).
+		Return(pgconn.NewCommandTag("INSERT 0 1"), nil)
+	s.tx.EXPECT().Commit(ctx).Return(nil)
+
+	s.Require().NoError(s.repo.CreateResetToken(ctx, token))
+}
+
+func (s *RepositorySuite) TestRehashPassword_KeepsConcurrentChange() {
+	ctx := context.Background()
+	id := uuid.New()
+
+	s.pool.EXPECT().Exec(ctx, Contains("AND password = $2"), id, "old", "new").Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+
+	s.Require().NoError(s.repo.RehashPassword(ctx, id, "old", "new"))
+}
+
 func TestRepositorySuite(t *testing.T) {
 	suite.Run(t, new(RepositorySuite))
 }
@@ -4,18 +4,23 @@ package products
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/transaction"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 )
 
 type DB interface {
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
 }
 
+const productColumns = `g.id, g.accepted_datetime, g.product_type, g.receiving_id, g.barcode, g.weight_grams, g.length_mm, g.width_mm, g.height_mm`
+
 type ProductRepositoryPg struct {
 	db DB
 }
@@ -24,98 +29,244 @@ func NewProductRepositoryPg(db DB) *ProductRepositoryPg {
 	return &ProductRepositoryPg{db: db}
 }
 
-func (r *ProductRepositoryPg) CreateProductTransactional(ctx context.Context, pvzID uuid.UUID, productType models.TypeProduct) (models.Product, error) {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
-	if err != nil {
-		return models.Product{}, fmt.Errorf("begin transaction: %w", err)
+// CreateProductTransactional - добавление товара в активную приёмку ПВЗ.
+// Штрихкод, если он передан, не должен встречаться в других открытых приёмках.
+func (r *ProductRepositoryPg) CreateProductTransactional(ctx context.Context, pvzID uuid.UUID, product models.Product) (models.Product, error) {
+	created, err := r.CreateProductsBatchTransactional(ctx, pvzID, []models.Product{product})
+	var itemErr *models.BatchItemError
+	if errors.As(err, &itemErr) {
+		return models.Product{}, itemErr.Err
+	} else if err != nil {
+		return models.Product{}, err
 	}
-	defer tx.Rollback(ctx)
 
-	queryReception := `
-		SELECT id, receiving_datetime, pickup_point_id, status
-		FROM receiving
-		WHERE pickup_point_id = $1 AND status = 'in_progress'
-		ORDER BY receiving_datetime DESC
-		LIMIT 1
-		FOR UPDATE
-	`
-	var recID string
-	var recTime time.Time
-	var recPvzID string
-	var recStatus string
+	return created[0], nil
+}
 
-	err = tx.QueryRow(ctx, queryReception, pvzID).
-		Scan(&recID, &recTime, &recPvzID, &recStatus)
-	if err != nil {
-		return models.Product{}, fmt.Errorf("нет активной приёмки для pvzID=%s: %w", pvzID, err)
-	}
+// CreateProductsBatchTransactional - добавление пачки товаров в активную приёмку ПВЗ одной транзакцией.
+// Либо добавляются все товары, либо ни один; ошибка конкретного товара возвращается как *models.BatchItemError.
+func (r *ProductRepositoryPg) CreateProductsBatchTransactional(ctx context.Context, pvzID uuid.UUID, products []models.Product) ([]models.Product, error) {
+	var created []models.Product
+	err := transaction.Serializable(ctx, r.db, func(tx pgx.Tx) error {
+		queryReception := `
+			SELECT id, receiving_datetime, pickup_point_id, status
+			FROM receiving
+			WHERE pickup_point_id = $1 AND status = 'in_progress'
+			ORDER BY receiving_datetime DESC
+			LIMIT 1
+			FOR UPDATE
+		`
+		var recID string
+		var recTime time.Time
+		var recPvzID string
+		var recStatus string
 
-	productID := uuid.NewString()
-	acceptedTime := time.Now()
+		err := tx.QueryRow(ctx, queryReception, pvzID).
+			Scan(&recID, &recTime, &recPvzID, &recStatus)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNoActiveReception
+		} else if err != nil {
+			return fmt.Errorf("query active reception: %w", err)
+		}
 
-	queryInsert := `
-		INSERT INTO goods (id, receiving_id, accepted_datetime, product_type)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, accepted_datetime, product_type, receiving_id
-	`
-	var prod models.Product
-	err = tx.QueryRow(ctx, queryInsert, productID, recID, acceptedTime, productType).
-		Scan(&prod.ID, &prod.DateTime, &prod.Type, &prod.ReceptionID)
-	if err != nil {
-		return models.Product{}, fmt.Errorf("невозможно добавить товар: %w", err)
-	}
+		if err = checkBarcodes(ctx, tx, products); err != nil {
+			return err
+		}
 
-	if err = tx.Commit(ctx); err != nil {
-		return models.Product{}, fmt.Errorf("commit transaction: %w", err)
+		queryInsert := `
+			INSERT INTO goods AS g (id, receiving_id, accepted_datetime, product_type, barcode, weight_grams, length_mm, width_mm, height_mm)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING ` + productColumns
+
+		created = make([]models.Product, 0, len(products))
+		for i, product := range products {
+			var length, width, height *int
+			if product.Dimensions != nil {
+				length, width, height = &product.Dimensions.LengthMM, &product.Dimensions.WidthMM, &product.Dimensions.HeightMM
+			}
+
+			prod, err := scanProduct(tx.QueryRow(ctx, queryInsert, uuid.NewString(), recID, time.Now(), product.Type,
+				product.Barcode, product.WeightGrams, length, width, height))
+			if err != nil {
+				return &models.BatchItemError{Index: i, Err: fmt.Errorf("невозможно добавить товар: %w", err)}
+			}
+			created = append(created, prod)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return prod, nil
+	return created, nil
 }
 
-func (r *ProductRepositoryPg) DeleteLastProductTransactional(ctx context.Context, pvzID string) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
-	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+// checkBarcodes проверяет, что штрихкоды не повторяются внутри пачки и не встречаются в открытых приёмках
+func checkBarcodes(ctx context.Context, tx pgx.Tx, products []models.Product) error {
+	indexes := make(map[string]int, len(products))
+	barcodes := make([]string, 0, len(products))
+	for i, product := range products {
+		if product.Barcode == nil {
+			continue
+		}
+		if _, ok := indexes[*product.Barcode]; ok {
+			return &models.BatchItemError{Index: i, Err: models.ErrDuplicateBarcode}
+		}
+		indexes[*product.Barcode] = i
+		barcodes = append(barcodes, *product.Barcode)
+	}
+	if len(barcodes) == 0 {
+		return nil
 	}
-	defer tx.Rollback(ctx)
 
-	queryReception := `
-		SELECT id 
-		FROM receiving
-		WHERE pickup_point_id = $1 AND status = 'in_progress'
-		ORDER BY receiving_datetime DESC
+	queryBarcode := `
+		SELECT g.barcode
+		FROM goods g
+		JOIN receiving r ON r.id = g.receiving_id
+		WHERE g.barcode = ANY($1) AND r.status = 'in_progress'
 		LIMIT 1
-		FOR UPDATE
 	`
-	var recID string
-	err = tx.QueryRow(ctx, queryReception, pvzID).Scan(&recID)
-	if err != nil {
-		return fmt.Errorf("нет активной приемки для pvzID=%s: %w", pvzID, err)
+	var duplicate string
+	err := tx.QueryRow(ctx, queryBarcode, barcodes).Scan(&duplicate)
+	if err == nil {
+		return &models.BatchItemError{Index: indexes[duplicate], Err: models.ErrDuplicateBarcode}
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("query barcode: %w", err)
 	}
 
-	queryProduct := `
-		SELECT id
-		FROM goods
-		WHERE receiving_id = $1
-		ORDER BY accepted_datetime DESC
+	return nil
+}
+
+func (r *ProductRepositoryPg) DeleteLastProductTransactional(ctx context.Context, pvzID string) error {
+	return transaction.Serializable(ctx, r.db, func(tx pgx.Tx) error {
+		queryReception := `
+			SELECT id
+			FROM receiving
+			WHERE pickup_point_id = $1 AND status = 'in_progress'
+			ORDER BY receiving_datetime DESC
+			LIMIT 1
+			FOR UPDATE
+		`
+		var recID string
+		err := tx.QueryRow(ctx, queryReception, pvzID).Scan(&recID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNoActiveReception
+		} else if err != nil {
+			return fmt.Errorf("query active reception: %w", err)
+		}
+
+		queryProduct := `
+			SELECT id
+			FROM goods
+			WHERE receiving_id = $1
+			ORDER BY accepted_datetime DESC
+			LIMIT 1
+			FOR UPDATE
+		`
+		var prodID string
+		err = tx.QueryRow(ctx, queryProduct, recID).Scan(&prodID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNoProductsToDelete
+		} else if err != nil {
+			return fmt.Errorf("query last product: %w", err)
+		}
+
+		deleteQuery := `DELETE FROM goods WHERE id = $1`
+		_, err = tx.Exec(ctx, deleteQuery, prodID)
+		if err != nil {
+			return fmt.Errorf("ошибка при удалении товара: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// DeleteProductTransactional - удаление конкретного товара ПВЗ.
+// Удалять можно только из приёмки в статусе in_progress, строка приёмки блокируется до конца транзакции.
+func (r *ProductRepositoryPg) DeleteProductTransactional(ctx context.Context, pvzID, productID string) error {
+	return transaction.Serializable(ctx, r.db, func(tx pgx.Tx) error {
+		queryReception := `
+			SELECT r.id, r.status
+			FROM goods g
+			JOIN receiving r ON r.id = g.receiving_id
+			WHERE g.id = $1 AND r.pickup_point_id = $2
+			FOR UPDATE
+		`
+		var recID, recStatus string
+		err := tx.QueryRow(ctx, queryReception, productID, pvzID).Scan(&recID, &recStatus)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrProductNotFound
+		} else if err != nil {
+			return fmt.Errorf("query product reception: %w", err)
+		}
+
+		if models.StatusReception(recStatus) != models.StatusInProgress {
+			return models.ErrReceptionClosed
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM goods WHERE id = $1`, productID)
+		if err != nil {
+			return fmt.Errorf("ошибка при удалении товара: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetByBarcode - последний принятый товар с указанным штрихкодом вместе с его приёмкой и ПВЗ
+func (r *ProductRepositoryPg) GetByBarcode(ctx context.Context, barcode string) (models.ProductLocation, error) {
+	query := `
+		SELECT ` + productColumns + `,
+			r.id, r.receiving_datetime, r.pickup_point_id, r.status,
+			p.id, p.registration_date, p.city, p.deactivated_at
+		FROM goods g
+		JOIN receiving r ON r.id = g.receiving_id
+		JOIN pickup_point p ON p.id = r.pickup_point_id
+		WHERE g.barcode = $1
+		ORDER BY g.accepted_datetime DESC
 		LIMIT 1
-		FOR UPDATE
 	`
-	var prodID string
-	err = tx.QueryRow(ctx, queryProduct, recID).Scan(&prodID)
-	if err != nil {
-		return fmt.Errorf("нет товаров для удаления в приемке: %w", err)
+	var (
+		loc                   models.ProductLocation
+		length, width, height *int
+	)
+	err := r.db.QueryRow(ctx, query, barcode).Scan(
+		&loc.Product.ID, &loc.Product.DateTime, &loc.Product.Type, &loc.Product.ReceptionID,
+		&loc.Product.Barcode, &loc.Product.WeightGrams, &length, &width, &height,
+		&loc.Reception.ID, &loc.Reception.DateTime, &loc.Reception.PvzID, &loc.Reception.Status,
+		&loc.PVZ.ID, &loc.PVZ.RegistrationDate, &loc.PVZ.City, &loc.PVZ.DeactivatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ProductLocation{}, models.ErrProductNotFound
+	} else if err != nil {
+		return models.ProductLocation{}, fmt.Errorf("query product by barcode: %w", err)
 	}
+	loc.Product.Dimensions = toDimensions(length, width, height)
+	loc.PVZ.Active = loc.PVZ.DeactivatedAt == nil
+
+	return loc, nil
+}
 
-	deleteQuery := `DELETE FROM goods WHERE id = $1`
-	_, err = tx.Exec(ctx, deleteQuery, prodID)
+func scanProduct(row pgx.Row) (models.Product, error) {
+	var (
+		prod                  models.Product
+		length, width, height *int
+	)
+	err := row.Scan(&prod.ID, &prod.DateTime, &prod.Type, &prod.ReceptionID,
+		&prod.Barcode, &prod.WeightGrams, &length, &width, &height)
 	if err != nil {
-		return fmt.Errorf("ошибка при удалении товара: %w", err)
+		return models.Product{}, err
 	}
+	prod.Dimensions = toDimensions(length, width, height)
 
-	if err = tx.Commit(ctx); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+	return prod, nil
+}
+
+func toDimensions(length, width, height *int) *models.Dimensions {
+	if length == nil || width == nil || height == nil {
+		return nil
 	}
 
-	return nil
+	return &models.Dimensions{LengthMM: *length, WidthMM: *width, HeightMM: *height}
 }
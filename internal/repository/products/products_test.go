@@ -13,6 +13,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // ----------------------
@@ -59,6 +60,8 @@ func (r *fakeRow) Scan(dest ...interface{}) error {
 			*d = v.(uuid.UUID)
 		case *time.Time:
 			*d = v.(time.Time)
+		case *string:
+			*d = v.(string)
 		default:
 			return errors.New("неподдерживаемый тип в fakeRow.Scan")
 		}
@@ -95,7 +98,7 @@ func TestCreateProductTransactional_NoActiveReception(t *testing.T) {
 		AnyTimes()
 
 	repo := NewProductRepositoryPg(mockDB)
-	_, err := repo.CreateProductTransactional(ctx, pvzID, productType)
+	_, err := repo.CreateProductTransactional(ctx, pvzID, models.Product{Type: productType})
 	if err == nil {
 		t.Fatal("ожидалась ошибка при отсутствии активной приёмки")
 	}
@@ -153,12 +156,18 @@ func TestDeleteLastProductTransactional_NoProduct(t *testing.T) {
 
 	// Возвращаем активную приёмку.
 	mockTx.EXPECT().
-		QueryRow(ctx, gomock.Any(), gomock.Any()).
+		QueryRow(ctx, Contains("FROM receiving"), pvzID).
 		Return(&fakeRow{
 			values: []interface{}{recID},
 		}).
 		Times(1)
 
+	// Товаров в приёмке нет.
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FROM goods"), recID).
+		Return(&fakeRow{err: pgx.ErrNoRows}).
+		Times(1)
+
 	mockTx.EXPECT().
 		Rollback(ctx).
 		Return(pgx.ErrTxClosed).
@@ -170,3 +179,314 @@ func TestDeleteLastProductTransactional_NoProduct(t *testing.T) {
 		t.Fatal("ожидалась ошибка при отсутствии товара для удаления")
 	}
 }
+
+// TestCreateProductTransactional_DuplicateBarcode проверяет отказ при повторе штрихкода в открытой приёмке.
+func TestCreateProductTransactional_DuplicateBarcode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.New()
+	barcode := "4601234567890"
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FROM receiving"), pvzID).
+		Return(&fakeRow{
+			values: []interface{}{"rec-uuid", time.Now(), pvzID.String(), "in_progress"},
+		}).
+		Times(1)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("g.barcode = ANY($1)"), []string{barcode}).
+		Return(&fakeRow{
+			values: []interface{}{barcode},
+		}).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+
+	repo := NewProductRepositoryPg(mockDB)
+	_, err := repo.CreateProductTransactional(ctx, pvzID, models.Product{Type: models.TypeShoes, Barcode: &barcode})
+	if !errors.Is(err, models.ErrDuplicateBarcode) {
+		t.Fatalf("ожидалась ошибка ErrDuplicateBarcode, получено %v", err)
+	}
+}
+
+// TestGetByBarcode_NotFound проверяет, что отсутствие товара возвращает ErrProductNotFound.
+func TestGetByBarcode_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+
+	ctx := context.Background()
+
+	mockDB.EXPECT().
+		QueryRow(ctx, Contains("WHERE g.barcode = $1"), "000").
+		Return(&fakeRow{err: pgx.ErrNoRows}).
+		Times(1)
+
+	repo := NewProductRepositoryPg(mockDB)
+	_, err := repo.GetByBarcode(ctx, "000")
+	if !errors.Is(err, models.ErrProductNotFound) {
+		t.Fatalf("ожидалась ошибка ErrProductNotFound, получено %v", err)
+	}
+}
+
+// TestDeleteProductTransactional_ReceptionClosed проверяет запрет удаления товара из закрытой приёмки.
+func TestDeleteProductTransactional_ReceptionClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.NewString()
+	productID := uuid.NewString()
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FOR UPDATE"), productID, pvzID).
+		Return(&fakeRow{
+			values: []interface{}{"rec-uuid", string(models.StatusClose)},
+		}).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+
+	repo := NewProductRepositoryPg(mockDB)
+	err := repo.DeleteProductTransactional(ctx, pvzID, productID)
+	if !errors.Is(err, models.ErrReceptionClosed) {
+		t.Fatalf("ожидалась ошибка ErrReceptionClosed, получено %v", err)
+	}
+}
+
+// TestDeleteProductTransactional_Success проверяет удаление товара из открытой приёмки.
+func TestDeleteProductTransactional_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.NewString()
+	productID := uuid.NewString()
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FOR UPDATE"), productID, pvzID).
+		Return(&fakeRow{
+			values: []interface{}{"rec-uuid", string(models.StatusInProgress)},
+		}).
+		Times(1)
+
+	mockTx.EXPECT().
+		Exec(ctx, Contains("DELETE FROM goods"), productID).
+		Return(pgconn.NewCommandTag("DELETE 1"), nil).
+		Times(1)
+
+	mockTx.EXPECT().
+		Commit(ctx).
+		Return(nil).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+
+	repo := NewProductRepositoryPg(mockDB)
+	if err := repo.DeleteProductTransactional(ctx, pvzID, productID); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+// TestCreateProductsBatchTransactional_DuplicateInBatch проверяет отказ всей пачки при повторе штрихкода внутри неё.
+func TestCreateProductsBatchTransactional_DuplicateInBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.New()
+	barcode := "4601234567890"
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FROM receiving"), pvzID).
+		Return(&fakeRow{
+			values: []interface{}{"rec-uuid", time.Now(), pvzID.String(), "in_progress"},
+		}).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+
+	repo := NewProductRepositoryPg(mockDB)
+	_, err := repo.CreateProductsBatchTransactional(ctx, pvzID, []models.Product{
+		{Type: models.TypeShoes},
+		{Type: models.TypeShoes, Barcode: &barcode},
+		{Type: models.TypeClothes, Barcode: &barcode},
+	})
+
+	var itemErr *models.BatchItemError
+	if !errors.As(err, &itemErr) {
+		t.Fatalf("ожидалась ошибка BatchItemError, получено %v", err)
+	}
+	if itemErr.Index != 2 || !errors.Is(err, models.ErrDuplicateBarcode) {
+		t.Fatalf("ожидался повтор штрихкода в элементе 2, получено %v", err)
+	}
+}
+
+// TestCreateProductsBatchTransactional_InsertError проверяет, что ошибка вставки указывает на элемент и откатывает пачку.
+func TestCreateProductsBatchTransactional_InsertError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.New()
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FROM receiving"), pvzID).
+		Return(&fakeRow{
+			values: []interface{}{"rec-uuid", time.Now(), pvzID.String(), "in_progress"},
+		}).
+		Times(1)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("INSERT INTO goods"), gomock.Any()).
+		Return(&fakeRow{err: errors.New("insert failed")}).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(nil).
+		Times(1)
+
+	repo := NewProductRepositoryPg(mockDB)
+	_, err := repo.CreateProductsBatchTransactional(ctx, pvzID, []models.Product{
+		{Type: models.TypeShoes},
+		{Type: models.TypeClothes},
+	})
+
+	var itemErr *models.BatchItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 0 {
+		t.Fatalf("ожидалась ошибка первого элемента, получено %v", err)
+	}
+}
+
+// TestDeleteProductTransactional_RetriesSerializationFailure проверяет повтор транзакции после ошибки 40001.
+func TestDeleteProductTransactional_RetriesSerializationFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.NewString()
+	productID := uuid.NewString()
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil).
+		Times(2)
+
+	gomock.InOrder(
+		mockTx.EXPECT().
+			QueryRow(ctx, Contains("FOR UPDATE"), productID, pvzID).
+			Return(&fakeRow{err: &pgconn.PgError{Code: "40001"}}),
+		mockTx.EXPECT().
+			QueryRow(ctx, Contains("FOR UPDATE"), productID, pvzID).
+			Return(&fakeRow{
+				values: []interface{}{"rec-uuid", string(models.StatusInProgress)},
+			}),
+	)
+
+	mockTx.EXPECT().
+		Exec(ctx, Contains("DELETE FROM goods"), productID).
+		Return(pgconn.NewCommandTag("DELETE 1"), nil).
+		Times(1)
+
+	mockTx.EXPECT().
+		Commit(ctx).
+		Return(nil).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+
+	repo := NewProductRepositoryPg(mockDB)
+	if err := repo.DeleteProductTransactional(ctx, pvzID, productID); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+// TestDeleteLastProductTransactional_NoRowsMapsToSentinel проверяет, что отсутствие приёмки возвращается как ErrNoActiveReception.
+func TestDeleteLastProductTransactional_NoRowsMapsToSentinel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	ctx := context.Background()
+	pvzID := uuid.NewString()
+
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FROM receiving"), pvzID).
+		Return(&fakeRow{err: pgx.ErrNoRows}).
+		Times(1)
+
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(nil).
+		Times(1)
+
+	repo := NewProductRepositoryPg(mockDB)
+	err := repo.DeleteLastProductTransactional(ctx, pvzID)
+	if !errors.Is(err, models.ErrNoActiveReception) {
+		t.Fatalf("ожидалась ошибка ErrNoActiveReception, получено %v", err)
+	}
+}
@@ -2,19 +2,26 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+
 	"AvitoPVZ/internal/models"
 	"github.com/google/uuid"
 )
 
+type pool interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type PvzRepositoryPostgres struct {
-	pool *pgxpool.Pool
+	pool pool
 }
 
-func NewPVZRepositoryPostgres(db *pgxpool.Pool) *PvzRepositoryPostgres {
+func NewPVZRepositoryPostgres(db pool) *PvzRepositoryPostgres {
 	return &PvzRepositoryPostgres{pool: db}
 }
 
@@ -25,26 +32,167 @@ func (r *PvzRepositoryPostgres) Create(ctx context.Context, city models.PVZCity)
 	query := `
         INSERT INTO pickup_point (id, registration_date, city)
         VALUES ($1, $2, $3)
-        RETURNING id, registration_date, city
+        RETURNING id, registration_date, city, deactivated_at
     `
 
-	var pvz models.PVZ
-	err := r.pool.QueryRow(ctx, query, id, registrationDate, city).
-		Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City)
+	pvz, err := scanPVZ(r.pool.QueryRow(ctx, query, id, registrationDate, city))
 	if err != nil {
 		return models.PVZ{}, fmt.Errorf("failed to insert PVZ: %w", err)
 	}
 
 	return pvz, nil
 }
+
+func (r *PvzRepositoryPostgres) GetByID(ctx context.Context, id string) (models.PVZ, error) {
+	query := `SELECT id, registration_date, city, deactivated_at FROM pickup_point WHERE id = $1`
+
+	pvz, err := scanPVZ(r.pool.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.PVZ{}, models.ErrPVZNotFound
+	} else if err != nil {
+		return models.PVZ{}, fmt.Errorf("failed to get PVZ: %w", err)
+	}
+
+	return pvz, nil
+}
+
+func (r *PvzRepositoryPostgres) UpdateCity(ctx context.Context, id string, city models.PVZCity) (models.PVZ, error) {
+	query := `
+        UPDATE pickup_point
+        SET city = $2
+        WHERE id = $1
+        RETURNING id, registration_date, city, deactivated_at
+    `
+
+	pvz, err := scanPVZ(r.pool.QueryRow(ctx, query, id, city))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.PVZ{}, models.ErrPVZNotFound
+	} else if err != nil {
+		return models.PVZ{}, fmt.Errorf("failed to update PVZ: %w", err)
+	}
+
+	return pvz, nil
+}
+
+// Deactivate - мягкое удаление ПВЗ: запись остаётся в истории, но новые приёмки по нему не создаются.
+// Повторная деактивация не меняет исходную дату.
+func (r *PvzRepositoryPostgres) Deactivate(ctx context.Context, id string) (models.PVZ, error) {
+	query := `
+        UPDATE pickup_point
+        SET deactivated_at = COALESCE(deactivated_at, $2)
+        WHERE id = $1
+        RETURNING id, registration_date, city, deactivated_at
+    `
+
+	pvz, err := scanPVZ(r.pool.QueryRow(ctx, query, id, time.Now()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.PVZ{}, models.ErrPVZNotFound
+	} else if err != nil {
+		return models.PVZ{}, fmt.Errorf("failed to deactivate PVZ: %w", err)
+	}
+
+	return pvz, nil
+}
+
+func scanPVZ(row pgx.Row) (models.PVZ, error) {
+	var pvz models.PVZ
+	if err := row.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City, &pvz.DeactivatedAt); err != nil {
+		return models.PVZ{}, err
+	}
+	pvz.Active = pvz.DeactivatedAt == nil
+
+	return pvz, nil
+}
+
+// GetPVZData - выборка страницы ПВЗ вместе с приёмками и товарами.
+// Количество запросов не зависит от объёма данных: ПВЗ, приёмки и товары
+// загружаются тремя запросами, приёмки и товары - пачками через = ANY($1).
 func (r *PvzRepositoryPostgres) GetPVZData(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]models.PVZData, error) {
-	var pvzList []models.PVZ
+	pvzList, err := r.getPVZPage(ctx, startDate, endDate, nil, (page-1)*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.loadPVZData(ctx, pvzList, startDate, endDate)
+}
+
+// GetPVZDataByCursor - keyset-пагинация по (registration_date, id): выборка ПВЗ строго после cursor.
+// Возвращает курсор следующей страницы или nil, если страница последняя.
+func (r *PvzRepositoryPostgres) GetPVZDataByCursor(ctx context.Context, startDate, endDate *time.Time, cursor *models.PVZCursor, limit int) ([]models.PVZData, *models.PVZCursor, error) {
+	pvzList, err := r.getPVZPage(ctx, startDate, endDate, cursor, 0, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var next *models.PVZCursor
+	if len(pvzList) == limit {
+		last := pvzList[len(pvzList)-1]
+		next = &models.PVZCursor{RegistrationDate: last.RegistrationDate, ID: last.ID}
+	}
+
+	data, err := r.loadPVZData(ctx, pvzList, startDate, endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, next, nil
+}
+
+func (r *PvzRepositoryPostgres) loadPVZData(ctx context.Context, pvzList []models.PVZ, startDate, endDate *time.Time) ([]models.PVZData, error) {
+	if len(pvzList) == 0 {
+		return []models.PVZData{}, nil
+	}
+
+	pvzIDs := make([]string, 0, len(pvzList))
+	for _, p := range pvzList {
+		pvzIDs = append(pvzIDs, p.ID)
+	}
+
+	receptions, err := r.getReceptions(ctx, pvzIDs, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	recIDs := make([]string, 0, len(receptions))
+	for _, rec := range receptions {
+		recIDs = append(recIDs, rec.ID.String())
+	}
+
+	productsByReception, err := r.getProducts(ctx, recIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	receptionsByPVZ := make(map[string][]models.ReceptionData, len(pvzList))
+	for _, rec := range receptions {
+		pvzID := rec.PvzID.String()
+		receptionsByPVZ[pvzID] = append(receptionsByPVZ[pvzID], models.ReceptionData{
+			Reception: rec,
+			Products:  productsByReception[rec.ID],
+		})
+	}
+
+	var results []models.PVZData
+	for _, p := range pvzList {
+		recDataList := receptionsByPVZ[p.ID]
+		if len(recDataList) > 0 {
+			results = append(results, models.PVZData{
+				PVZ:        p,
+				Receptions: recDataList,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func (r *PvzRepositoryPostgres) getPVZPage(ctx context.Context, startDate, endDate *time.Time, after *models.PVZCursor, offset, limit int) ([]models.PVZ, error) {
 	var args []interface{}
-	query := ""
 	argIdx := 1
 
+	query := `SELECT p.id, p.registration_date, p.city, p.deactivated_at FROM pickup_point p WHERE 1=1`
 	if startDate != nil || endDate != nil {
-		query = `SELECT DISTINCT p.id, p.registration_date, p.city
+		query = `SELECT DISTINCT p.id, p.registration_date, p.city, p.deactivated_at
 			FROM pickup_point p
 			JOIN receiving r ON p.id = r.pickup_point_id
 			WHERE 1=1`
@@ -58,12 +206,14 @@ func (r *PvzRepositoryPostgres) GetPVZData(ctx context.Context, startDate, endDa
 			args = append(args, *endDate)
 			argIdx++
 		}
-		query += fmt.Sprintf(" ORDER BY p.registration_date LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-		args = append(args, limit, (page-1)*limit)
-	} else {
-		query = fmt.Sprintf(`SELECT id, registration_date, city FROM pickup_point ORDER BY registration_date LIMIT $1 OFFSET $2`)
-		args = append(args, limit, (page-1)*limit)
 	}
+	if after != nil {
+		query += fmt.Sprintf(" AND (p.registration_date, p.id) > ($%d, $%d)", argIdx, argIdx+1)
+		args = append(args, after.RegistrationDate, after.ID)
+		argIdx += 2
+	}
+	query += fmt.Sprintf(" ORDER BY p.registration_date, p.id LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, limit, offset)
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -71,82 +221,81 @@ func (r *PvzRepositoryPostgres) GetPVZData(ctx context.Context, startDate, endDa
 	}
 	defer rows.Close()
 
+	var pvzList []models.PVZ
 	for rows.Next() {
-		var p models.PVZ
-		if err := rows.Scan(&p.ID, &p.RegistrationDate, &p.City); err != nil {
+		p, err := scanPVZ(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan pvz: %w", err)
 		}
 		pvzList = append(pvzList, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read pvz: %w", err)
+	}
 
-	if len(pvzList) == 0 {
-		return []models.PVZData{}, nil
+	return pvzList, nil
+}
+
+func (r *PvzRepositoryPostgres) getReceptions(ctx context.Context, pvzIDs []string, startDate, endDate *time.Time) ([]models.Reception, error) {
+	query := `SELECT id, receiving_datetime, pickup_point_id, status FROM receiving WHERE pickup_point_id = ANY($1)`
+	args := []interface{}{pvzIDs}
+	argIdx := 2
+	if startDate != nil {
+		query += fmt.Sprintf(" AND receiving_datetime >= $%d", argIdx)
+		args = append(args, *startDate)
+		argIdx++
+	}
+	if endDate != nil {
+		query += fmt.Sprintf(" AND receiving_datetime <= $%d", argIdx)
+		args = append(args, *endDate)
+		argIdx++
 	}
+	query += " ORDER BY receiving_datetime"
 
-	var results []models.PVZData
-	for _, p := range pvzList {
-		recvQuery := `SELECT id, receiving_datetime, pickup_point_id, status FROM receiving WHERE pickup_point_id = $1`
-		recvArgs := []interface{}{p.ID}
-		argPosition := 2
-		if startDate != nil {
-			recvQuery += fmt.Sprintf(" AND receiving_datetime >= $%d", argPosition)
-			recvArgs = append(recvArgs, *startDate)
-			argPosition++
-		}
-		if endDate != nil {
-			recvQuery += fmt.Sprintf(" AND receiving_datetime <= $%d", argPosition)
-			recvArgs = append(recvArgs, *endDate)
-			argPosition++
-		}
-		recvQuery += " ORDER BY receiving_datetime"
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query receptions: %w", err)
+	}
+	defer rows.Close()
 
-		recvRows, err := r.pool.Query(ctx, recvQuery, recvArgs...)
-		if err != nil {
-			return nil, fmt.Errorf("query receptions: %w", err)
+	var receptions []models.Reception
+	for rows.Next() {
+		var rec models.Reception
+		if err := rows.Scan(&rec.ID, &rec.DateTime, &rec.PvzID, &rec.Status); err != nil {
+			return nil, fmt.Errorf("scan reception: %w", err)
 		}
+		receptions = append(receptions, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read receptions: %w", err)
+	}
 
-		var recDataList []models.ReceptionData
-		for recvRows.Next() {
-			var rec models.Reception
-			if err := recvRows.Scan(&rec.ID, &rec.DateTime, &rec.PvzID, &rec.Status); err != nil {
-				recvRows.Close()
-				return nil, fmt.Errorf("scan reception: %w", err)
-			}
-
-			prodQuery := `SELECT id, accepted_datetime, product_type, receiving_id FROM goods WHERE receiving_id = $1 ORDER BY accepted_datetime`
-			prodRows, err := r.pool.Query(ctx, prodQuery, rec.ID)
-			if err != nil {
-				recvRows.Close()
-				return nil, fmt.Errorf("query products: %w", err)
-			}
-			var products []models.Product
-			for prodRows.Next() {
-				var prod models.Product
-				if err := prodRows.Scan(&prod.ID, &prod.DateTime, &prod.Type, &prod.ReceptionID); err != nil {
-					prodRows.Close()
-					recvRows.Close()
-					return nil, fmt.Errorf("scan product: %w", err)
-				}
-				products = append(products, prod)
-			}
-			prodRows.Close()
-
-			recData := models.ReceptionData{
-				Reception: rec,
-				Products:  products,
-			}
-			recDataList = append(recDataList, recData)
-		}
-		recvRows.Close()
+	return receptions, nil
+}
 
-		if len(recDataList) > 0 {
-			pvzData := models.PVZData{
-				PVZ:        p,
-				Receptions: recDataList,
-			}
-			results = append(results, pvzData)
+func (r *PvzRepositoryPostgres) getProducts(ctx context.Context, recIDs []string) (map[uuid.UUID][]models.Product, error) {
+	result := make(map[uuid.UUID][]models.Product, len(recIDs))
+	if len(recIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT id, accepted_datetime, product_type, receiving_id FROM goods WHERE receiving_id = ANY($1) ORDER BY accepted_datetime`
+	rows, err := r.pool.Query(ctx, query, recIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prod models.Product
+		if err := rows.Scan(&prod.ID, &prod.DateTime, &prod.Type, &prod.ReceptionID); err != nil {
+			return nil, fmt.Errorf("scan product: %w", err)
 		}
+		result[prod.ReceptionID] = append(result[prod.ReceptionID], prod)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read products: %w", err)
 	}
 
-	return results, nil
+	return result, nil
 }
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/models"
+)
+
+// ----------------------
+// fakeRows для эмуляции результатов вызовов Query
+// ----------------------
+type fakeRows struct {
+	values [][]interface{}
+	idx    int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return r.values[r.idx-1], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.idx >= len(r.values) {
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.values[r.idx-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("ожидалось %d аргументов для Scan, получено %d", len(row), len(dest))
+	}
+	for i, v := range row {
+		switch d := dest[i].(type) {
+		case *string:
+			*d = v.(string)
+		case *uuid.UUID:
+			*d = v.(uuid.UUID)
+		case *time.Time:
+			*d = v.(time.Time)
+		case **time.Time:
+			*d = v.(*time.Time)
+		case *models.StatusReception:
+			*d = v.(models.StatusReception)
+		case *models.TypeProduct:
+			*d = v.(models.TypeProduct)
+		default:
+			return errors.New("неподдерживаемый тип в fakeRows.Scan")
+		}
+	}
+	return nil
+}
+
+// ----------------------
+// fakePool хранит набор ПВЗ, приёмок и товаров и считает количество запросов
+// ----------------------
+type fakePool struct {
+	pvz        []models.PVZ
+	receptions []models.Reception
+	products   []models.Product
+	queries    int
+
+	lastPVZQuery string
+	lastPVZArgs  []any
+
+	row pgx.Row
+}
+
+func newFakePool(pvzCount, receptionsPerPVZ, productsPerReception int) *fakePool {
+	p := &fakePool{}
+	now := time.Now()
+	for i := 0; i < pvzCount; i++ {
+		pvz := models.PVZ{ID: uuid.NewString(), RegistrationDate: now, City: string(models.CityMoscow)}
+		p.pvz = append(p.pvz, pvz)
+		for j := 0; j < receptionsPerPVZ; j++ {
+			rec := models.Reception{
+				ID:       uuid.New(),
+				DateTime: now,
+				PvzID:    uuid.MustParse(pvz.ID),
+				Status:   models.StatusClose,
+			}
+			p.receptions = append(p.receptions, rec)
+			for k := 0; k < productsPerReception; k++ {
+				p.products = append(p.products, models.Product{
+					ID:          uuid.New(),
+					DateTime:    now,
+					Type:        models.TypeShoes,
+					ReceptionID: rec.ID,
+				})
+			}
+		}
+	}
+	return p
+}
+
+func (p *fakePool) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	p.queries++
+
+	rows := &fakeRows{}
+	switch {
+	case strings.Contains(sql, "FROM pickup_point"):
+		p.lastPVZQuery, p.lastPVZArgs = sql, args
+		limit, offset := args[len(args)-2].(int), args[len(args)-1].(int)
+		page := p.pvz[min(offset, len(p.pvz)):min(offset+limit, len(p.pvz))]
+		for _, pvz := range page {
+			rows.values = append(rows.values, []interface{}{pvz.ID, pvz.RegistrationDate, pvz.City, pvz.DeactivatedAt})
+		}
+	case strings.Contains(sql, "FROM receiving"):
+		ids := toSet(args[0].([]string))
+		for _, rec := range p.receptions {
+			if ids[rec.PvzID.String()] {
+				rows.values = append(rows.values, []interface{}{rec.ID, rec.DateTime, rec.PvzID, rec.Status})
+			}
+		}
+	case strings.Contains(sql, "FROM goods"):
+		ids := toSet(args[0].([]string))
+		for _, prod := range p.products {
+			if ids[prod.ReceptionID.String()] {
+				rows.values = append(rows.values, []interface{}{prod.ID, prod.DateTime, prod.Type, prod.ReceptionID})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("неожиданный запрос: %s", sql)
+	}
+
+	return rows, nil
+}
+
+func (p *fakePool) QueryRow(_ context.Context, _ string, _ ...any) pgx.Row {
+	p.queries++
+	return p.row
+}
+
+// ----------------------
+// fakeRow для эмуляции результатов вызовов QueryRow
+// ----------------------
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r *fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	rows := &fakeRows{values: [][]interface{}{r.values}, idx: 1}
+	return rows.Scan(dest...)
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// TestGetPVZData_GroupsNestedData проверяет, что приёмки и товары раскладываются по своим ПВЗ.
+func TestGetPVZData_GroupsNestedData(t *testing.T) {
+	db := newFakePool(3, 2, 4)
+	repo := NewPVZRepositoryPostgres(db)
+
+	data, err := repo.GetPVZData(context.Background(), nil, nil, 1, 10)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if len(data) != 3 {
+		t.Fatalf("ожидалось 3 ПВЗ, получено %d", len(data))
+	}
+	for _, item := range data {
+		if len(item.Receptions) != 2 {
+			t.Fatalf("ожидалось 2 приёмки у ПВЗ %s, получено %d", item.PVZ.ID, len(item.Receptions))
+		}
+		for _, rec := range item.Receptions {
+			if rec.Reception.PvzID.String() != item.PVZ.ID {
+				t.Fatalf("приёмка %s попала в чужой ПВЗ", rec.Reception.ID)
+			}
+			if len(rec.Products) != 4 {
+				t.Fatalf("ожидалось 4 товара в приёмке %s, получено %d", rec.Reception.ID, len(rec.Products))
+			}
+		}
+	}
+}
+
+// TestGetPVZData_SkipsPVZWithoutReceptions проверяет, что ПВЗ без приёмок не попадают в ответ.
+func TestGetPVZData_SkipsPVZWithoutReceptions(t *testing.T) {
+	db := newFakePool(2, 0, 0)
+	repo := NewPVZRepositoryPostgres(db)
+
+	data, err := repo.GetPVZData(context.Background(), nil, nil, 1, 10)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(data) != 0 {
+		t.Fatalf("ожидался пустой ответ, получено %d ПВЗ", len(data))
+	}
+	if db.queries != 2 {
+		t.Fatalf("ожидалось 2 запроса, выполнено %d", db.queries)
+	}
+}
+
+// TestGetPVZDataByCursor проверяет keyset-условие и формирование курсора следующей страницы.
+func TestGetPVZDataByCursor(t *testing.T) {
+	db := newFakePool(3, 1, 1)
+	repo := NewPVZRepositoryPostgres(db)
+	after := &models.PVZCursor{RegistrationDate: time.Now(), ID: uuid.NewString()}
+
+	data, next, err := repo.GetPVZDataByCursor(context.Background(), nil, nil, after, 2)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("ожидалось 2 ПВЗ, получено %d", len(data))
+	}
+	if !strings.Contains(db.lastPVZQuery, "(p.registration_date, p.id) > ($1, $2)") {
+		t.Fatalf("в запросе нет keyset-условия: %s", db.lastPVZQuery)
+	}
+	if db.lastPVZArgs[1] != after.ID {
+		t.Fatalf("в запрос передан неверный курсор: %v", db.lastPVZArgs)
+	}
+	if next == nil || next.ID != data[1].PVZ.ID {
+		t.Fatalf("ожидался курсор на последний ПВЗ страницы, получено %v", next)
+	}
+
+	_, next, err = repo.GetPVZDataByCursor(context.Background(), nil, nil, nil, 5)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if next != nil {
+		t.Fatalf("на последней странице курсор должен быть пустым, получено %v", next)
+	}
+}
+
+// TestGetByID_NotFound проверяет, что отсутствие ПВЗ возвращается как models.ErrPVZNotFound.
+func TestGetByID_NotFound(t *testing.T) {
+	db := &fakePool{row: &fakeRow{err: pgx.ErrNoRows}}
+	repo := NewPVZRepositoryPostgres(db)
+
+	_, err := repo.GetByID(context.Background(), uuid.NewString())
+	if !errors.Is(err, models.ErrPVZNotFound) {
+		t.Fatalf("ожидалась ошибка %v, получено %v", models.ErrPVZNotFound, err)
+	}
+}
+
+// TestDeactivate проверяет, что деактивированный ПВЗ помечается неактивным.
+func TestDeactivate(t *testing.T) {
+	now := time.Now()
+	id := uuid.NewString()
+	db := &fakePool{row: &fakeRow{values: []interface{}{id, now, string(models.CityKazan), &now}}}
+	repo := NewPVZRepositoryPostgres(db)
+
+	pvz, err := repo.Deactivate(context.Background(), id)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if pvz.Active || pvz.DeactivatedAt == nil {
+		t.Fatalf("ПВЗ должен быть деактивирован: %+v", pvz)
+	}
+}
+
+// BenchmarkGetPVZData_QueryCount показывает, что число запросов к БД
+// не зависит от количества ПВЗ, приёмок и товаров.
+func BenchmarkGetPVZData_QueryCount(b *testing.B) {
+	cases := []struct {
+		pvz, receptions, products int
+	}{
+		{1, 1, 1},
+		{10, 5, 10},
+		{30, 30, 50},
+	}
+
+	for _, tc := range cases {
+		b.Run(fmt.Sprintf("pvz=%d/receptions=%d/products=%d", tc.pvz, tc.receptions, tc.products), func(b *testing.B) {
+			db := newFakePool(tc.pvz, tc.receptions, tc.products)
+			repo := NewPVZRepositoryPostgres(db)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetPVZData(ctx, nil, nil, 1, tc.pvz); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			queriesPerOp := float64(db.queries) / float64(b.N)
+			if queriesPerOp != 3 {
+				b.Fatalf("ожидалось 3 запроса на вызов, выполнено %.1f", queriesPerOp)
+			}
+			b.ReportMetric(queriesPerOp, "queries/op")
+		})
+	}
+}
@@ -8,100 +8,247 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/transaction"
 )
 
+type pool interface {
+	transaction.Beginner
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type ReceptionRepositoryPg struct {
-	Pool *pgxpool.Pool
+	pool pool
 }
 
-func NewReceptionRepositoryPg(pool *pgxpool.Pool) *ReceptionRepositoryPg {
-	return &ReceptionRepositoryPg{Pool: pool}
+func NewReceptionRepositoryPg(db pool) *ReceptionRepositoryPg {
+	return &ReceptionRepositoryPg{pool: db}
 }
 
-func (r *ReceptionRepositoryPg) CreateReceptionTransactional(ctx context.Context, pvzID uuid.UUID) (models.Reception, error) {
-	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
-	if err != nil {
-		return models.Reception{}, fmt.Errorf("begin transaction: %w", err)
-	}
-	defer func(tx pgx.Tx, ctx context.Context) {
-		_ = tx.Rollback(ctx)
-	}(tx, ctx)
+// CreateReceptionTransactional открывает приёмку и возвращает её вместе с городом ПВЗ - меткой доменных метрик
+func (r *ReceptionRepositoryPg) CreateReceptionTransactional(ctx context.Context, pvzID uuid.UUID) (models.Reception, models.PVZCity, error) {
+	var (
+		newRec models.Reception
+		city   models.PVZCity
+	)
+	err := transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		var deactivatedAt *time.Time
+		queryPVZ := `
+  SELECT deactivated_at, city
+  FROM pickup_point
+  WHERE id = $1
+  FOR SHARE
+ `
+		err := tx.QueryRow(ctx, queryPVZ, pvzID).Scan(&deactivatedAt, &city)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrPVZNotFound
+		} else if err != nil {
+			return fmt.Errorf("query pvz: %w", err)
+		}
+		if deactivatedAt != nil {
+			return models.ErrPVZDeactivated
+		}
 
-	var active models.Reception
-	querySelect := `
+		var active models.Reception
+		querySelect := `
   SELECT id, receiving_datetime, pickup_point_id, status
   FROM receiving
   WHERE pickup_point_id = $1 AND status = 'in_progress'
   FOR UPDATE
  `
-	err = tx.QueryRow(ctx, querySelect, pvzID).Scan(&active.ID, &active.DateTime, &active.PvzID, &active.Status)
-	if err == nil {
-		return models.Reception{}, fmt.Errorf("active reception already exists")
-	} else if !errors.Is(err, pgx.ErrNoRows) {
-		return models.Reception{}, fmt.Errorf("query active reception: %w", err)
-	}
+		err = tx.QueryRow(ctx, querySelect, pvzID).Scan(&active.ID, &active.DateTime, &active.PvzID, &active.Status)
+		if err == nil {
+			return models.ErrReceptionAlreadyOpen
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("query active reception: %w", err)
+		}
 
-	id := uuid.NewString()
-	now := time.Now()
-	insertQuery := `
+		id := uuid.NewString()
+		now := time.Now()
+		insertQuery := `
   INSERT INTO receiving (id, receiving_datetime, pickup_point_id, status)
   VALUES ($1, $2, $3, $4)
   RETURNING id, receiving_datetime, pickup_point_id, status
  `
-	var newRec models.Reception
-	err = tx.QueryRow(ctx, insertQuery, id, now, pvzID, "in_progress").
-		Scan(&newRec.ID, &newRec.DateTime, &newRec.PvzID, &newRec.Status)
-	if err != nil {
-		return models.Reception{}, fmt.Errorf("insert reception: %w", err)
-	}
+		err = tx.QueryRow(ctx, insertQuery, id, now, pvzID, "in_progress").
+			Scan(&newRec.ID, &newRec.DateTime, &newRec.PvzID, &newRec.Status)
+		if err != nil {
+			return fmt.Errorf("insert reception: %w", err)
+		}
 
-	if err = tx.Commit(ctx); err != nil {
-		return models.Reception{}, fmt.Errorf("commit transaction: %w", err)
+		return nil
+	})
+	if err != nil {
+		return models.Reception{}, "", err
 	}
 
-	return newRec, nil
+	return newRec, city, nil
 }
 
-func (r *ReceptionRepositoryPg) CloseLastReceptionTransactional(ctx context.Context, pvzID string) (models.Reception, error) {
-	tx, err := r.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+// CloseLastReceptionTransactional закрывает открытую приёмку и возвращает её вместе с городом ПВЗ
+func (r *ReceptionRepositoryPg) CloseLastReceptionTransactional(ctx context.Context, pvzID string) (models.Reception, models.PVZCity, error) {
+	var (
+		updatedRec models.Reception
+		city       models.PVZCity
+	)
+	err := transaction.Serializable(ctx, r.pool, func(tx pgx.Tx) error {
+		queryReception := `
+			SELECT id, receiving_datetime, pickup_point_id, status
+			FROM receiving
+			WHERE pickup_point_id = $1 AND status = 'in_progress'
+			ORDER BY receiving_datetime DESC
+			LIMIT 1
+			FOR UPDATE
+		`
+		var rec models.Reception
+		err := tx.QueryRow(ctx, queryReception, pvzID).Scan(&rec.ID, &rec.DateTime, &rec.PvzID, &rec.Status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrNoActiveReception
+		} else if err != nil {
+			return fmt.Errorf("query active reception: %w", err)
+		}
+
+		updateQuery := `
+			UPDATE receiving r
+			SET status = 'close'
+			FROM pickup_point p
+			WHERE r.id = $1 AND p.id = r.pickup_point_id
+			RETURNING r.id, r.receiving_datetime, r.pickup_point_id, r.status, p.city
+		`
+		err = tx.QueryRow(ctx, updateQuery, rec.ID).
+			Scan(&updatedRec.ID, &updatedRec.DateTime, &updatedRec.PvzID, &updatedRec.Status, &city)
+		if err != nil {
+			return fmt.Errorf("невозможно закрыть приемку: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return models.Reception{}, fmt.Errorf("begin transaction: %w", err)
+		return models.Reception{}, "", err
 	}
-	defer tx.Rollback(ctx)
 
-	queryReception := `
+	return updatedRec, city, nil
+}
+
+func (r *ReceptionRepositoryPg) GetReception(ctx context.Context, id uuid.UUID) (models.ReceptionDetails, error) {
+	query := `
 		SELECT id, receiving_datetime, pickup_point_id, status
 		FROM receiving
-		WHERE pickup_point_id = $1 AND status = 'in_progress'
-		ORDER BY receiving_datetime DESC
-		LIMIT 1
-		FOR UPDATE
+		WHERE id = $1
 	`
 	var rec models.Reception
-	err = tx.QueryRow(ctx, queryReception, pvzID).Scan(&rec.ID, &rec.DateTime, &rec.PvzID, &rec.Status)
+	err := r.pool.QueryRow(ctx, query, id).Scan(&rec.ID, &rec.DateTime, &rec.PvzID, &rec.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ReceptionDetails{}, models.ErrReceptionNotFound
+	} else if err != nil {
+		return models.ReceptionDetails{}, fmt.Errorf("query reception: %w", err)
+	}
+
+	details, err := r.withProducts(ctx, []models.Reception{rec})
 	if err != nil {
-		return models.Reception{}, fmt.Errorf("активная приемка не найдена для pvzID=%s: %w", pvzID, err)
+		return models.ReceptionDetails{}, err
 	}
 
-	updateQuery := `
-		UPDATE receiving
-		SET status = 'close'
-		WHERE id = $1
-		RETURNING id, receiving_datetime, pickup_point_id, status
-	`
-	var updatedRec models.Reception
-	err = tx.QueryRow(ctx, updateQuery, rec.ID).
-		Scan(&updatedRec.ID, &updatedRec.DateTime, &updatedRec.PvzID, &updatedRec.Status)
+	return details[0], nil
+}
+
+// ListReceptions - приёмки ПВЗ с фильтрами по статусу и дате, от новых к старым.
+// Товары всех приёмок страницы загружаются одним запросом.
+func (r *ReceptionRepositoryPg) ListReceptions(ctx context.Context, pvzID uuid.UUID, filter models.ReceptionFilter) ([]models.ReceptionDetails, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM pickup_point WHERE id = $1)`, pvzID).Scan(&exists)
 	if err != nil {
-		return models.Reception{}, fmt.Errorf("невозможно закрыть приемку: %w", err)
+		return nil, fmt.Errorf("query pvz: %w", err)
+	}
+	if !exists {
+		return nil, models.ErrPVZNotFound
+	}
+
+	query := `SELECT id, receiving_datetime, pickup_point_id, status FROM receiving WHERE pickup_point_id = $1`
+	args := []interface{}{pvzID}
+	argIdx := 2
+	if filter.Status != nil {
+		query += fmt.Sprintf(" AND status = $%d", argIdx)
+		args = append(args, *filter.Status)
+		argIdx++
+	}
+	if filter.StartDate != nil {
+		query += fmt.Sprintf(" AND receiving_datetime >= $%d", argIdx)
+		args = append(args, *filter.StartDate)
+		argIdx++
+	}
+	if filter.EndDate != nil {
+		query += fmt.Sprintf(" AND receiving_datetime <= $%d", argIdx)
+		args = append(args, *filter.EndDate)
+		argIdx++
+	}
+	query += fmt.Sprintf(" ORDER BY receiving_datetime DESC, id LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query receptions: %w", err)
+	}
+	defer rows.Close()
+
+	var receptions []models.Reception
+	for rows.Next() {
+		var rec models.Reception
+		if err := rows.Scan(&rec.ID, &rec.DateTime, &rec.PvzID, &rec.Status); err != nil {
+			return nil, fmt.Errorf("scan reception: %w", err)
+		}
+		receptions = append(receptions, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read receptions: %w", err)
+	}
+
+	return r.withProducts(ctx, receptions)
+}
+
+func (r *ReceptionRepositoryPg) withProducts(ctx context.Context, receptions []models.Reception) ([]models.ReceptionDetails, error) {
+	details := make([]models.ReceptionDetails, 0, len(receptions))
+	if len(receptions) == 0 {
+		return details, nil
+	}
+
+	recIDs := make([]string, 0, len(receptions))
+	for _, rec := range receptions {
+		recIDs = append(recIDs, rec.ID.String())
+	}
+
+	query := `SELECT id, accepted_datetime, product_type, receiving_id FROM goods WHERE receiving_id = ANY($1) ORDER BY accepted_datetime`
+	rows, err := r.pool.Query(ctx, query, recIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query products: %w", err)
+	}
+	defer rows.Close()
+
+	productsByReception := make(map[uuid.UUID][]models.Product, len(receptions))
+	for rows.Next() {
+		var prod models.Product
+		if err := rows.Scan(&prod.ID, &prod.DateTime, &prod.Type, &prod.ReceptionID); err != nil {
+			return nil, fmt.Errorf("scan product: %w", err)
+		}
+		productsByReception[prod.ReceptionID] = append(productsByReception[prod.ReceptionID], prod)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read products: %w", err)
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return models.Reception{}, fmt.Errorf("commit transaction: %w", err)
+	for _, rec := range receptions {
+		products := productsByReception[rec.ID]
+		if products == nil {
+			products = []models.Product{}
+		}
+		details = append(details, models.ReceptionDetails{
+			Reception:    rec,
+			ProductCount: len(products),
+			Products:     products,
+		})
 	}
 
-	return updatedRec, nil
+	return details, nil
 }
@@ -0,0 +1,268 @@
+package receptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/models"
+)
+
+// ----------------------
+// fakeRows для эмуляции результатов вызовов Query
+// ----------------------
+type fakeRows struct {
+	values [][]interface{}
+	idx    int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return r.values[r.idx-1], nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.idx >= len(r.values) {
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.values[r.idx-1]
+	if len(dest) != len(row) {
+		return fmt.Errorf("ожидалось %d аргументов для Scan, получено %d", len(row), len(dest))
+	}
+	for i, v := range row {
+		switch d := dest[i].(type) {
+		case *uuid.UUID:
+			*d = v.(uuid.UUID)
+		case *time.Time:
+			*d = v.(time.Time)
+		case **time.Time:
+			*d = v.(*time.Time)
+		case *bool:
+			*d = v.(bool)
+		case *models.StatusReception:
+			*d = v.(models.StatusReception)
+		case *models.TypeProduct:
+			*d = v.(models.TypeProduct)
+		case *models.PVZCity:
+			*d = v.(models.PVZCity)
+		default:
+			return errors.New("неподдерживаемый тип в fakeRows.Scan")
+		}
+	}
+	return nil
+}
+
+// ----------------------
+// fakeRow для эмуляции результатов вызовов QueryRow
+// ----------------------
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r *fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	rows := &fakeRows{values: [][]interface{}{r.values}, idx: 1}
+	return rows.Scan(dest...)
+}
+
+// ----------------------
+// fakePool отдаёт результаты QueryRow по очереди (и в транзакции, и вне её),
+// а результаты Query - по таблице из запроса
+// ----------------------
+type fakePool struct {
+	rows       []*fakeRow
+	receptions []models.Reception
+	products   []models.Product
+	begins     int
+
+	lastQuery string
+	lastArgs  []any
+}
+
+func (p *fakePool) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	p.begins++
+	return &fakeTx{pool: p}, nil
+}
+
+func (p *fakePool) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	if len(p.rows) == 0 {
+		return &fakeRow{err: fmt.Errorf("неожиданный запрос: %s", sql)}
+	}
+	row := p.rows[0]
+	p.rows = p.rows[1:]
+	return row
+}
+
+func (p *fakePool) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows := &fakeRows{}
+	switch {
+	case strings.Contains(sql, "FROM receiving"):
+		p.lastQuery, p.lastArgs = sql, args
+		for _, rec := range p.receptions {
+			rows.values = append(rows.values, []interface{}{rec.ID, rec.DateTime, rec.PvzID, rec.Status})
+		}
+	case strings.Contains(sql, "FROM goods"):
+		ids := make(map[string]bool)
+		for _, id := range args[0].([]string) {
+			ids[id] = true
+		}
+		for _, prod := range p.products {
+			if ids[prod.ReceptionID.String()] {
+				rows.values = append(rows.values, []interface{}{prod.ID, prod.DateTime, prod.Type, prod.ReceptionID})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("неожиданный запрос: %s", sql)
+	}
+
+	return rows, nil
+}
+
+// fakeTx - транзакция fakePool; методы, которые репозиторий не вызывает, не реализованы
+type fakeTx struct {
+	pgx.Tx
+	pool *fakePool
+}
+
+func (t *fakeTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.pool.QueryRow(ctx, sql, args...)
+}
+
+func (t *fakeTx) Commit(context.Context) error   { return nil }
+func (t *fakeTx) Rollback(context.Context) error { return nil }
+
+// TestListReceptions_PageOffset проверяет фильтры, LIMIT/OFFSET страницы и раскладку товаров по приёмкам.
+func TestListReceptions_PageOffset(t *testing.T) {
+	pvzID := uuid.New()
+	first := models.Reception{ID: uuid.New(), DateTime: time.Now(), PvzID: pvzID, Status: models.StatusClose}
+	second := models.Reception{ID: uuid.New(), DateTime: time.Now(), PvzID: pvzID, Status: models.StatusClose}
+	db := &fakePool{
+		rows:       []*fakeRow{{values: []interface{}{true}}},
+		receptions: []models.Reception{first, second},
+		products: []models.Product{
+			{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeShoes, ReceptionID: first.ID},
+			{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeClothes, ReceptionID: first.ID},
+		},
+	}
+
+	status := models.StatusClose
+	filter := models.ReceptionFilter{Status: &status, Page: 3, Limit: 10}
+	got, err := NewReceptionRepositoryPg(db).ListReceptions(context.Background(), pvzID, filter)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if !strings.Contains(db.lastQuery, "status = $2") || !strings.Contains(db.lastQuery, "LIMIT $3 OFFSET $4") {
+		t.Errorf("неожиданный запрос: %s", db.lastQuery)
+	}
+	if limit, offset := db.lastArgs[2], db.lastArgs[3]; limit != 10 || offset != 20 {
+		t.Errorf("ожидались LIMIT 10 OFFSET 20, получено %v %v", limit, offset)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("ожидалось 2 приёмки, получено %d", len(got))
+	}
+	if got[0].ProductCount != 2 || len(got[1].Products) != 0 || got[1].Products == nil {
+		t.Errorf("товары разложены неверно: %d, %v", got[0].ProductCount, got[1].Products)
+	}
+}
+
+// TestListReceptions_PVZNotFound проверяет, что для несуществующего ПВЗ возвращается ErrPVZNotFound, а не пустой список.
+func TestListReceptions_PVZNotFound(t *testing.T) {
+	db := &fakePool{rows: []*fakeRow{{values: []interface{}{false}}}}
+
+	_, err := NewReceptionRepositoryPg(db).ListReceptions(context.Background(), uuid.New(), models.ReceptionFilter{Page: 1, Limit: 10})
+	if !errors.Is(err, models.ErrPVZNotFound) {
+		t.Fatalf("ожидалась ErrPVZNotFound, получено %v", err)
+	}
+	if db.lastQuery != "" {
+		t.Error("приёмки не должны запрашиваться для несуществующего ПВЗ")
+	}
+}
+
+// TestGetReception проверяет приёмку с товарами и отображение отсутствия строки в ErrReceptionNotFound.
+func TestGetReception(t *testing.T) {
+	rec := models.Reception{ID: uuid.New(), DateTime: time.Now(), PvzID: uuid.New(), Status: models.StatusInProgress}
+	db := &fakePool{
+		rows: []*fakeRow{
+			{values: []interface{}{rec.ID, rec.DateTime, rec.PvzID, rec.Status}},
+			{err: pgx.ErrNoRows},
+		},
+		products: []models.Product{{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeShoes, ReceptionID: rec.ID}},
+	}
+	repo := NewReceptionRepositoryPg(db)
+
+	got, err := repo.GetReception(context.Background(), rec.ID)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if got.Reception != rec || got.ProductCount != 1 {
+		t.Errorf("неожиданная приёмка: %+v", got)
+	}
+
+	if _, err := repo.GetReception(context.Background(), uuid.New()); !errors.Is(err, models.ErrReceptionNotFound) {
+		t.Fatalf("ожидалась ErrReceptionNotFound, получено %v", err)
+	}
+}
+
+// TestCreateReceptionTransactional_RetriesSerializationFailure проверяет повтор транзакции после ошибки 40001.
+func TestCreateReceptionTransactional_RetriesSerializationFailure(t *testing.T) {
+	pvzID := uuid.New()
+	created := models.Reception{ID: uuid.New(), DateTime: time.Now(), PvzID: pvzID, Status: models.StatusInProgress}
+	db := &fakePool{rows: []*fakeRow{
+		{err: &pgconn.PgError{Code: "40001"}},
+		{values: []interface{}{(*time.Time)(nil), models.CityKazan}},
+		{err: pgx.ErrNoRows},
+		{values: []interface{}{created.ID, created.DateTime, created.PvzID, created.Status}},
+	}}
+
+	rec, city, err := NewReceptionRepositoryPg(db).CreateReceptionTransactional(context.Background(), pvzID)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if db.begins != 2 {
+		t.Errorf("ожидалось 2 попытки транзакции, получено %d", db.begins)
+	}
+	if rec != created || city != models.CityKazan {
+		t.Errorf("неожиданный результат: %+v %q", rec, city)
+	}
+}
+
+// TestCreateReceptionTransactional_Deactivated проверяет, что в выведенном из работы ПВЗ приёмка не открывается.
+func TestCreateReceptionTransactional_Deactivated(t *testing.T) {
+	deactivatedAt := time.Now()
+	db := &fakePool{rows: []*fakeRow{{values: []interface{}{&deactivatedAt, models.CityMoscow}}}}
+
+	_, _, err := NewReceptionRepositoryPg(db).CreateReceptionTransactional(context.Background(), uuid.New())
+	if !errors.Is(err, models.ErrPVZDeactivated) {
+		t.Fatalf("ожидалась ErrPVZDeactivated, получено %v", err)
+	}
+}
+
+// TestCloseLastReceptionTransactional_NoActiveReception проверяет отображение отсутствия строки в ErrNoActiveReception.
+func TestCloseLastReceptionTransactional_NoActiveReception(t *testing.T) {
+	db := &fakePool{rows: []*fakeRow{{err: pgx.ErrNoRows}}}
+
+	_, _, err := NewReceptionRepositoryPg(db).CloseLastReceptionTransactional(context.Background(), uuid.NewString())
+	if !errors.Is(err, models.ErrNoActiveReception) {
+		t.Fatalf("ожидалась ErrNoActiveReception, получено %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package reference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"AvitoPVZ/internal/models"
+)
+
+const (
+	tableCities       = "cities"
+	tableProductTypes = "product_types"
+
+	// ChangeChannel - канал NOTIFY, в который триггеры справочников сообщают об изменениях
+	ChangeChannel = "reference_changed"
+
+	pgForeignKeyViolation = "23503"
+)
+
+type pool interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Acquire(ctx context.Context) (*pgxpool.Conn, error)
+}
+
+type Repository struct {
+	pool pool
+}
+
+func NewRepository(db pool) *Repository {
+	return &Repository{pool: db}
+}
+
+func (r *Repository) ListCities(ctx context.Context) ([]models.PVZCity, error) {
+	names, err := r.list(ctx, tableCities)
+	if err != nil {
+		return nil, err
+	}
+
+	cities := make([]models.PVZCity, 0, len(names))
+	for _, name := range names {
+		cities = append(cities, models.PVZCity(name))
+	}
+
+	return cities, nil
+}
+
+func (r *Repository) AddCity(ctx context.Context, city models.PVZCity) error {
+	return r.add(ctx, tableCities, string(city))
+}
+
+func (r *Repository) DeleteCity(ctx context.Context, city models.PVZCity) error {
+	return r.delete(ctx, tableCities, string(city))
+}
+
+func (r *Repository) ListProductTypes(ctx context.Context) ([]models.TypeProduct, error) {
+	names, err := r.list(ctx, tableProductTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]models.TypeProduct, 0, len(names))
+	for _, name := range names {
+		types = append(types, models.TypeProduct(name))
+	}
+
+	return types, nil
+}
+
+func (r *Repository) AddProductType(ctx context.Context, productType models.TypeProduct) error {
+	return r.add(ctx, tableProductTypes, string(productType))
+}
+
+func (r *Repository) DeleteProductType(ctx context.Context, productType models.TypeProduct) error {
+	return r.delete(ctx, tableProductTypes, string(productType))
+}
+
+// Listen - подписка на изменения справочников, в том числе сделанные другими экземплярами сервиса.
+// onChange вызывается сразу после подписки (чтобы не потерять изменения до неё) и после каждого уведомления.
+// Блокируется до отмены ctx или ошибки соединения.
+func (r *Repository) Listen(ctx context.Context, onChange func(ctx context.Context)) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+ChangeChannel); err != nil {
+		return fmt.Errorf("listen %s: %w", ChangeChannel, err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), "UNLISTEN "+ChangeChannel)
+	}()
+
+	onChange(ctx)
+
+	for {
+		if _, err = conn.Conn().WaitForNotification(ctx); err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		onChange(ctx)
+	}
+}
+
+func (r *Repository) list(ctx context.Context, table string) ([]string, error) {
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(`SELECT name FROM %s ORDER BY name`, table))
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", table, err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", table, err)
+	}
+
+	return names, nil
+}
+
+func (r *Repository) add(ctx context.Context, table, name string) error {
+	tag, err := r.pool.Exec(ctx, fmt.Sprintf(`INSERT INTO %s (name) VALUES ($1) ON CONFLICT DO NOTHING`, table), name)
+	if err != nil {
+		return fmt.Errorf("insert into %s: %w", table, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrReferenceExists
+	}
+
+	return nil
+}
+
+func (r *Repository) delete(ctx context.Context, table, name string) error {
+	tag, err := r.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE name = $1`, table), name)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return models.ErrReferenceInUse
+		}
+		return fmt.Errorf("delete from %s: %w", table, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrReferenceNotFound
+	}
+
+	return nil
+}
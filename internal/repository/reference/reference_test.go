@@ -0,0 +1,149 @@
+package reference
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"AvitoPVZ/internal/models"
+)
+
+// fakeRows отдаёт имена справочника построчно
+type fakeRows struct {
+	names []string
+	idx   int
+}
+
+func (r *fakeRows) Close()                                       {}
+func (r *fakeRows) Err() error                                   { return nil }
+func (r *fakeRows) CommandTag() pgconn.CommandTag                { return pgconn.CommandTag{} }
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakeRows) Values() ([]any, error)                       { return []any{r.names[r.idx-1]}, nil }
+func (r *fakeRows) RawValues() [][]byte                          { return nil }
+func (r *fakeRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.idx >= len(r.names) {
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if len(dest) != 1 {
+		return fmt.Errorf("ожидался 1 аргумент для Scan, получено %d", len(dest))
+	}
+	*dest[0].(*string) = r.names[r.idx-1]
+	return nil
+}
+
+// fakePool запоминает последний запрос и отдаёт заданный результат
+type fakePool struct {
+	names   []string
+	tag     pgconn.CommandTag
+	err     error
+	lastSQL string
+}
+
+func (p *fakePool) Query(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	p.lastSQL = sql
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &fakeRows{names: p.names}, nil
+}
+
+func (p *fakePool) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	p.lastSQL = sql
+	return p.tag, p.err
+}
+
+func (p *fakePool) Acquire(context.Context) (*pgxpool.Conn, error) {
+	return nil, p.err
+}
+
+func TestListCities(t *testing.T) {
+	db := &fakePool{names: []string{"Казань", "Москва"}}
+
+	cities, err := NewRepository(db).ListCities(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cities) != 2 || cities[0] != models.CityKazan || cities[1] != models.CityMoscow {
+		t.Errorf("неожиданные города: %v", cities)
+	}
+	if !strings.Contains(db.lastSQL, "FROM cities") {
+		t.Errorf("запрос не к таблице городов: %s", db.lastSQL)
+	}
+}
+
+func TestListProductTypes_QueryError(t *testing.T) {
+	dbErr := errors.New("db down")
+
+	_, err := NewRepository(&fakePool{err: dbErr}).ListProductTypes(context.Background())
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("ожидалась обёрнутая ошибка БД, получено %v", err)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	cases := []struct {
+		name string
+		tag  string
+		want error
+	}{
+		{"inserted", "INSERT 0 1", nil},
+		{"duplicate", "INSERT 0 0", models.ErrReferenceExists},
+	}
+
+	for _, tc := range cases {
+		db := &fakePool{tag: pgconn.NewCommandTag(tc.tag)}
+
+		err := NewRepository(db).AddProductType(context.Background(), models.TypeShoes)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: ожидалось %v, получено %v", tc.name, tc.want, err)
+		}
+		if !strings.Contains(db.lastSQL, "INSERT INTO product_types") {
+			t.Errorf("%s: запрос не к таблице типов товаров: %s", tc.name, db.lastSQL)
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []struct {
+		name string
+		db   *fakePool
+		want error
+	}{
+		{"deleted", &fakePool{tag: pgconn.NewCommandTag("DELETE 1")}, nil},
+		{"missing", &fakePool{tag: pgconn.NewCommandTag("DELETE 0")}, models.ErrReferenceNotFound},
+		{"in use", &fakePool{err: &pgconn.PgError{Code: pgForeignKeyViolation}}, models.ErrReferenceInUse},
+	}
+
+	for _, tc := range cases {
+		err := NewRepository(tc.db).DeleteCity(context.Background(), models.CityKazan)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: ожидалось %v, получено %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestListen_AcquireError(t *testing.T) {
+	dbErr := errors.New("pool closed")
+	called := false
+
+	err := NewRepository(&fakePool{err: dbErr}).Listen(context.Background(), func(context.Context) { called = true })
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("ожидалась ошибка получения соединения, получено %v", err)
+	}
+	if called {
+		t.Error("onChange не должен вызываться без подписки")
+	}
+}
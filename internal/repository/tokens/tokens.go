@@ -0,0 +1,155 @@
+//go:generate mockgen -source=tokens.go -destination=mocks/tokens.go -package=mocks $GOPACKAGE
+//go:generate mockgen -destination=mocks/mock_tx.go -package=mocks github.com/jackc/pgx/v5 Tx
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/transaction"
+)
+
+type DB interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+type Repository struct {
+	db DB
+	// Logger - исключение из правила "репозитории не пишут в журнал": повтор отозванного
+	// refresh-токена отзывает все сессии внутри транзакции, и это событие безопасности видно только здесь
+	Logger *slog.Logger
+}
+
+func NewRepository(db DB) *Repository {
+	return &Repository{db: db, Logger: slog.Default()}
+}
+
+func (r *Repository) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, role, token_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(ctx, query, token.ID, token.UserID, token.Role, token.TokenHash, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("insert refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeRefreshToken - одноразовое использование refresh-токена при ротации.
+// Повторное предъявление уже отозванного токена считается кражей: отзываются все токены пользователя.
+func (r *Repository) ConsumeRefreshToken(ctx context.Context, tokenHash string, now time.Time) (models.RefreshToken, error) {
+	var (
+		token  models.RefreshToken
+		reused bool
+	)
+	err := transaction.Serializable(ctx, r.db, func(tx pgx.Tx) error {
+		reused = false
+
+		query := `
+			SELECT id, user_id, role, token_hash, created_at, expires_at, revoked_at
+			FROM refresh_tokens
+			WHERE token_hash = $1
+			FOR UPDATE
+		`
+		err := tx.QueryRow(ctx, query, tokenHash).Scan(&token.ID, &token.UserID, &token.Role,
+			&token.TokenHash, &token.CreatedAt, &token.ExpiresAt, &token.RevokedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrInvalidRefreshToken
+		} else if err != nil {
+			return fmt.Errorf("query refresh token: %w", err)
+		}
+
+		if token.RevokedAt != nil {
+			reused = true
+			_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`,
+				token.UserID, now)
+			if err != nil {
+				return fmt.Errorf("revoke user refresh tokens: %w", err)
+			}
+			return nil
+		}
+
+		if !token.ExpiresAt.After(now) {
+			return models.ErrInvalidRefreshToken
+		}
+
+		_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = $2 WHERE id = $1`, token.ID, now)
+		if err != nil {
+			return fmt.Errorf("revoke refresh token: %w", err)
+		}
+		token.RevokedAt = &now
+
+		return nil
+	})
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	if reused {
+		r.Logger.WarnContext(ctx, "revoked refresh token reused, all sessions of the user revoked",
+			slog.String("target_user_id", token.UserID.String()))
+		return models.RefreshToken{}, models.ErrInvalidRefreshToken
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken - отзыв refresh-токена пользователя при выходе
+func (r *Repository) RevokeRefreshToken(ctx context.Context, tokenHash string, userID uuid.UUID, now time.Time) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = $3
+		WHERE token_hash = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+	if _, err := r.db.Exec(ctx, query, tokenHash, userID, now); err != nil {
+		return fmt.Errorf("revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAccessToken - занесение jti access-токена в список отозванных до истечения его срока.
+// Заодно удаляются записи, срок которых уже истёк: такие токены отвергаются и без списка.
+func (r *Repository) RevokeAccessToken(ctx context.Context, jti uuid.UUID, expiresAt time.Time, now time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, query, jti, expiresAt); err != nil {
+		return fmt.Errorf("revoke access token: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < $1`, now); err != nil {
+		return fmt.Errorf("cleanup revoked tokens: %w", err)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked - отозван ли access-токен. Кроме списка отозванных jti токен перестаёт
+// действовать, если пользователь удалён, заблокирован или сменил роль: в токене записана прежняя роль
+func (r *Repository) IsAccessTokenRevoked(ctx context.Context, jti, userID uuid.UUID, role models.UserRole) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+			OR NOT EXISTS (SELECT 1 FROM users WHERE id = $2 AND role = $3 AND disabled_at IS NULL)
+	`
+	var revoked bool
+	err := r.db.QueryRow(ctx, query, jti, userID, role).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("query revoked token: %w", err)
+	}
+
+	return revoked, nil
+}
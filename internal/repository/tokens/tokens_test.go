@@ -0,0 +1,194 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/tokens/mocks"
+)
+
+type containsMatcher struct {
+	substr string
+}
+
+func (m *containsMatcher) Matches(x interface{}) bool {
+	s, ok := x.(string)
+	return ok && strings.Contains(s, m.substr)
+}
+
+func (m *containsMatcher) String() string {
+	return fmt.Sprintf("contains substring %q", m.substr)
+}
+
+func Contains(substr string) gomock.Matcher {
+	return &containsMatcher{substr: substr}
+}
+
+// fakeRow присваивает значения по порядку через reflect
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r *fakeRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(dest) != len(r.values) {
+		return fmt.Errorf("ожидалось %d аргументов для Scan, получено %d", len(r.values), len(dest))
+	}
+	for i, v := range r.values {
+		target := reflect.ValueOf(dest[i]).Elem()
+		if v == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		target.Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+func tokenRow(userID uuid.UUID, expiresAt time.Time, revokedAt *time.Time) *fakeRow {
+	return &fakeRow{values: []interface{}{
+		uuid.New(), userID, models.RoleEmployee, "hash", time.Now(), expiresAt, revokedAt,
+	}}
+}
+
+func expectTx(ctx context.Context, mockDB *mocks.MockDB, mockTx *mocks.MockTx) {
+	mockDB.EXPECT().
+		BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable}).
+		Return(mockTx, nil)
+	mockTx.EXPECT().
+		Rollback(ctx).
+		Return(pgx.ErrTxClosed).
+		AnyTimes()
+}
+
+// TestConsumeRefreshToken_Success проверяет, что действующий токен отзывается при использовании.
+func TestConsumeRefreshToken_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+	expectTx(ctx, mockDB, mockTx)
+
+	now := time.Now()
+	userID := uuid.New()
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FOR UPDATE"), "hash").
+		Return(tokenRow(userID, now.Add(time.Hour), nil))
+	mockTx.EXPECT().
+		Exec(ctx, Contains("WHERE id = $1"), gomock.Any(), now).
+		Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	token, err := NewRepository(mockDB).ConsumeRefreshToken(ctx, "hash", now)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if token.UserID != userID || token.Role != models.RoleEmployee {
+		t.Fatalf("неверная сессия: %+v", token)
+	}
+}
+
+// TestConsumeRefreshToken_Expired проверяет отказ для просроченного токена.
+func TestConsumeRefreshToken_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+	expectTx(ctx, mockDB, mockTx)
+
+	now := time.Now()
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FOR UPDATE"), "hash").
+		Return(tokenRow(uuid.New(), now.Add(-time.Second), nil))
+
+	_, err := NewRepository(mockDB).ConsumeRefreshToken(ctx, "hash", now)
+	if !errors.Is(err, models.ErrInvalidRefreshToken) {
+		t.Fatalf("ожидалась ошибка ErrInvalidRefreshToken, получено %v", err)
+	}
+}
+
+// TestConsumeRefreshToken_ReuseRevokesAll проверяет, что повторное использование отзывает все токены пользователя.
+func TestConsumeRefreshToken_ReuseRevokesAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+	expectTx(ctx, mockDB, mockTx)
+
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+	userID := uuid.New()
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FOR UPDATE"), "hash").
+		Return(tokenRow(userID, now.Add(time.Hour), &revokedAt))
+	mockTx.EXPECT().
+		Exec(ctx, Contains("WHERE user_id = $1"), userID, now).
+		Return(pgconn.NewCommandTag("UPDATE 2"), nil)
+	mockTx.EXPECT().Commit(ctx).Return(nil)
+
+	_, err := NewRepository(mockDB).ConsumeRefreshToken(ctx, "hash", now)
+	if !errors.Is(err, models.ErrInvalidRefreshToken) {
+		t.Fatalf("ожидалась ошибка ErrInvalidRefreshToken, получено %v", err)
+	}
+}
+
+// TestConsumeRefreshToken_Unknown проверяет отказ для неизвестного токена.
+func TestConsumeRefreshToken_Unknown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+	expectTx(ctx, mockDB, mockTx)
+
+	mockTx.EXPECT().
+		QueryRow(ctx, Contains("FOR UPDATE"), "hash").
+		Return(&fakeRow{err: pgx.ErrNoRows})
+
+	_, err := NewRepository(mockDB).ConsumeRefreshToken(ctx, "hash", time.Now())
+	if !errors.Is(err, models.ErrInvalidRefreshToken) {
+		t.Fatalf("ожидалась ошибка ErrInvalidRefreshToken, получено %v", err)
+	}
+}
+
+// TestIsAccessTokenRevoked проверяет, что отзыв учитывает и список jti, и текущее состояние пользователя.
+func TestIsAccessTokenRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	jti, userID := uuid.New(), uuid.New()
+
+	mockDB.EXPECT().
+		QueryRow(ctx, gomock.All(Contains("FROM revoked_tokens"), Contains("disabled_at IS NULL")), jti, userID, models.RoleEmployee).
+		Return(&fakeRow{values: []interface{}{true}})
+
+	revoked, err := NewRepository(mockDB).IsAccessTokenRevoked(ctx, jti, userID, models.RoleEmployee)
+	if err != nil || !revoked {
+		t.Fatalf("ожидался отозванный токен, получено %v, %v", revoked, err)
+	}
+}
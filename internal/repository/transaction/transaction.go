@@ -0,0 +1,161 @@
+// Package transaction - общий запуск транзакций репозиториев с повтором
+// при ошибках сериализации и взаимных блокировках.
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"AvitoPVZ/internal/tracing"
+)
+
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// Beginner - источник транзакций (pgxpool.Pool, pgx.Conn или мок)
+type Beginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// Retry - политика повторов: число попыток и границы экспоненциальной задержки с jitter.
+// OnRetry, если задан, вызывается с SQLSTATE перед каждым повтором (для метрик).
+// Logger, если задан, получает повторы и исчерпание попыток с контекстом запроса: повторы
+// не доходят до вызывающего кода, поэтому это второе исключение из правила "репозитории не пишут в журнал"
+type Retry struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	OnRetry     func(sqlState string)
+	Logger      *slog.Logger
+}
+
+// DefaultRetry используется функцией Serializable
+var DefaultRetry = Retry{
+	MaxAttempts: 5,
+	BaseDelay:   10 * time.Millisecond,
+	MaxDelay:    200 * time.Millisecond,
+}
+
+// Serializable выполняет fn в транзакции уровня Serializable по политике DefaultRetry
+func Serializable(ctx context.Context, db Beginner, fn func(tx pgx.Tx) error) error {
+	return DefaultRetry.Run(ctx, db, pgx.TxOptions{IsoLevel: pgx.Serializable}, fn)
+}
+
+// Run выполняет fn в новой транзакции и фиксирует её.
+// Если fn или Commit вернули 40001/40P01, транзакция откатывается и запускается заново,
+// поэтому fn не должна иметь побочных эффектов вне транзакции. Остальные ошибки возвращаются сразу.
+func (r Retry) Run(ctx context.Context, db Beginner, opts pgx.TxOptions, fn func(tx pgx.Tx) error) (err error) {
+	ctx, span := tracing.Start(ctx, "db.transaction", attribute.String("db.transaction.isolation", string(opts.IsoLevel)))
+	defer func() { tracing.End(span, err) }()
+
+	attempts := max(r.MaxAttempts, 1)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, r.backoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		err = runOnce(ctx, db, opts, fn)
+		if !IsRetryable(err) {
+			return err
+		}
+
+		if attempt+1 < attempts {
+			span.AddEvent("retry", trace.WithAttributes(attribute.String("db.response.status_code", sqlState(err))))
+			if r.OnRetry != nil {
+				r.OnRetry(sqlState(err))
+			}
+			if r.Logger != nil {
+				r.Logger.DebugContext(ctx, "retrying transaction", slog.Int("attempt", attempt+1), slog.String("sqlstate", sqlState(err)))
+			}
+		}
+	}
+
+	if r.Logger != nil {
+		r.Logger.WarnContext(ctx, "transaction retries exhausted", slog.Int("attempts", attempts), slog.String("sqlstate", sqlState(err)))
+	}
+
+	return fmt.Errorf("transaction failed after %d attempts: %w", attempts, err)
+}
+
+func runOnce(ctx context.Context, db Beginner, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsRetryable сообщает, что ошибка - конфликт сериализации или взаимная блокировка
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
+
+func sqlState(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
+
+// backoff - "full jitter": случайная задержка от 0 до min(MaxDelay, BaseDelay*2^(attempt-1))
+func (r Retry) backoff(attempt int) time.Duration {
+	if r.BaseDelay <= 0 {
+		return 0
+	}
+
+	ceiling := r.BaseDelay << (attempt - 1)
+	if r.MaxDelay > 0 && (ceiling > r.MaxDelay || ceiling <= 0) {
+		ceiling = r.MaxDelay
+	}
+
+	return rand.N(ceiling + 1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
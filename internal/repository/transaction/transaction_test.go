@@ -0,0 +1,186 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"AvitoPVZ/internal/repository/products/mocks"
+)
+
+var serializable = pgx.TxOptions{IsoLevel: pgx.Serializable}
+
+// fastRetry - политика без реальных задержек для тестов
+var fastRetry = Retry{MaxAttempts: 3}
+
+func pgError(code string) error {
+	return fmt.Errorf("query: %w", &pgconn.PgError{Code: code})
+}
+
+// TestRun_RetriesSerializationFailure проверяет повтор после 40001 и успешную фиксацию второй попытки.
+func TestRun_RetriesSerializationFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	mockDB.EXPECT().BeginTx(ctx, serializable).Return(mockTx, nil).Times(2)
+	mockTx.EXPECT().Commit(ctx).Return(nil).Times(1)
+	mockTx.EXPECT().Rollback(ctx).Return(nil).Times(2)
+
+	calls := 0
+	err := fastRetry.Run(ctx, mockDB, serializable, func(tx pgx.Tx) error {
+		calls++
+		if calls == 1 {
+			return pgError(codeSerializationFailure)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("ожидалось 2 вызова, получено %d", calls)
+	}
+}
+
+// TestRun_RetriesDeadlockOnCommit проверяет повтор, когда 40P01 возвращает Commit.
+func TestRun_RetriesDeadlockOnCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	mockDB.EXPECT().BeginTx(ctx, serializable).Return(mockTx, nil).Times(2)
+	gomock.InOrder(
+		mockTx.EXPECT().Commit(ctx).Return(&pgconn.PgError{Code: codeDeadlockDetected}),
+		mockTx.EXPECT().Commit(ctx).Return(nil),
+	)
+	mockTx.EXPECT().Rollback(ctx).Return(pgx.ErrTxClosed).AnyTimes()
+
+	err := fastRetry.Run(ctx, mockDB, serializable, func(tx pgx.Tx) error { return nil })
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+// TestRun_DoesNotRetryOtherErrors проверяет, что прочие ошибки возвращаются без повтора.
+func TestRun_DoesNotRetryOtherErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	expectedErr := errors.New("business error")
+	mockDB.EXPECT().BeginTx(ctx, serializable).Return(mockTx, nil).Times(1)
+	mockTx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+	err := fastRetry.Run(ctx, mockDB, serializable, func(tx pgx.Tx) error {
+		return fmt.Errorf("wrapped: %w", expectedErr)
+	})
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("ожидалась исходная ошибка, получено %v", err)
+	}
+}
+
+// TestRun_AttemptsExhausted проверяет, что число попыток ограничено и последняя ошибка сохраняется.
+func TestRun_AttemptsExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	mockDB.EXPECT().BeginTx(ctx, serializable).Return(mockTx, nil).Times(fastRetry.MaxAttempts)
+	mockTx.EXPECT().Rollback(ctx).Return(nil).Times(fastRetry.MaxAttempts)
+
+	err := fastRetry.Run(ctx, mockDB, serializable, func(tx pgx.Tx) error {
+		return pgError(codeSerializationFailure)
+	})
+	if !IsRetryable(err) {
+		t.Fatalf("ожидалась ошибка сериализации, получено %v", err)
+	}
+}
+
+// TestRun_OnRetry проверяет, что OnRetry получает SQLSTATE каждого повтора, но не последней неудачи.
+func TestRun_OnRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	mockDB.EXPECT().BeginTx(ctx, serializable).Return(mockTx, nil).Times(3)
+	mockTx.EXPECT().Rollback(ctx).Return(nil).Times(3)
+
+	var retried []string
+	retry := fastRetry
+	retry.OnRetry = func(sqlState string) { retried = append(retried, sqlState) }
+
+	codes := []string{codeSerializationFailure, codeDeadlockDetected, codeSerializationFailure}
+	calls := 0
+	err := retry.Run(ctx, mockDB, serializable, func(tx pgx.Tx) error {
+		code := codes[calls]
+		calls++
+		return pgError(code)
+	})
+	if !IsRetryable(err) {
+		t.Fatalf("ожидалась ошибка сериализации, получено %v", err)
+	}
+	if len(retried) != 2 || retried[0] != codeSerializationFailure || retried[1] != codeDeadlockDetected {
+		t.Fatalf("неожиданные повторы: %v", retried)
+	}
+}
+
+// TestRun_ContextCanceledDuringBackoff проверяет, что отмена контекста прерывает ожидание повтора.
+func TestRun_ContextCanceledDuringBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockDB := mocks.NewMockDB(ctrl)
+	mockTx := mocks.NewMockTx(ctrl)
+
+	mockDB.EXPECT().BeginTx(ctx, serializable).Return(mockTx, nil).Times(1)
+	mockTx.EXPECT().Rollback(ctx).Return(nil).Times(1)
+
+	retry := Retry{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	err := retry.Run(ctx, mockDB, serializable, func(tx pgx.Tx) error {
+		cancel()
+		return pgError(codeDeadlockDetected)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ожидалась context.Canceled, получено %v", err)
+	}
+}
+
+// TestBackoff_Bounds проверяет, что задержка не превышает экспоненциальный потолок и MaxDelay.
+func TestBackoff_Bounds(t *testing.T) {
+	retry := Retry{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	for attempt := 1; attempt < 70; attempt++ {
+		ceiling := retry.MaxDelay
+		if attempt <= 3 {
+			ceiling = retry.BaseDelay << (attempt - 1)
+		}
+		for i := 0; i < 100; i++ {
+			if d := retry.backoff(attempt); d < 0 || d > ceiling {
+				t.Fatalf("attempt %d: задержка %v вне диапазона [0, %v]", attempt, d, ceiling)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Package server - запуск HTTP-сервера и его корректная остановка.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Requests - базовый контекст всех запросов. Serve отменяет его, когда истекает drainTimeout:
+// недоработавшие обработчики прерывают запросы к БД и возвращают соединения в пул,
+// иначе pool.Close после остановки ждал бы их до SIGKILL.
+type Requests struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewRequests() *Requests {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Requests{ctx: ctx, cancel: cancel}
+}
+
+// Middleware делает базовый контекст c.UserContext() запроса. Регистрируется первым в app.Use,
+// чтобы остальные middleware дополняли уже его
+func (r *Requests) Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(r.ctx)
+		return c.Next()
+	}
+}
+
+// Serve обслуживает запросы на ln до отмены ctx. После отмены новые соединения не принимаются,
+// а текущие запросы дорабатывают не дольше drainTimeout, после чего контекст requests отменяется.
+//
+// Обработчики должны передавать дальше c.UserContext(), а не c.Context(): контекст fasthttp
+// отменяется в самом начале остановки и оборвал бы незавершённые транзакции.
+func Serve(ctx context.Context, app *fiber.App, ln net.Listener, requests *Requests, drainTimeout time.Duration) error {
+	// к возврату из Serve запросы либо завершились, либо исчерпали drainTimeout и должны прерваться
+	defer requests.cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- app.Listener(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	if err := app.ShutdownWithTimeout(drainTimeout); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("shutdown: requests still running after %s", drainTimeout)
+		}
+		return fmt.Errorf("shutdown: %w", err)
+	}
+
+	return <-errCh
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newApp(requests *Requests, started, cancelled chan<- struct{}, delay time.Duration) *fiber.App {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(requests.Middleware())
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		select {
+		case <-time.After(delay):
+		case <-c.UserContext().Done():
+			close(cancelled)
+			return c.SendStatus(http.StatusServiceUnavailable)
+		}
+		return c.SendString("done")
+	})
+
+	return app
+}
+
+// TestServe_DrainsInFlightRequest проверяет, что запрос, начатый до остановки, дорабатывает до конца.
+func TestServe_DrainsInFlightRequest(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	requests := NewRequests()
+	started := make(chan struct{})
+	app := newApp(requests, started, make(chan struct{}), 300*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, app, ln, requests, 5*time.Second)
+	}()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	respCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			respCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		respCh <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	res := <-respCh
+	if res.err != nil {
+		t.Fatalf("запрос оборван при остановке: %v", res.err)
+	}
+	if res.status != http.StatusOK || res.body != "done" {
+		t.Fatalf("ожидался 200 done, получено %d %q", res.status, res.body)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("неожиданная ошибка остановки: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve не завершился после остановки")
+	}
+
+	if _, err := net.DialTimeout("tcp", ln.Addr().String(), 200*time.Millisecond); err == nil {
+		t.Fatal("после остановки сервер не должен принимать соединения")
+	}
+}
+
+// TestServe_DrainTimeout проверяет, что слишком долгий запрос не задерживает остановку дольше drainTimeout,
+// а его контекст отменяется, чтобы он не держал соединение пула после остановки.
+func TestServe_DrainTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	requests := NewRequests()
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	app := newApp(requests, started, cancelled, 10*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- Serve(ctx, app, ln, requests, 200*time.Millisecond)
+	}()
+
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	begin := time.Now()
+	cancel()
+
+	select {
+	case err := <-served:
+		if err == nil {
+			t.Fatal("ожидалась ошибка о незавершённых запросах")
+		}
+		if elapsed := time.Since(begin); elapsed > 2*time.Second {
+			t.Fatalf("остановка заняла %s", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve не завершился по таймауту")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("контекст недоработавшего запроса не отменён после drainTimeout")
+	}
+}
@@ -0,0 +1,19 @@
+// Package testutil - заглушки, общие для тестов нескольких пакетов
+package testutil
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// StubAccess - заглушка access.PVZAccess: возвращает Err и запоминает проверенный ПВЗ
+type StubAccess struct {
+	Err     error
+	Checked []string
+}
+
+func (a *StubAccess) CheckPVZAccess(_ context.Context, _ uuid.UUID, pvzID string) error {
+	a.Checked = append(a.Checked, pvzID)
+	return a.Err
+}
@@ -0,0 +1,19 @@
+package testutil
+
+import "AvitoPVZ/internal/models"
+
+// Roles - заглушка набора ролей из политики rbac
+type Roles []models.UserRole
+
+// DefaultRoles - роли из config.yml
+var DefaultRoles = Roles{models.RoleAdmin, models.RoleEmployee, models.RoleModerator}
+
+func (r Roles) HasRole(role models.UserRole) bool {
+	for _, known := range r {
+		if known == role {
+			return true
+		}
+	}
+
+	return false
+}
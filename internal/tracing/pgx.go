@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer открывает клиентский span на каждый запрос pgx, включая BEGIN и COMMIT транзакций,
+// поэтому ожидание блокировки FOR UPDATE и фиксация видны отдельными span'ами.
+// Записывается только текст запроса: параметры содержат хеши паролей и токены
+type QueryTracer struct{}
+
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	statement := compactSQL(data.SQL)
+	operation := operationName(statement)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.query.text", statement),
+		attribute.String("db.operation.name", operation),
+	}
+	if conn != nil {
+		attrs = append(attrs, attribute.String("db.namespace", conn.Config().Database))
+	}
+
+	ctx, _ = tracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+
+		var pgErr *pgconn.PgError
+		if errors.As(data.Err, &pgErr) {
+			span.SetAttributes(attribute.String("db.response.status_code", pgErr.Code))
+		}
+		return
+	}
+
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+}
+
+// compactSQL убирает отступы многострочных запросов репозиториев
+func compactSQL(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// operationName - первое слово запроса в верхнем регистре: SELECT, INSERT, BEGIN, COMMIT
+func operationName(statement string) string {
+	operation, _, _ := strings.Cut(statement, " ")
+	if operation == "" {
+		return "query"
+	}
+
+	return strings.ToUpper(operation)
+}
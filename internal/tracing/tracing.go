@@ -0,0 +1,86 @@
+// Package tracing - трассировка OpenTelemetry: span'ы HTTP-запросов, use case'ов и запросов pgx.
+// Контекст span'а передаётся через context.Context, поэтому обработчики должны передавать дальше c.UserContext().
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"AvitoPVZ/internal/models"
+)
+
+const instrumentationName = "AvitoPVZ"
+
+// tracer берётся у глобального провайдера и начинает писать span'ы после Setup; до этого он no-op
+var tracer = otel.Tracer(instrumentationName)
+
+// Setup включает трассировку: span'ы пишутся в w в формате JSON по одному на строку,
+// входящий заголовок traceparent (W3C Trace Context) продолжает трассу клиента.
+// sampleRatio - доля новых трасс; у продолженных решение берётся от родителя.
+// Возвращённую функцию нужно вызвать при остановке, чтобы дописать накопленные span'ы
+func Setup(w io.Writer, serviceName string, sampleRatio float64) (func(context.Context) error, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Start открывает внутренний span, например use case'а; закрывать его нужно через End.
+// Если span не записывается (трассировка выключена или трасса не попала в выборку),
+// возвращается исходный контекст: дочерним вызовам достаточно родительского span'а
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	spanCtx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	if !span.IsRecording() {
+		return ctx, span
+	}
+
+	return spanCtx, span
+}
+
+// End закрывает span и записывает в него ошибку. Ошибки клиента (доменные со статусом 4xx)
+// остаются событием и не помечают span как сбойный: это ожидаемый исход, а не отказ сервиса
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !isClientError(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+
+	span.End()
+}
+
+func isClientError(err error) bool {
+	var domainErr *models.DomainError
+	return errors.As(err, &domainErr) && domainErr.Status < http.StatusInternalServerError
+}
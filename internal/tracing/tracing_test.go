@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"AvitoPVZ/internal/middleware/errhandler"
+	"AvitoPVZ/internal/models"
+)
+
+// exporter получает span'ы всех тестов пакета: глобальный провайдер OpenTelemetry задаётся один раз
+var exporter = tracetest.NewInMemoryExporter()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	os.Exit(m.Run())
+}
+
+func spans(t *testing.T) tracetest.SpanStubs {
+	t.Helper()
+	t.Cleanup(exporter.Reset)
+	return exporter.GetSpans()
+}
+
+func attr(span tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func newApp() *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
+	app.Use(Middleware())
+	app.Use(errhandler.Middleware())
+	app.Get("/pvz/:pvzId", func(c *fiber.Ctx) error {
+		ctx, span := Start(c.UserContext(), "pvz.GetPVZ")
+		var err error
+		if c.Params("pvzId") == "missing" {
+			err = models.ErrPVZNotFound
+		}
+		End(span, err)
+		if err != nil {
+			return err
+		}
+
+		return c.SendString(trace.SpanContextFromContext(ctx).TraceID().String())
+	})
+	return app
+}
+
+func TestMiddleware_ContinuesTraceparent(t *testing.T) {
+	exporter.Reset()
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	req := httptest.NewRequest("GET", "/pvz/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := newApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("ожидался статус 200, получен %d", resp.StatusCode)
+	}
+
+	got := spans(t)
+	if len(got) != 2 {
+		t.Fatalf("ожидалось 2 span'а, получено %d", len(got))
+	}
+
+	useCase, server := got[0], got[1]
+	if server.Name != "GET /pvz/:pvzId" || server.SpanKind != trace.SpanKindServer {
+		t.Errorf("неожиданный серверный span: %q %v", server.Name, server.SpanKind)
+	}
+	if server.SpanContext.TraceID().String() != traceID || !server.Parent.IsRemote() {
+		t.Errorf("трасса клиента не продолжена: %s", server.SpanContext.TraceID())
+	}
+	if useCase.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("span use case'а не вложен в серверный")
+	}
+	if attr(server, "http.route").AsString() != "/pvz/:pvzId" || attr(server, "http.response.status_code").AsInt64() != 200 {
+		t.Errorf("неожиданные атрибуты: %v", server.Attributes)
+	}
+}
+
+func TestMiddleware_ClientErrorIsNotSpanError(t *testing.T) {
+	exporter.Reset()
+
+	resp, err := newApp().Test(httptest.NewRequest("GET", "/pvz/missing", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("ожидался статус 404, получен %d", resp.StatusCode)
+	}
+
+	for _, span := range spans(t) {
+		if span.Status.Code == codes.Error {
+			t.Errorf("span %q помечен как сбойный из-за ошибки клиента", span.Name)
+		}
+	}
+}
+
+func TestMiddleware_UnmatchedRoute(t *testing.T) {
+	exporter.Reset()
+
+	if _, err := newApp().Test(httptest.NewRequest("GET", "/wp-admin/setup.php", nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	got := spans(t)
+	if len(got) != 1 || got[0].Name != "GET" {
+		t.Fatalf("ожидался span без шаблона маршрута, получено %v", got)
+	}
+}
+
+func TestEnd_InternalError(t *testing.T) {
+	exporter.Reset()
+
+	_, span := Start(context.Background(), "products.CreateProduct")
+	End(span, errors.New("connection reset"))
+
+	got := spans(t)
+	if len(got) != 1 || got[0].Status.Code != codes.Error || len(got[0].Events) != 1 {
+		t.Fatalf("ожидался сбойный span с событием ошибки, получено %v", got)
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	exporter.Reset()
+	qt := NewQueryTracer()
+
+	ctx := qt.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL: `
+  SELECT id
+  FROM users
+  WHERE password_hash = $1
+ `,
+		Args: []any{"$2a$12$secret"},
+	})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	ctx = qt.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "commit"})
+	qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: &pgconn.PgError{Code: "40001"}})
+
+	got := spans(t)
+	if len(got) != 2 {
+		t.Fatalf("ожидалось 2 span'а, получено %d", len(got))
+	}
+
+	query, commit := got[0], got[1]
+	if query.Name != "SELECT" || query.SpanKind != trace.SpanKindClient {
+		t.Errorf("неожиданный span запроса: %q %v", query.Name, query.SpanKind)
+	}
+	if got := attr(query, "db.query.text").AsString(); got != "SELECT id FROM users WHERE password_hash = $1" {
+		t.Errorf("неожиданный текст запроса: %q", got)
+	}
+	if attr(query, "db.response.rows_affected").AsInt64() != 1 {
+		t.Errorf("не записано число строк: %v", query.Attributes)
+	}
+	for _, kv := range query.Attributes {
+		if kv.Value.Emit() == "$2a$12$secret" {
+			t.Errorf("параметр запроса попал в атрибут %s", kv.Key)
+		}
+	}
+
+	if commit.Name != "COMMIT" || commit.Status.Code != codes.Error || attr(commit, "db.response.status_code").AsString() != "40001" {
+		t.Errorf("неожиданный span фиксации: %q %v %v", commit.Name, commit.Status, commit.Attributes)
+	}
+}
+
+func TestStart_NotSampledKeepsContext(t *testing.T) {
+	exporter.Reset()
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+		Remote:  true,
+	})
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), parent)
+
+	got, span := Start(ctx, "pvz.GetPVZ")
+	End(span, nil)
+
+	if got != ctx {
+		t.Errorf("для невыбранной трассы ожидался исходный контекст")
+	}
+	if len(spans(t)) != 0 {
+		t.Errorf("span невыбранной трассы не должен выгружаться")
+	}
+}
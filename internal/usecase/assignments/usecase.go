@@ -0,0 +1,85 @@
+package assignments
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+)
+
+type repository interface {
+	Assign(ctx context.Context, userID, pvzID uuid.UUID, now time.Time) (models.Assignment, error)
+	Unassign(ctx context.Context, userID, pvzID uuid.UUID) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Assignment, error)
+	IsAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error)
+}
+
+type UseCase struct {
+	repo repository
+	Now  func() time.Time
+	// Logger - журнал закреплений и отказов в доступе к ПВЗ; кто выполнил действие, берётся из контекста запроса
+	Logger *slog.Logger
+}
+
+func NewUseCase(repo repository) *UseCase {
+	return &UseCase{repo: repo, Now: time.Now, Logger: slog.Default()}
+}
+
+func (uc *UseCase) Assign(ctx context.Context, userID, pvzID uuid.UUID) (_ models.Assignment, err error) {
+	ctx, span := tracing.Start(ctx, "assignments.Assign")
+	defer func() { tracing.End(span, err) }()
+
+	assignment, err := uc.repo.Assign(ctx, userID, pvzID, uc.Now().UTC())
+	if err != nil {
+		return models.Assignment{}, err
+	}
+
+	uc.Logger.InfoContext(ctx, "employee assigned to pvz", slog.String("target_user_id", userID.String()), slog.String("pvz_id", pvzID.String()))
+	return assignment, nil
+}
+
+func (uc *UseCase) Unassign(ctx context.Context, userID, pvzID uuid.UUID) (err error) {
+	ctx, span := tracing.Start(ctx, "assignments.Unassign")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.Unassign(ctx, userID, pvzID); err != nil {
+		return err
+	}
+
+	uc.Logger.InfoContext(ctx, "employee unassigned from pvz", slog.String("target_user_id", userID.String()), slog.String("pvz_id", pvzID.String()))
+	return nil
+}
+
+func (uc *UseCase) List(ctx context.Context, userID uuid.UUID) (_ []models.Assignment, err error) {
+	ctx, span := tracing.Start(ctx, "assignments.List")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.ListByUser(ctx, userID)
+}
+
+// CheckPVZAccess - может ли пользователь менять данные ПВЗ. Закрепление проверяется
+// на каждый запрос, поэтому снятие сотрудника с ПВЗ действует сразу, без перевыпуска токена
+func (uc *UseCase) CheckPVZAccess(ctx context.Context, userID uuid.UUID, pvzID string) (err error) {
+	ctx, span := tracing.Start(ctx, "assignments.CheckPVZAccess")
+	defer func() { tracing.End(span, err) }()
+
+	pvzUUID, err := uuid.Parse(pvzID)
+	if err != nil {
+		return models.ErrPVZNotAssigned
+	}
+
+	assigned, err := uc.repo.IsAssigned(ctx, userID, pvzUUID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		uc.Logger.WarnContext(ctx, "pvz access denied", slog.String("pvz_id", pvzID))
+		return models.ErrPVZNotAssigned
+	}
+
+	return nil
+}
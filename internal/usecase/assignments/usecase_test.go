@@ -0,0 +1,133 @@
+package assignments_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/logging"
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/usecase/assignments"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) Assign(ctx context.Context, userID, pvzID uuid.UUID, now time.Time) (models.Assignment, error) {
+	args := m.Called(ctx, userID, pvzID, now)
+	return args.Get(0).(models.Assignment), args.Error(1)
+}
+
+func (m *mockRepo) Unassign(ctx context.Context, userID, pvzID uuid.UUID) error {
+	return m.Called(ctx, userID, pvzID).Error(0)
+}
+
+func (m *mockRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Assignment, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Assignment), args.Error(1)
+}
+
+func (m *mockRepo) IsAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, pvzID)
+	return args.Bool(0), args.Error(1)
+}
+
+type AssignmentsUseCaseSuite struct {
+	suite.Suite
+	repo *mockRepo
+	uc   *assignments.UseCase
+	now  time.Time
+	log  *bytes.Buffer
+}
+
+func (s *AssignmentsUseCaseSuite) SetupTest() {
+	s.repo = new(mockRepo)
+	s.uc = assignments.NewUseCase(s.repo)
+	s.now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.uc.Now = func() time.Time { return s.now }
+	s.log = new(bytes.Buffer)
+	s.uc.Logger = logging.New(s.log, slog.LevelInfo)
+}
+
+func (s *AssignmentsUseCaseSuite) entry() map[string]any {
+	var entry map[string]any
+	s.Require().NoError(json.Unmarshal(s.log.Bytes(), &entry))
+	return entry
+}
+
+func (s *AssignmentsUseCaseSuite) TestAssign() {
+	adminID := uuid.NewString()
+	ctx := logging.WithAttrs(context.Background(), slog.String("user_id", adminID))
+	userID, pvzID := uuid.New(), uuid.New()
+	want := models.Assignment{UserID: userID, PvzID: pvzID, AssignedAt: s.now}
+	s.repo.On("Assign", ctx, userID, pvzID, s.now).Return(want, nil)
+
+	got, err := s.uc.Assign(ctx, userID, pvzID)
+	s.Require().NoError(err)
+	s.Equal(want, got)
+
+	// В журнале видно, кто и кого закрепил за ПВЗ
+	entry := s.entry()
+	s.Equal("employee assigned to pvz", entry["msg"])
+	s.Equal(adminID, entry["user_id"])
+	s.Equal(userID.String(), entry["target_user_id"])
+	s.Equal(pvzID.String(), entry["pvz_id"])
+}
+
+func (s *AssignmentsUseCaseSuite) TestAssign_RepoErrorIsNotLogged() {
+	ctx := context.Background()
+	userID, pvzID := uuid.New(), uuid.New()
+	s.repo.On("Assign", ctx, userID, pvzID, s.now).Return(models.Assignment{}, models.ErrPVZNotFound)
+
+	_, err := s.uc.Assign(ctx, userID, pvzID)
+	s.ErrorIs(err, models.ErrPVZNotFound)
+	s.Empty(s.log.String())
+}
+
+func (s *AssignmentsUseCaseSuite) TestCheckPVZAccess_Assigned() {
+	ctx := context.Background()
+	userID, pvzID := uuid.New(), uuid.New()
+	s.repo.On("IsAssigned", ctx, userID, pvzID).Return(true, nil)
+
+	s.NoError(s.uc.CheckPVZAccess(ctx, userID, pvzID.String()))
+}
+
+func (s *AssignmentsUseCaseSuite) TestCheckPVZAccess_NotAssigned() {
+	ctx := context.Background()
+	userID, pvzID := uuid.New(), uuid.New()
+	s.repo.On("IsAssigned", ctx, userID, pvzID).Return(false, nil)
+
+	s.ErrorIs(s.uc.CheckPVZAccess(ctx, userID, pvzID.String()), models.ErrPVZNotAssigned)
+
+	entry := s.entry()
+	s.Equal("pvz access denied", entry["msg"])
+	s.Equal("WARN", entry["level"])
+	s.Equal(pvzID.String(), entry["pvz_id"])
+}
+
+func (s *AssignmentsUseCaseSuite) TestCheckPVZAccess_RepoError() {
+	ctx := context.Background()
+	userID, pvzID := uuid.New(), uuid.New()
+	repoErr := errors.New("db down")
+	s.repo.On("IsAssigned", ctx, userID, pvzID).Return(false, repoErr)
+
+	s.ErrorIs(s.uc.CheckPVZAccess(ctx, userID, pvzID.String()), repoErr)
+}
+
+func (s *AssignmentsUseCaseSuite) TestCheckPVZAccess_InvalidID() {
+	s.ErrorIs(s.uc.CheckPVZAccess(context.Background(), uuid.New(), "not-a-uuid"), models.ErrPVZNotAssigned)
+	s.repo.AssertNotCalled(s.T(), "IsAssigned")
+}
+
+func TestAssignmentsUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(AssignmentsUseCaseSuite))
+}
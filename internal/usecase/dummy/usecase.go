@@ -0,0 +1,76 @@
+// Package dummy - пользователи для /dummyLogin в dev и test.
+// Токен выдаётся настоящему пользователю из таблицы users, поэтому проверки по базе
+// (закрепление за ПВЗ, блокировка, смена роли) работают для него так же, как в production.
+package dummy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+	"AvitoPVZ/internal/utils"
+)
+
+type users interface {
+	UpsertUser(ctx context.Context, user models.User) (uuid.UUID, error)
+}
+
+type assignments interface {
+	AssignAllActive(ctx context.Context, userID uuid.UUID, now time.Time) error
+}
+
+type UseCase struct {
+	users              users
+	assignments        assignments
+	CreateHashPassword func(password string) (string, error)
+	Now                func() time.Time
+	Logger             *slog.Logger
+}
+
+func NewUseCase(users users, assignments assignments) *UseCase {
+	return &UseCase{
+		users:              users,
+		assignments:        assignments,
+		CreateHashPassword: utils.CreateHashPassword,
+		Now:                time.Now,
+		Logger:             slog.Default(),
+	}
+}
+
+// Email - адрес пользователя /dummyLogin с ролью role
+func Email(role models.UserRole) string {
+	return "dummy-" + string(role) + "@dummy.local"
+}
+
+// Login возвращает id пользователя /dummyLogin с ролью role, создавая его при первом входе.
+// Пароль случайный, поэтому войти через /login под этим пользователем нельзя.
+// Сотрудник при каждом входе закрепляется за всеми действующими ПВЗ
+func (uc *UseCase) Login(ctx context.Context, role models.UserRole) (_ uuid.UUID, err error) {
+	ctx, span := tracing.Start(ctx, "dummy.Login")
+	defer func() { tracing.End(span, err) }()
+
+	hash, err := uc.CreateHashPassword(uuid.NewString())
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed generate password: %w", err)
+	}
+
+	userID, err := uc.users.UpsertUser(ctx, models.User{ID: uuid.New(), Email: Email(role), Password: hash, Role: role})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if role == models.RoleEmployee {
+		if err := uc.assignments.AssignAllActive(ctx, userID, uc.Now().UTC()); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	uc.Logger.WarnContext(ctx, "dummy login", slog.String("user_id", userID.String()), slog.String("role", string(role)))
+
+	return userID, nil
+}
@@ -0,0 +1,88 @@
+package dummy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/usecase/dummy"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) UpsertUser(ctx context.Context, user models.User) (uuid.UUID, error) {
+	args := m.Called(ctx, user)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *mockRepo) AssignAllActive(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	return m.Called(ctx, userID, now).Error(0)
+}
+
+type UseCaseSuite struct {
+	suite.Suite
+	repo *mockRepo
+	uc   *dummy.UseCase
+	now  time.Time
+}
+
+func (s *UseCaseSuite) SetupTest() {
+	s.repo = &mockRepo{}
+	s.now = time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.uc = dummy.NewUseCase(s.repo, s.repo)
+	s.uc.CreateHashPassword = func(string) (string, error) { return "hash", nil }
+	s.uc.Now = func() time.Time { return s.now }
+}
+
+func (s *UseCaseSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *UseCaseSuite) userWithRole(role models.UserRole) interface{} {
+	return mock.MatchedBy(func(u models.User) bool {
+		return u.Email == dummy.Email(role) && u.Role == role && u.Password == "hash"
+	})
+}
+
+func (s *UseCaseSuite) TestLogin_EmployeeAssignedToAllPVZ() {
+	ctx := context.Background()
+	userID := uuid.New()
+	s.repo.On("UpsertUser", ctx, s.userWithRole(models.RoleEmployee)).Return(userID, nil)
+	s.repo.On("AssignAllActive", ctx, userID, s.now).Return(nil)
+
+	got, err := s.uc.Login(ctx, models.RoleEmployee)
+	s.Require().NoError(err)
+	s.Equal(userID, got)
+}
+
+func (s *UseCaseSuite) TestLogin_ModeratorNotAssigned() {
+	ctx := context.Background()
+	userID := uuid.New()
+	s.repo.On("UpsertUser", ctx, s.userWithRole(models.RoleModerator)).Return(userID, nil)
+
+	got, err := s.uc.Login(ctx, models.RoleModerator)
+	s.Require().NoError(err)
+	s.Equal(userID, got)
+	s.repo.AssertNotCalled(s.T(), "AssignAllActive", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UseCaseSuite) TestLogin_RepoError() {
+	ctx := context.Background()
+	repoErr := errors.New("db is down")
+	s.repo.On("UpsertUser", ctx, s.userWithRole(models.RoleAdmin)).Return(uuid.Nil, repoErr)
+
+	_, err := s.uc.Login(ctx, models.RoleAdmin)
+	s.ErrorIs(err, repoErr)
+}
+
+func TestUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(UseCaseSuite))
+}
@@ -5,44 +5,218 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
 )
 
-var ErrIncorrectPassword = errors.New("incorrect password")
+var ErrInvalidCredentials = models.ErrInvalidCredentials
+
+// Причины отказа во входе - метка reason в метриках
+const (
+	FailureInvalidCredentials = "invalid_credentials"
+	FailureLocked             = "locked"
+	FailureDisabled           = "disabled"
+)
+
+// Metrics - счётчик отказов во входе
+type Metrics interface {
+	LoginFailed(reason string)
+}
 
 type db interface {
 	GetUserEmail(ctx context.Context, email string) (models.User, error)
+	RehashPassword(ctx context.Context, id uuid.UUID, oldHash, newHash string) error
+}
+
+// Attempts - счётчики неудачных входов по ключу (email или IP клиента)
+type Attempts interface {
+	LockedUntil(ctx context.Context, keys []string, now time.Time) (time.Time, error)
+	RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
+
+// Lockout - политика блокировки входа. После MaxFailures неудач подряд для email
+// (MaxIPFailures для IP) вход блокируется на BaseDelay, и каждая следующая неудача
+// удваивает срок вплоть до MaxDelay. Серия прерывается, если неудач не было дольше Window.
+type Lockout struct {
+	MaxFailures   int
+	MaxIPFailures int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	Window        time.Duration
+}
+
+// Delay - срок блокировки после failures неудач при пороге limit; 0, если порог не достигнут
+func (l Lockout) Delay(failures, limit int) time.Duration {
+	if limit <= 0 || failures < limit {
+		return 0
+	}
+
+	delay := l.BaseDelay
+	for i := limit; i < failures && delay < l.MaxDelay; i++ {
+		delay *= 2
+	}
+	if l.MaxDelay > 0 && delay > l.MaxDelay {
+		delay = l.MaxDelay
+	}
+
+	return delay
 }
 
 type UseCase struct {
 	db                     db
+	attempts               Attempts
+	lockout                Lockout
 	CompareHashAndPassword func(hash string, password string) (bool, error)
+	CreateHashPassword     func(password string) (string, error)
+	NeedsRehash            func(hash string) bool
+	Now                    func() time.Time
+	Metrics                Metrics
+	Logger                 *slog.Logger
 }
 
-func NewUseCase(db db) *UseCase {
+func NewUseCase(db db, attempts Attempts, lockout Lockout) *UseCase {
 	useCase := &UseCase{
-		db: db,
+		db:       db,
+		attempts: attempts,
+		lockout:  lockout,
 	}
 
 	if useCase.CompareHashAndPassword == nil {
 		useCase.CompareHashAndPassword = utils.CompareHashAndPassword
 	}
+	if useCase.CreateHashPassword == nil {
+		useCase.CreateHashPassword = utils.CreateHashPassword
+	}
+	if useCase.NeedsRehash == nil {
+		useCase.NeedsRehash = utils.NeedsRehash
+	}
+	if useCase.Now == nil {
+		useCase.Now = time.Now
+	}
+	if useCase.Metrics == nil {
+		useCase.Metrics = nopMetrics{}
+	}
+	if useCase.Logger == nil {
+		useCase.Logger = slog.Default()
+	}
 
 	return useCase
 }
 
-func (c *UseCase) LoginUser(ctx context.Context, user models.User) (models.User, error) {
-	dbUser, err := c.db.GetUserEmail(ctx, user.Email)
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(email)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// LoginUser проверяет учётные данные. Неудачи считаются отдельно по email и по IP клиента;
+// пока действует блокировка, пароль не проверяется и возвращается models.ErrTooManyAttempts.
+func (c *UseCase) LoginUser(ctx context.Context, user models.User, clientIP string) (_ models.User, err error) {
+	ctx, span := tracing.Start(ctx, "login.LoginUser")
+	defer func() { tracing.End(span, err) }()
+
+	// Сроки хранятся в TIMESTAMP без часового пояса, поэтому всегда в UTC: иначе
+	// на сервере не в UTC блокировка и Retry-After сдвигаются на смещение пояса
+	now := c.Now().UTC()
+	keys := []string{emailKey(user.Email), ipKey(clientIP)}
+
+	lockedUntil, err := c.attempts.LockedUntil(ctx, keys, now)
 	if err != nil {
+		return models.User{}, fmt.Errorf("failed check login lockout: %w", err)
+	}
+	if lockedUntil.After(now) {
+		c.Metrics.LoginFailed(FailureLocked)
+		return models.User{}, &models.RetryAfterError{Err: models.ErrTooManyAttempts, RetryAfter: lockedUntil.Sub(now)}
+	}
+
+	// Неизвестный email и неверный пароль неразличимы ни по ответу, ни по времени
+	dbUser, err := c.db.GetUserEmail(ctx, user.Email)
+	if errors.Is(err, models.ErrUserNotFound) {
+		_, _ = c.CompareHashAndPassword(utils.DummyHash(), user.Password)
+		if err := c.registerFailure(ctx, user.Email, clientIP, now); err != nil {
+			return models.User{}, err
+		}
+		c.Metrics.LoginFailed(FailureInvalidCredentials)
+		return models.User{}, ErrInvalidCredentials
+	} else if err != nil {
 		return models.User{}, fmt.Errorf("failed get user by username: %w", err)
 	}
 
 	_, err = c.CompareHashAndPassword(dbUser.Password, user.Password)
 	if err != nil {
-		return models.User{}, ErrIncorrectPassword
+		if err := c.registerFailure(ctx, user.Email, clientIP, now); err != nil {
+			return models.User{}, err
+		}
+		c.Metrics.LoginFailed(FailureInvalidCredentials)
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	// Счётчик IP не сбрасывается: иначе перебор чужих паролей можно было бы
+	// перемежать входами в свою учётную запись
+	if err := c.attempts.Reset(ctx, emailKey(user.Email)); err != nil {
+		return models.User{}, fmt.Errorf("failed reset login attempts: %w", err)
+	}
+
+	if dbUser.DisabledAt != nil {
+		c.Metrics.LoginFailed(FailureDisabled)
+		return models.User{}, models.ErrUserDisabled
+	}
+
+	if c.NeedsRehash(dbUser.Password) {
+		c.rehash(ctx, dbUser, user.Password)
 	}
 
 	return dbUser, nil
 
 }
+
+// rehash пересчитывает хеш пароля с текущей стоимостью bcrypt. Ошибка не мешает входу:
+// пароль уже проверен, а пересчитать хеш можно и при следующем входе
+func (c *UseCase) rehash(ctx context.Context, user models.User, password string) {
+	hash, err := c.CreateHashPassword(password)
+	if err == nil {
+		err = c.db.RehashPassword(ctx, user.ID, user.Password, hash)
+	}
+	if err != nil {
+		c.Logger.ErrorContext(ctx, "failed rehash password", slog.String("user_id", user.ID.String()), slog.Any("error", err))
+	}
+}
+
+func (c *UseCase) registerFailure(ctx context.Context, email, clientIP string, now time.Time) error {
+	limits := []struct {
+		key   string
+		limit int
+	}{
+		{emailKey(email), c.lockout.MaxFailures},
+		{ipKey(clientIP), c.lockout.MaxIPFailures},
+	}
+
+	for _, l := range limits {
+		failures, err := c.attempts.RegisterFailure(ctx, l.key, now, c.lockout.Window)
+		if err != nil {
+			return fmt.Errorf("failed register login failure: %w", err)
+		}
+
+		if delay := c.lockout.Delay(failures, l.limit); delay > 0 {
+			if err := c.attempts.Lock(ctx, l.key, now.Add(delay)); err != nil {
+				return fmt.Errorf("failed lock login: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) LoginFailed(string) {}
@@ -2,10 +2,14 @@ package login_test
 
 import (
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/attempts"
 	"AvitoPVZ/internal/usecase/login"
+	"AvitoPVZ/internal/utils"
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -21,17 +25,46 @@ func (m *mockDB) GetUserEmail(ctx context.Context, email string) (models.User, e
 	return args.Get(0).(models.User), args.Error(1)
 }
 
+const clientIP = "10.0.0.1"
+
+func (m *mockDB) RehashPassword(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	return m.Called(ctx, id, oldHash, newHash).Error(0)
+}
+
+type stubMetrics struct {
+	failures []string
+}
+
+func (m *stubMetrics) LoginFailed(reason string) { m.failures = append(m.failures, reason) }
+
 type LoginUseCaseSuite struct {
 	suite.Suite
-	mockDB *mockDB
-	uc     *login.UseCase
+	mockDB   *mockDB
+	metrics  *stubMetrics
+	uc       *login.UseCase
+	now      time.Time
+	compared []string
 }
 
 func (s *LoginUseCaseSuite) SetupTest() {
 	s.mockDB = new(mockDB)
-	s.uc = login.NewUseCase(s.mockDB)
+	s.now = time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.uc = login.NewUseCase(s.mockDB, attempts.NewMemory(), login.Lockout{
+		MaxFailures:   3,
+		MaxIPFailures: 5,
+		BaseDelay:     time.Minute,
+		MaxDelay:      10 * time.Minute,
+		Window:        15 * time.Minute,
+	})
+	s.uc.Now = func() time.Time { return s.now }
+	s.metrics = new(stubMetrics)
+	s.uc.Metrics = s.metrics
+	s.uc.NeedsRehash = func(hash string) bool { return false }
+	s.uc.CreateHashPassword = func(password string) (string, error) { return "rehashed:" + password, nil }
 
+	s.compared = nil
 	s.uc.CompareHashAndPassword = func(hash string, password string) (bool, error) {
+		s.compared = append(s.compared, hash)
 		if hash == "hashed_pass" && password == "plain_pass" {
 			return true, nil
 		}
@@ -54,7 +87,7 @@ func (s *LoginUseCaseSuite) Test_LoginUser_Success() {
 
 	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(expectedUser, nil)
 
-	result, err := s.uc.LoginUser(context.Background(), inputUser)
+	result, err := s.uc.LoginUser(context.Background(), inputUser, clientIP)
 
 	s.Require().NoError(err)
 	s.Equal(expectedUser.ID, result.ID)
@@ -70,13 +103,25 @@ func (s *LoginUseCaseSuite) Test_LoginUser_UserNotFound() {
 		Password: "plain_pass",
 	}
 
-	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(models.User{}, errors.New("user not found"))
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(models.User{}, models.ErrUserNotFound)
+
+	_, err := s.uc.LoginUser(context.Background(), inputUser, clientIP)
+
+	// Ответ совпадает с ответом на неверный пароль, а сравнение с фиктивным хешем выравнивает время
+	s.Require().Equal(login.ErrInvalidCredentials, err)
+	s.Equal([]string{utils.DummyHash()}, s.compared)
+	s.mockDB.AssertExpectations(s.T())
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_DBError() {
+	email := "user@example.com"
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(models.User{}, errors.New("connection reset"))
 
-	_, err := s.uc.LoginUser(context.Background(), inputUser)
+	_, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
 
 	s.Require().Error(err)
+	s.NotErrorIs(err, models.ErrInvalidCredentials)
 	s.Contains(err.Error(), "failed get user by username")
-	s.mockDB.AssertExpectations(s.T())
 }
 
 func (s *LoginUseCaseSuite) Test_LoginUser_IncorrectPassword() {
@@ -94,13 +139,178 @@ func (s *LoginUseCaseSuite) Test_LoginUser_IncorrectPassword() {
 
 	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(dbUser, nil)
 
-	_, err := s.uc.LoginUser(context.Background(), inputUser)
+	_, err := s.uc.LoginUser(context.Background(), inputUser, clientIP)
 
 	s.Require().Error(err)
-	s.Equal(login.ErrIncorrectPassword, err)
+	s.Equal(login.ErrInvalidCredentials, err)
+	s.mockDB.AssertExpectations(s.T())
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_Disabled() {
+	email := "disabled@example.com"
+	disabledAt := time.Now()
+
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(models.User{
+		ID:         uuid.New(),
+		Email:      email,
+		Password:   "hashed_pass",
+		Role:       models.RoleEmployee,
+		DisabledAt: &disabledAt,
+	}, nil)
+
+	_, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	s.ErrorIs(err, models.ErrUserDisabled)
+	s.Equal([]string{login.FailureDisabled}, s.metrics.failures)
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_RehashesWeakHash() {
+	email := "user@example.com"
+	user := s.dbUser(email)
+	s.uc.NeedsRehash = func(hash string) bool { return hash == "hashed_pass" }
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(user, nil)
+	s.mockDB.On("RehashPassword", mock.Anything, user.ID, "hashed_pass", "rehashed:plain_pass").Return(nil)
+
+	_, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	s.Require().NoError(err)
 	s.mockDB.AssertExpectations(s.T())
 }
 
+func (s *LoginUseCaseSuite) Test_LoginUser_RehashFailureDoesNotBlockLogin() {
+	email := "user@example.com"
+	user := s.dbUser(email)
+	s.uc.NeedsRehash = func(hash string) bool { return true }
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(user, nil)
+	s.mockDB.On("RehashPassword", mock.Anything, user.ID, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+	result, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	s.Require().NoError(err)
+	s.Equal(user.ID, result.ID)
+}
+
+func (s *LoginUseCaseSuite) dbUser(email string) models.User {
+	return models.User{ID: uuid.New(), Email: email, Password: "hashed_pass", Role: models.RoleEmployee}
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_LockoutAfterFailures() {
+	email := "victim@example.com"
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(s.dbUser(email), nil)
+	wrong := models.User{Email: email, Password: "wrong_pass"}
+
+	for i := 0; i < 3; i++ {
+		_, err := s.uc.LoginUser(context.Background(), wrong, clientIP)
+		s.Require().ErrorIs(err, models.ErrInvalidCredentials)
+	}
+
+	// Во время блокировки не помогает даже верный пароль, и пароль не проверяется
+	_, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, "10.0.0.2")
+	s.Require().ErrorIs(err, models.ErrTooManyAttempts)
+
+	var retryErr *models.RetryAfterError
+	s.Require().True(errors.As(err, &retryErr))
+	s.Equal(time.Minute, retryErr.RetryAfter)
+	s.mockDB.AssertNumberOfCalls(s.T(), "GetUserEmail", 3)
+	s.Equal([]string{
+		login.FailureInvalidCredentials,
+		login.FailureInvalidCredentials,
+		login.FailureInvalidCredentials,
+		login.FailureLocked,
+	}, s.metrics.failures)
+
+	s.now = s.now.Add(time.Minute)
+	_, err = s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	s.Require().NoError(err)
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_LockoutGrowsExponentially() {
+	email := "victim@example.com"
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(s.dbUser(email), nil)
+	wrong := models.User{Email: email, Password: "wrong_pass"}
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for i := 0; i < 2; i++ {
+		_, _ = s.uc.LoginUser(context.Background(), wrong, clientIP)
+	}
+	for _, delay := range expected {
+		_, err := s.uc.LoginUser(context.Background(), wrong, clientIP)
+		s.Require().ErrorIs(err, models.ErrInvalidCredentials)
+
+		_, err = s.uc.LoginUser(context.Background(), wrong, clientIP)
+		var retryErr *models.RetryAfterError
+		s.Require().True(errors.As(err, &retryErr))
+		s.Equal(delay, retryErr.RetryAfter)
+
+		s.now = s.now.Add(delay)
+	}
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_IPLockoutAcrossEmails() {
+	for i := 0; i < 5; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		s.mockDB.On("GetUserEmail", mock.Anything, email).Return(models.User{}, models.ErrUserNotFound)
+
+		_, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+		s.Require().ErrorIs(err, models.ErrInvalidCredentials)
+	}
+
+	_, err := s.uc.LoginUser(context.Background(), models.User{Email: "other@example.com", Password: "plain_pass"}, clientIP)
+	s.Require().ErrorIs(err, models.ErrTooManyAttempts)
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_SuccessResetsEmailCounter() {
+	email := "user@example.com"
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(s.dbUser(email), nil)
+	wrong := models.User{Email: email, Password: "wrong_pass"}
+
+	_, _ = s.uc.LoginUser(context.Background(), wrong, clientIP)
+	_, _ = s.uc.LoginUser(context.Background(), wrong, clientIP)
+	_, err := s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	s.Require().NoError(err)
+
+	_, _ = s.uc.LoginUser(context.Background(), wrong, clientIP)
+	_, _ = s.uc.LoginUser(context.Background(), wrong, clientIP)
+	_, err = s.uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	s.Require().NoError(err)
+}
+
+// recordingAttempts запоминает время, с которым use case обращается к счётчикам
+type recordingAttempts struct {
+	*attempts.Memory
+	times []time.Time
+}
+
+func (a *recordingAttempts) RegisterFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	a.times = append(a.times, now)
+	return a.Memory.RegisterFailure(ctx, key, now, window)
+}
+
+func (a *recordingAttempts) Lock(ctx context.Context, key string, until time.Time) error {
+	a.times = append(a.times, until)
+	return a.Memory.Lock(ctx, key, until)
+}
+
+func (s *LoginUseCaseSuite) Test_LoginUser_UsesUTCOnNonUTCHost() {
+	store := &recordingAttempts{Memory: attempts.NewMemory()}
+	uc := login.NewUseCase(s.mockDB, store, login.Lockout{MaxFailures: 1, BaseDelay: time.Minute, Window: time.Minute})
+	uc.CompareHashAndPassword = s.uc.CompareHashAndPassword
+	uc.Now = func() time.Time { return s.now.In(time.FixedZone("MSK", 3*60*60)) }
+
+	email := "user@example.com"
+	s.mockDB.On("GetUserEmail", mock.Anything, email).Return(s.dbUser(email), nil)
+
+	_, err := uc.LoginUser(context.Background(), models.User{Email: email, Password: "wrong_pass"}, clientIP)
+	s.ErrorIs(err, login.ErrInvalidCredentials)
+
+	s.Require().NotEmpty(store.times)
+	for _, tm := range store.times {
+		s.Equal(time.UTC, tm.Location())
+	}
+
+	_, err = uc.LoginUser(context.Background(), models.User{Email: email, Password: "plain_pass"}, clientIP)
+	var retry *models.RetryAfterError
+	s.Require().ErrorAs(err, &retry)
+	s.Equal(time.Minute, retry.RetryAfter)
+}
+
 func TestLoginUseCaseSuite(t *testing.T) {
 	suite.Run(t, new(LoginUseCaseSuite))
 }
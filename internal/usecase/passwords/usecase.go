@@ -0,0 +1,249 @@
+package passwords
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+	"AvitoPVZ/internal/usecase/login"
+	"AvitoPVZ/internal/utils"
+)
+
+const (
+	resetTokenBytes = 32
+	defaultResetTTL = 30 * time.Minute
+)
+
+var (
+	errWrongCurrentPassword = models.ErrInvalidCredentials.WithMessage("current password is incorrect")
+	errTooManyResetRequests = models.ErrTooManyAttempts.WithMessage("too many password reset requests, try again later")
+)
+
+type repository interface {
+	GetUserEmail(ctx context.Context, email string) (models.User, error)
+	GetPasswordHash(ctx context.Context, id uuid.UUID) (string, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, hash string, now time.Time) error
+	CreateResetToken(ctx context.Context, token models.PasswordResetToken) error
+	ResetTokenValid(ctx context.Context, tokenHash string, now time.Time) error
+	ResetPassword(ctx context.Context, tokenHash, hash string, now time.Time) error
+}
+
+// Notifier доставляет пользователю токен сброса пароля
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, email, token string, expiresAt time.Time) error
+}
+
+// UseCase ограничивает перебор тем же хранилищем попыток и политикой, что и вход:
+// неверные текущие пароли считаются по пользователю, запросы сброса - по email и по IP
+type UseCase struct {
+	repo                   repository
+	notifier               Notifier
+	attempts               login.Attempts
+	lockout                login.Lockout
+	ResetTTL               time.Duration
+	CreateHashPassword     func(password string) (string, error)
+	CompareHashAndPassword func(hash string, password string) (bool, error)
+	Now                    func() time.Time
+	Logger                 *slog.Logger
+}
+
+func NewUseCase(repo repository, notifier Notifier, attempts login.Attempts, lockout login.Lockout, resetTTL time.Duration) *UseCase {
+	if resetTTL <= 0 {
+		resetTTL = defaultResetTTL
+	}
+
+	return &UseCase{
+		repo:                   repo,
+		notifier:               notifier,
+		attempts:               attempts,
+		lockout:                lockout,
+		ResetTTL:               resetTTL,
+		CreateHashPassword:     utils.CreateHashPassword,
+		CompareHashAndPassword: utils.CompareHashAndPassword,
+		Now:                    time.Now,
+		Logger:                 slog.Default(),
+	}
+}
+
+func changeKey(userID uuid.UUID) string {
+	return "password:" + userID.String()
+}
+
+func resetEmailKey(email string) string {
+	return "reset:email:" + strings.ToLower(email)
+}
+
+func resetIPKey(ip string) string {
+	return "reset:ip:" + ip
+}
+
+// ChangePassword - смена пароля с проверкой текущего. Все refresh-сессии пользователя завершаются.
+// После серии неверных текущих паролей смена блокируется так же, как вход
+func (uc *UseCase) ChangePassword(ctx context.Context, userID uuid.UUID, current, next string) (err error) {
+	ctx, span := tracing.Start(ctx, "passwords.ChangePassword")
+	defer func() { tracing.End(span, err) }()
+
+	now := uc.Now().UTC()
+	key := changeKey(userID)
+	if err := uc.checkLocked(ctx, []string{key}, now, models.ErrTooManyAttempts); err != nil {
+		return err
+	}
+
+	hash, err := uc.repo.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed get password: %w", err)
+	}
+
+	if _, err := uc.CompareHashAndPassword(hash, current); err != nil {
+		if err := uc.registerAttempt(ctx, key, uc.lockout.MaxFailures, now); err != nil {
+			return err
+		}
+		return errWrongCurrentPassword
+	}
+	if err := uc.attempts.Reset(ctx, key); err != nil {
+		return fmt.Errorf("failed reset password attempts: %w", err)
+	}
+
+	newHash, err := uc.CreateHashPassword(next)
+	if err != nil {
+		return fmt.Errorf("failed generate password: %w", err)
+	}
+
+	if err := uc.repo.UpdatePassword(ctx, userID, newHash, now); err != nil {
+		return err
+	}
+
+	uc.Logger.InfoContext(ctx, "password changed")
+
+	return nil
+}
+
+// RequestReset выпускает одноразовый токен сброса и отправляет его пользователю.
+// Для неизвестного или отключённого email ничего не происходит, а ответ тот же,
+// чтобы по нему нельзя было проверить наличие учётной записи. Запросы считаются по email
+// и по IP клиента до поиска пользователя: частые запросы не засыпают пользователя письмами
+// и не гасят раз за разом его действующий токен
+func (uc *UseCase) RequestReset(ctx context.Context, email, clientIP string) (err error) {
+	ctx, span := tracing.Start(ctx, "passwords.RequestReset")
+	defer func() { tracing.End(span, err) }()
+
+	now := uc.Now().UTC()
+	if err := uc.checkLocked(ctx, []string{resetEmailKey(email), resetIPKey(clientIP)}, now, errTooManyResetRequests); err != nil {
+		return err
+	}
+	if err := uc.registerAttempt(ctx, resetEmailKey(email), uc.lockout.MaxFailures, now); err != nil {
+		return err
+	}
+	if err := uc.registerAttempt(ctx, resetIPKey(clientIP), uc.lockout.MaxIPFailures, now); err != nil {
+		return err
+	}
+
+	user, err := uc.repo.GetUserEmail(ctx, email)
+	if errors.Is(err, models.ErrUserNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed get user by email: %w", err)
+	}
+	if user.DisabledAt != nil {
+		return nil
+	}
+
+	raw := make([]byte, resetTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("failed generate reset token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	resetToken := models.PasswordResetToken{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.ResetTTL),
+	}
+	if err := uc.repo.CreateResetToken(ctx, resetToken); err != nil {
+		return fmt.Errorf("failed store reset token: %w", err)
+	}
+
+	if err := uc.notifier.SendPasswordReset(ctx, user.Email, token, resetToken.ExpiresAt); err != nil {
+		return fmt.Errorf("failed send reset token: %w", err)
+	}
+
+	uc.Logger.InfoContext(ctx, "password reset requested", slog.String("target_user_id", user.ID.String()))
+
+	return nil
+}
+
+// ConfirmReset гасит токен сброса и устанавливает новый пароль. Токен проверяется до
+// хеширования пароля, чтобы запросы с подобранными токенами не тратили время на bcrypt
+func (uc *UseCase) ConfirmReset(ctx context.Context, token, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "passwords.ConfirmReset")
+	defer func() { tracing.End(span, err) }()
+
+	if token == "" {
+		return models.ErrInvalidResetToken
+	}
+
+	now := uc.Now().UTC()
+	tokenHash := hashToken(token)
+	if err := uc.repo.ResetTokenValid(ctx, tokenHash, now); err != nil {
+		return err
+	}
+
+	hash, err := uc.CreateHashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed generate password: %w", err)
+	}
+
+	if err := uc.repo.ResetPassword(ctx, tokenHash, hash, now); err != nil {
+		return err
+	}
+
+	uc.Logger.InfoContext(ctx, "password reset confirmed")
+
+	return nil
+}
+
+// checkLocked возвращает lockedErr со сроком блокировки, если заблокирован любой из keys
+func (uc *UseCase) checkLocked(ctx context.Context, keys []string, now time.Time, lockedErr error) error {
+	lockedUntil, err := uc.attempts.LockedUntil(ctx, keys, now)
+	if err != nil {
+		return fmt.Errorf("failed check lockout: %w", err)
+	}
+	if lockedUntil.After(now) {
+		return &models.RetryAfterError{Err: lockedErr, RetryAfter: lockedUntil.Sub(now)}
+	}
+
+	return nil
+}
+
+// registerAttempt засчитывает попытку по key и блокирует его по политике входа при пороге limit
+func (uc *UseCase) registerAttempt(ctx context.Context, key string, limit int, now time.Time) error {
+	attempts, err := uc.attempts.RegisterFailure(ctx, key, now, uc.lockout.Window)
+	if err != nil {
+		return fmt.Errorf("failed register attempt: %w", err)
+	}
+
+	if delay := uc.lockout.Delay(attempts, limit); delay > 0 {
+		if err := uc.attempts.Lock(ctx, key, now.Add(delay)); err != nil {
+			return fmt.Errorf("failed lock: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
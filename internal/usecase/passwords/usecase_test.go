@@ -0,0 +1,214 @@
+package passwords_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/repository/attempts"
+	"AvitoPVZ/internal/usecase/login"
+	"AvitoPVZ/internal/usecase/passwords"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) GetUserEmail(ctx context.Context, email string) (models.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *mockRepo) GetPasswordHash(ctx context.Context, id uuid.UUID) (string, error) {
+	args := m.Called(ctx, id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hash string, now time.Time) error {
+	return m.Called(ctx, id, hash, now).Error(0)
+}
+
+func (m *mockRepo) CreateResetToken(ctx context.Context, token models.PasswordResetToken) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *mockRepo) ResetTokenValid(ctx context.Context, tokenHash string, now time.Time) error {
+	return m.Called(ctx, tokenHash, now).Error(0)
+}
+
+func (m *mockRepo) ResetPassword(ctx context.Context, tokenHash, hash string, now time.Time) error {
+	return m.Called(ctx, tokenHash, hash, now).Error(0)
+}
+
+type sentReset struct {
+	email     string
+	token     string
+	expiresAt time.Time
+}
+
+type stubNotifier struct {
+	sent []sentReset
+	err  error
+}
+
+func (n *stubNotifier) SendPasswordReset(_ context.Context, email, token string, expiresAt time.Time) error {
+	n.sent = append(n.sent, sentReset{email: email, token: token, expiresAt: expiresAt})
+	return n.err
+}
+
+type PasswordsUseCaseSuite struct {
+	suite.Suite
+	repo     *mockRepo
+	notifier *stubNotifier
+	uc       *passwords.UseCase
+	now      time.Time
+}
+
+func (s *PasswordsUseCaseSuite) SetupTest() {
+	s.repo = new(mockRepo)
+	s.notifier = &stubNotifier{}
+	s.uc = passwords.NewUseCase(s.repo, s.notifier, attempts.NewMemory(), login.Lockout{
+		MaxFailures:   3,
+		MaxIPFailures: 5,
+		BaseDelay:     time.Minute,
+		MaxDelay:      time.Hour,
+		Window:        15 * time.Minute,
+	}, time.Hour)
+	s.now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.uc.Now = func() time.Time { return s.now }
+	s.uc.CreateHashPassword = func(password string) (string, error) { return "hash:" + password, nil }
+	s.uc.CompareHashAndPassword = func(hash, password string) (bool, error) {
+		if hash != "hash:"+password {
+			return false, errors.New("mismatch")
+		}
+		return true, nil
+	}
+}
+
+func (s *PasswordsUseCaseSuite) TestChangePassword_Success() {
+	id := uuid.New()
+	s.repo.On("GetPasswordHash", mock.Anything, id).Return("hash:old", nil)
+	s.repo.On("UpdatePassword", mock.Anything, id, "hash:new", s.now).Return(nil)
+
+	s.Require().NoError(s.uc.ChangePassword(context.Background(), id, "old", "new"))
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PasswordsUseCaseSuite) TestChangePassword_WrongCurrent() {
+	id := uuid.New()
+	s.repo.On("GetPasswordHash", mock.Anything, id).Return("hash:old", nil)
+
+	err := s.uc.ChangePassword(context.Background(), id, "guess", "new")
+	s.ErrorIs(err, models.ErrInvalidCredentials)
+	s.repo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *PasswordsUseCaseSuite) TestChangePassword_LockedAfterFailures() {
+	id := uuid.New()
+	s.repo.On("GetPasswordHash", mock.Anything, id).Return("hash:old", nil).Times(3)
+
+	for i := 0; i < 3; i++ {
+		s.ErrorIs(s.uc.ChangePassword(context.Background(), id, "guess", "new"), models.ErrInvalidCredentials)
+	}
+
+	// Заблокированная смена не проверяет пароль, даже верный
+	err := s.uc.ChangePassword(context.Background(), id, "old", "new")
+	s.ErrorIs(err, models.ErrTooManyAttempts)
+	var retry *models.RetryAfterError
+	s.Require().ErrorAs(err, &retry)
+	s.Equal(time.Minute, retry.RetryAfter)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PasswordsUseCaseSuite) TestRequestReset_SendsToken() {
+	user := models.User{ID: uuid.New(), Email: "user@example.com"}
+	s.repo.On("GetUserEmail", mock.Anything, user.Email).Return(user, nil)
+
+	var stored models.PasswordResetToken
+	s.repo.On("CreateResetToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(models.PasswordResetToken)
+	}).Return(nil)
+
+	s.Require().NoError(s.uc.RequestReset(context.Background(), user.Email, "10.0.0.1"))
+	s.Require().Len(s.notifier.sent, 1)
+
+	sent := s.notifier.sent[0]
+	s.Equal(user.Email, sent.email)
+	s.Equal(s.now.Add(time.Hour), sent.expiresAt)
+
+	// В базе только хеш, сам токен уходит пользователю
+	sum := sha256.Sum256([]byte(sent.token))
+	s.Equal(hex.EncodeToString(sum[:]), stored.TokenHash)
+	s.Equal(user.ID, stored.UserID)
+}
+
+func (s *PasswordsUseCaseSuite) TestRequestReset_UnknownOrDisabledIsSilent() {
+	disabledAt := s.now
+	s.repo.On("GetUserEmail", mock.Anything, "missing@example.com").Return(models.User{}, models.ErrUserNotFound)
+	s.repo.On("GetUserEmail", mock.Anything, "disabled@example.com").
+		Return(models.User{ID: uuid.New(), Email: "disabled@example.com", DisabledAt: &disabledAt}, nil)
+
+	s.NoError(s.uc.RequestReset(context.Background(), "missing@example.com", "10.0.0.1"))
+	s.NoError(s.uc.RequestReset(context.Background(), "disabled@example.com", "10.0.0.1"))
+	s.Empty(s.notifier.sent)
+	s.repo.AssertNotCalled(s.T(), "CreateResetToken", mock.Anything, mock.Anything)
+}
+
+func (s *PasswordsUseCaseSuite) TestRequestReset_RateLimitedPerEmail() {
+	s.repo.On("GetUserEmail", mock.Anything, mock.Anything).Return(models.User{}, models.ErrUserNotFound).Times(3)
+
+	for i := 0; i < 3; i++ {
+		s.NoError(s.uc.RequestReset(context.Background(), "Missing@example.com", "10.0.0.1"))
+	}
+
+	err := s.uc.RequestReset(context.Background(), "missing@example.com", "10.0.0.2")
+	s.ErrorIs(err, models.ErrTooManyAttempts)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PasswordsUseCaseSuite) TestRequestReset_RateLimitedPerIP() {
+	s.repo.On("GetUserEmail", mock.Anything, mock.Anything).Return(models.User{}, models.ErrUserNotFound).Times(5)
+
+	for i := 0; i < 5; i++ {
+		s.NoError(s.uc.RequestReset(context.Background(), uuid.NewString()+"@example.com", "10.0.0.1"))
+	}
+
+	s.ErrorIs(s.uc.RequestReset(context.Background(), "other@example.com", "10.0.0.1"), models.ErrTooManyAttempts)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PasswordsUseCaseSuite) TestConfirmReset() {
+	sum := sha256.Sum256([]byte("token"))
+	s.repo.On("ResetTokenValid", mock.Anything, hex.EncodeToString(sum[:]), s.now).Return(nil)
+	s.repo.On("ResetPassword", mock.Anything, hex.EncodeToString(sum[:]), "hash:new", s.now).Return(nil)
+
+	s.Require().NoError(s.uc.ConfirmReset(context.Background(), "token", "new"))
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PasswordsUseCaseSuite) TestConfirmReset_InvalidTokenSkipsHashing() {
+	s.repo.On("ResetTokenValid", mock.Anything, mock.Anything, s.now).Return(models.ErrInvalidResetToken)
+	s.uc.CreateHashPassword = func(string) (string, error) {
+		s.Fail("пароль не должен хешироваться для неизвестного токена")
+		return "", nil
+	}
+
+	s.ErrorIs(s.uc.ConfirmReset(context.Background(), "guessed", "new"), models.ErrInvalidResetToken)
+	s.repo.AssertNotCalled(s.T(), "ResetPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *PasswordsUseCaseSuite) TestConfirmReset_EmptyToken() {
+	s.ErrorIs(s.uc.ConfirmReset(context.Background(), "", "new"), models.ErrInvalidResetToken)
+}
+
+func TestPasswordsUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(PasswordsUseCaseSuite))
+}
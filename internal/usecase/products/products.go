@@ -2,28 +2,112 @@ package products
 
 import (
 	"context"
+	"log/slog"
+
 	"github.com/google/uuid"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
 )
 
 type ProductRepository interface {
-	CreateProductTransactional(ctx context.Context, pvzID uuid.UUID, productType models.TypeProduct) (models.Product, error)
+	CreateProductTransactional(ctx context.Context, pvzID uuid.UUID, product models.Product) (models.Product, error)
+	CreateProductsBatchTransactional(ctx context.Context, pvzID uuid.UUID, products []models.Product) ([]models.Product, error)
 	DeleteLastProductTransactional(ctx context.Context, pvzID string) error
+	DeleteProductTransactional(ctx context.Context, pvzID, productID string) error
+	GetByBarcode(ctx context.Context, barcode string) (models.ProductLocation, error)
+}
+
+// Metrics - доменные счётчики принятых и удалённых товаров
+type Metrics interface {
+	ProductAccepted(productType models.TypeProduct)
+	ProductDeleted()
 }
 
 type ProductUseCase struct {
 	repo ProductRepository
+
+	Metrics Metrics
+	// Logger - журнал приёма и удаления товаров; кто выполнил действие, берётся из контекста запроса
+	Logger *slog.Logger
 }
 
 func NewProductUseCase(repo ProductRepository) *ProductUseCase {
-	return &ProductUseCase{repo: repo}
+	return &ProductUseCase{repo: repo, Metrics: nopMetrics{}, Logger: slog.Default()}
 }
 
-func (uc *ProductUseCase) CreateProduct(ctx context.Context, pvzID uuid.UUID, productType models.TypeProduct) (models.Product, error) {
-	return uc.repo.CreateProductTransactional(ctx, pvzID, productType)
+func (uc *ProductUseCase) CreateProduct(ctx context.Context, pvzID uuid.UUID, product models.Product) (_ models.Product, err error) {
+	ctx, span := tracing.Start(ctx, "products.CreateProduct")
+	defer func() { tracing.End(span, err) }()
+
+	created, err := uc.repo.CreateProductTransactional(ctx, pvzID, product)
+	if err != nil {
+		return models.Product{}, err
+	}
+
+	uc.Metrics.ProductAccepted(created.Type)
+	uc.Logger.InfoContext(ctx, "product accepted",
+		slog.String("product_id", created.ID.String()),
+		slog.String("pvz_id", pvzID.String()),
+		slog.String("type", string(created.Type)),
+	)
+
+	return created, nil
 }
 
-func (uc *ProductUseCase) DeleteLastProduct(ctx context.Context, pvzID string) error {
-	return uc.repo.DeleteLastProductTransactional(ctx, pvzID)
+func (uc *ProductUseCase) CreateProductsBatch(ctx context.Context, pvzID uuid.UUID, products []models.Product) (_ []models.Product, err error) {
+	ctx, span := tracing.Start(ctx, "products.CreateProductsBatch")
+	defer func() { tracing.End(span, err) }()
+
+	created, err := uc.repo.CreateProductsBatchTransactional(ctx, pvzID, products)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range created {
+		uc.Metrics.ProductAccepted(p.Type)
+	}
+	uc.Logger.InfoContext(ctx, "products batch accepted", slog.String("pvz_id", pvzID.String()), slog.Int("count", len(created)))
+
+	return created, nil
 }
+
+func (uc *ProductUseCase) DeleteLastProduct(ctx context.Context, pvzID string) (err error) {
+	ctx, span := tracing.Start(ctx, "products.DeleteLastProduct")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.DeleteLastProductTransactional(ctx, pvzID); err != nil {
+		return err
+	}
+
+	uc.Metrics.ProductDeleted()
+	uc.Logger.InfoContext(ctx, "last product deleted", slog.String("pvz_id", pvzID))
+
+	return nil
+}
+
+func (uc *ProductUseCase) DeleteProduct(ctx context.Context, pvzID, productID string) (err error) {
+	ctx, span := tracing.Start(ctx, "products.DeleteProduct")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.DeleteProductTransactional(ctx, pvzID, productID); err != nil {
+		return err
+	}
+
+	uc.Metrics.ProductDeleted()
+	uc.Logger.InfoContext(ctx, "product deleted", slog.String("pvz_id", pvzID), slog.String("product_id", productID))
+
+	return nil
+}
+
+func (uc *ProductUseCase) GetProductByBarcode(ctx context.Context, barcode string) (_ models.ProductLocation, err error) {
+	ctx, span := tracing.Start(ctx, "products.GetProductByBarcode")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.GetByBarcode(ctx, barcode)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ProductAccepted(models.TypeProduct) {}
+func (nopMetrics) ProductDeleted()                    {}
@@ -18,30 +18,58 @@ type mockProductRepo struct {
 	mock.Mock
 }
 
-func (m *mockProductRepo) CreateProductTransactional(ctx context.Context, pvzID uuid.UUID, productType models.TypeProduct) (models.Product, error) {
-	args := m.Called(ctx, pvzID, productType)
+func (m *mockProductRepo) CreateProductTransactional(ctx context.Context, pvzID uuid.UUID, product models.Product) (models.Product, error) {
+	args := m.Called(ctx, pvzID, product)
 	return args.Get(0).(models.Product), args.Error(1)
 }
 
+func (m *mockProductRepo) CreateProductsBatchTransactional(ctx context.Context, pvzID uuid.UUID, products []models.Product) ([]models.Product, error) {
+	args := m.Called(ctx, pvzID, products)
+	return args.Get(0).([]models.Product), args.Error(1)
+}
+
+func (m *mockProductRepo) GetByBarcode(ctx context.Context, barcode string) (models.ProductLocation, error) {
+	args := m.Called(ctx, barcode)
+	return args.Get(0).(models.ProductLocation), args.Error(1)
+}
+
 func (m *mockProductRepo) DeleteLastProductTransactional(ctx context.Context, pvzID string) error {
 	args := m.Called(ctx, pvzID)
 	return args.Error(0)
 }
 
+func (m *mockProductRepo) DeleteProductTransactional(ctx context.Context, pvzID, productID string) error {
+	args := m.Called(ctx, pvzID, productID)
+	return args.Error(0)
+}
+
+type stubMetrics struct {
+	accepted []models.TypeProduct
+	deleted  int
+}
+
+func (m *stubMetrics) ProductAccepted(t models.TypeProduct) { m.accepted = append(m.accepted, t) }
+func (m *stubMetrics) ProductDeleted()                      { m.deleted++ }
+
 type ProductUseCaseSuite struct {
 	suite.Suite
-	repo *mockProductRepo
-	uc   *products.ProductUseCase
+	repo    *mockProductRepo
+	metrics *stubMetrics
+	uc      *products.ProductUseCase
 }
 
 func (s *ProductUseCaseSuite) SetupTest() {
 	s.repo = new(mockProductRepo)
+	s.metrics = new(stubMetrics)
 	s.uc = products.NewProductUseCase(s.repo)
+	s.uc.Metrics = s.metrics
 }
 
 func (s *ProductUseCaseSuite) Test_CreateProduct_Success() {
 	pvzID := uuid.New()
 	productType := models.TypeElectronic
+	barcode := "4601234567890"
+	draft := models.Product{Type: productType, Barcode: &barcode}
 	expectedProduct := models.Product{
 		ID:          uuid.New(),
 		ReceptionID: pvzID,
@@ -49,30 +77,65 @@ func (s *ProductUseCaseSuite) Test_CreateProduct_Success() {
 		DateTime:    time.Now(),
 	}
 
-	s.repo.On("CreateProductTransactional", mock.Anything, pvzID, productType).Return(expectedProduct, nil)
+	s.repo.On("CreateProductTransactional", mock.Anything, pvzID, draft).Return(expectedProduct, nil)
 
-	result, err := s.uc.CreateProduct(context.Background(), pvzID, productType)
+	result, err := s.uc.CreateProduct(context.Background(), pvzID, draft)
 
 	s.Require().NoError(err)
 	s.Equal(expectedProduct, result)
+	s.Equal([]models.TypeProduct{productType}, s.metrics.accepted)
 	s.repo.AssertExpectations(s.T())
 }
 
 func (s *ProductUseCaseSuite) Test_CreateProduct_Error() {
 	pvzID := uuid.New()
-	productType := models.TypeClothes
+	draft := models.Product{Type: models.TypeClothes}
 	expectedErr := errors.New("database failure")
 
-	s.repo.On("CreateProductTransactional", mock.Anything, pvzID, productType).Return(models.Product{}, expectedErr)
+	s.repo.On("CreateProductTransactional", mock.Anything, pvzID, draft).Return(models.Product{}, expectedErr)
 
-	result, err := s.uc.CreateProduct(context.Background(), pvzID, productType)
+	result, err := s.uc.CreateProduct(context.Background(), pvzID, draft)
 
 	s.Require().Error(err)
 	s.Equal(expectedErr, err)
 	s.Equal(models.Product{}, result)
+	s.Empty(s.metrics.accepted)
 	s.repo.AssertExpectations(s.T())
 }
 
+func (s *ProductUseCaseSuite) Test_CreateProductsBatch_ItemError() {
+	pvzID := uuid.New()
+	drafts := []models.Product{{Type: models.TypeShoes}, {Type: models.TypeClothes}}
+	expectedErr := &models.BatchItemError{Index: 1, Err: models.ErrDuplicateBarcode}
+
+	s.repo.On("CreateProductsBatchTransactional", mock.Anything, pvzID, drafts).Return([]models.Product(nil), expectedErr)
+
+	result, err := s.uc.CreateProductsBatch(context.Background(), pvzID, drafts)
+
+	s.Require().ErrorIs(err, models.ErrDuplicateBarcode)
+	s.Nil(result)
+	s.Empty(s.metrics.accepted)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ProductUseCaseSuite) Test_CreateProductsBatch_CountsEachType() {
+	pvzID := uuid.New()
+	drafts := []models.Product{{Type: models.TypeShoes}, {Type: models.TypeShoes}, {Type: models.TypeElectronic}}
+	created := []models.Product{
+		{ID: uuid.New(), Type: models.TypeShoes},
+		{ID: uuid.New(), Type: models.TypeShoes},
+		{ID: uuid.New(), Type: models.TypeElectronic},
+	}
+
+	s.repo.On("CreateProductsBatchTransactional", mock.Anything, pvzID, drafts).Return(created, nil)
+
+	result, err := s.uc.CreateProductsBatch(context.Background(), pvzID, drafts)
+
+	s.Require().NoError(err)
+	s.Equal(created, result)
+	s.Equal([]models.TypeProduct{models.TypeShoes, models.TypeShoes, models.TypeElectronic}, s.metrics.accepted)
+}
+
 func (s *ProductUseCaseSuite) Test_DeleteLastProduct_Success() {
 	pvzID := uuid.NewString()
 	s.repo.On("DeleteLastProductTransactional", mock.Anything, pvzID).Return(nil)
@@ -80,6 +143,7 @@ func (s *ProductUseCaseSuite) Test_DeleteLastProduct_Success() {
 	err := s.uc.DeleteLastProduct(context.Background(), pvzID)
 
 	s.Require().NoError(err)
+	s.Equal(1, s.metrics.deleted)
 	s.repo.AssertExpectations(s.T())
 }
 
@@ -93,6 +157,35 @@ func (s *ProductUseCaseSuite) Test_DeleteLastProduct_Error() {
 
 	s.Require().Error(err)
 	s.Equal(expectedErr, err)
+	s.Zero(s.metrics.deleted)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ProductUseCaseSuite) Test_DeleteProduct_ReceptionClosed() {
+	pvzID := uuid.NewString()
+	productID := uuid.NewString()
+
+	s.repo.On("DeleteProductTransactional", mock.Anything, pvzID, productID).Return(models.ErrReceptionClosed)
+
+	err := s.uc.DeleteProduct(context.Background(), pvzID, productID)
+
+	s.Require().ErrorIs(err, models.ErrReceptionClosed)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ProductUseCaseSuite) Test_GetProductByBarcode_Success() {
+	barcode := "4601234567890"
+	expected := models.ProductLocation{
+		Product:   models.Product{ID: uuid.New(), Barcode: &barcode},
+		Reception: models.Reception{ID: uuid.New(), Status: models.StatusInProgress},
+		PVZ:       models.PVZ{ID: uuid.NewString(), Active: true},
+	}
+	s.repo.On("GetByBarcode", mock.Anything, barcode).Return(expected, nil)
+
+	result, err := s.uc.GetProductByBarcode(context.Background(), barcode)
+
+	s.Require().NoError(err)
+	s.Equal(expected, result)
 	s.repo.AssertExpectations(s.T())
 }
 
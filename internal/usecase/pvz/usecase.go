@@ -3,34 +3,91 @@ package pvz
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
 )
 
 type repository interface {
 	Create(ctx context.Context, city models.PVZCity) (models.PVZ, error)
 	GetPVZData(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]models.PVZData, error)
+	GetPVZDataByCursor(ctx context.Context, startDate, endDate *time.Time, cursor *models.PVZCursor, limit int) ([]models.PVZData, *models.PVZCursor, error)
+	GetByID(ctx context.Context, id string) (models.PVZ, error)
+	UpdateCity(ctx context.Context, id string, city models.PVZCity) (models.PVZ, error)
+	Deactivate(ctx context.Context, id string) (models.PVZ, error)
 }
 
 type UseCase struct {
 	pvzRepo repository
+	// Logger - журнал изменений ПВЗ; кто выполнил действие, берётся из контекста запроса
+	Logger *slog.Logger
 }
 
 func NewPVZUseCase(repo repository) *UseCase {
 	return &UseCase{
 		pvzRepo: repo,
+		Logger:  slog.Default(),
 	}
 }
 
-func (uc *UseCase) CreatePVZ(ctx context.Context, city models.PVZCity) (models.PVZ, error) {
+func (uc *UseCase) CreatePVZ(ctx context.Context, city models.PVZCity) (_ models.PVZ, err error) {
+	ctx, span := tracing.Start(ctx, "pvz.CreatePVZ")
+	defer func() { tracing.End(span, err) }()
+
 	newPVZ, err := uc.pvzRepo.Create(ctx, city)
 	if err != nil {
 		return models.PVZ{}, fmt.Errorf("failed to create PVZ: %w", err)
 	}
+
+	uc.Logger.InfoContext(ctx, "pvz created", slog.String("pvz_id", newPVZ.ID), slog.String("city", newPVZ.City))
 	return newPVZ, nil
 }
 
-func (uc *UseCase) GetPVZData(ctx context.Context, startDate, endDate *time.Time, page, limit int) ([]models.PVZData, error) {
+func (uc *UseCase) GetPVZData(ctx context.Context, startDate, endDate *time.Time, page, limit int) (_ []models.PVZData, err error) {
+	ctx, span := tracing.Start(ctx, "pvz.GetPVZData")
+	defer func() { tracing.End(span, err) }()
+
 	return uc.pvzRepo.GetPVZData(ctx, startDate, endDate, page, limit)
 }
+
+func (uc *UseCase) GetPVZDataByCursor(ctx context.Context, startDate, endDate *time.Time, cursor *models.PVZCursor, limit int) (_ []models.PVZData, _ *models.PVZCursor, err error) {
+	ctx, span := tracing.Start(ctx, "pvz.GetPVZDataByCursor")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.pvzRepo.GetPVZDataByCursor(ctx, startDate, endDate, cursor, limit)
+}
+
+func (uc *UseCase) GetPVZ(ctx context.Context, id string) (_ models.PVZ, err error) {
+	ctx, span := tracing.Start(ctx, "pvz.GetPVZ")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.pvzRepo.GetByID(ctx, id)
+}
+
+func (uc *UseCase) UpdatePVZ(ctx context.Context, id string, city models.PVZCity) (_ models.PVZ, err error) {
+	ctx, span := tracing.Start(ctx, "pvz.UpdatePVZ")
+	defer func() { tracing.End(span, err) }()
+
+	updated, err := uc.pvzRepo.UpdateCity(ctx, id, city)
+	if err != nil {
+		return models.PVZ{}, fmt.Errorf("failed to update PVZ: %w", err)
+	}
+
+	uc.Logger.InfoContext(ctx, "pvz city changed", slog.String("pvz_id", updated.ID), slog.String("city", updated.City))
+	return updated, nil
+}
+
+func (uc *UseCase) DeactivatePVZ(ctx context.Context, id string) (_ models.PVZ, err error) {
+	ctx, span := tracing.Start(ctx, "pvz.DeactivatePVZ")
+	defer func() { tracing.End(span, err) }()
+
+	deactivated, err := uc.pvzRepo.Deactivate(ctx, id)
+	if err != nil {
+		return models.PVZ{}, fmt.Errorf("failed to deactivate PVZ: %w", err)
+	}
+
+	uc.Logger.InfoContext(ctx, "pvz deactivated", slog.String("pvz_id", deactivated.ID))
+	return deactivated, nil
+}
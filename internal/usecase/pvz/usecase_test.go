@@ -27,6 +27,26 @@ func (m *mockPVZRepository) GetPVZData(ctx context.Context, startDate, endDate *
 	return args.Get(0).([]models.PVZData), args.Error(1)
 }
 
+func (m *mockPVZRepository) GetPVZDataByCursor(ctx context.Context, startDate, endDate *time.Time, cursor *models.PVZCursor, limit int) ([]models.PVZData, *models.PVZCursor, error) {
+	args := m.Called(ctx, startDate, endDate, cursor, limit)
+	return args.Get(0).([]models.PVZData), args.Get(1).(*models.PVZCursor), args.Error(2)
+}
+
+func (m *mockPVZRepository) GetByID(ctx context.Context, id string) (models.PVZ, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.PVZ), args.Error(1)
+}
+
+func (m *mockPVZRepository) UpdateCity(ctx context.Context, id string, city models.PVZCity) (models.PVZ, error) {
+	args := m.Called(ctx, id, city)
+	return args.Get(0).(models.PVZ), args.Error(1)
+}
+
+func (m *mockPVZRepository) Deactivate(ctx context.Context, id string) (models.PVZ, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.PVZ), args.Error(1)
+}
+
 type PVZUseCaseSuite struct {
 	suite.Suite
 	repo *mockPVZRepository
@@ -90,6 +110,53 @@ func (s *PVZUseCaseSuite) Test_GetPVZData_Success() {
 	s.repo.AssertExpectations(s.T())
 }
 
+func (s *PVZUseCaseSuite) Test_GetPVZDataByCursor_Success() {
+	cursor := &models.PVZCursor{RegistrationDate: time.Now(), ID: uuid.NewString()}
+	next := &models.PVZCursor{RegistrationDate: time.Now(), ID: uuid.NewString()}
+	expectedData := []models.PVZData{{PVZ: models.PVZ{ID: next.ID}}}
+	s.repo.On("GetPVZDataByCursor", mock.Anything, (*time.Time)(nil), (*time.Time)(nil), cursor, 10).Return(expectedData, next, nil)
+
+	result, nextCursor, err := s.uc.GetPVZDataByCursor(context.Background(), nil, nil, cursor, 10)
+
+	s.Require().NoError(err)
+	s.Equal(expectedData, result)
+	s.Equal(next, nextCursor)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PVZUseCaseSuite) Test_GetPVZ_NotFound() {
+	id := uuid.NewString()
+	s.repo.On("GetByID", mock.Anything, id).Return(models.PVZ{}, models.ErrPVZNotFound)
+
+	_, err := s.uc.GetPVZ(context.Background(), id)
+
+	s.Require().ErrorIs(err, models.ErrPVZNotFound)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PVZUseCaseSuite) Test_UpdatePVZ_Success() {
+	id := uuid.NewString()
+	expected := models.PVZ{ID: id, City: string(models.CitySPB), Active: true}
+	s.repo.On("UpdateCity", mock.Anything, id, models.CitySPB).Return(expected, nil)
+
+	result, err := s.uc.UpdatePVZ(context.Background(), id, models.CitySPB)
+
+	s.Require().NoError(err)
+	s.Equal(expected, result)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *PVZUseCaseSuite) Test_DeactivatePVZ_Error() {
+	id := uuid.NewString()
+	s.repo.On("Deactivate", mock.Anything, id).Return(models.PVZ{}, models.ErrPVZNotFound)
+
+	_, err := s.uc.DeactivatePVZ(context.Background(), id)
+
+	s.Require().ErrorIs(err, models.ErrPVZNotFound)
+	s.ErrorContains(err, "failed to deactivate PVZ")
+	s.repo.AssertExpectations(s.T())
+}
+
 func TestPVZUseCaseSuite(t *testing.T) {
 	suite.Run(t, new(PVZUseCaseSuite))
 }
@@ -2,28 +2,83 @@ package receptions
 
 import (
 	"context"
+	"log/slog"
+
 	"github.com/google/uuid"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
 )
 
 type ReceptionRepository interface {
-	CreateReceptionTransactional(ctx context.Context, pvzID uuid.UUID) (models.Reception, error)
-	CloseLastReceptionTransactional(ctx context.Context, pvzID string) (models.Reception, error)
+	CreateReceptionTransactional(ctx context.Context, pvzID uuid.UUID) (models.Reception, models.PVZCity, error)
+	CloseLastReceptionTransactional(ctx context.Context, pvzID string) (models.Reception, models.PVZCity, error)
+	GetReception(ctx context.Context, id uuid.UUID) (models.ReceptionDetails, error)
+	ListReceptions(ctx context.Context, pvzID uuid.UUID, filter models.ReceptionFilter) ([]models.ReceptionDetails, error)
+}
+
+// Metrics - доменные счётчики приёмок по городам ПВЗ
+type Metrics interface {
+	ReceptionOpened(city models.PVZCity)
+	ReceptionClosed(city models.PVZCity)
 }
 
 type ReceptionUseCase struct {
 	repo ReceptionRepository
+
+	Metrics Metrics
+	Logger  *slog.Logger
 }
 
 func NewReceptionUseCase(repo ReceptionRepository) *ReceptionUseCase {
-	return &ReceptionUseCase{repo: repo}
+	return &ReceptionUseCase{repo: repo, Metrics: nopMetrics{}, Logger: slog.Default()}
+}
+
+func (uc *ReceptionUseCase) CreateReception(ctx context.Context, pvzID uuid.UUID) (_ models.Reception, err error) {
+	ctx, span := tracing.Start(ctx, "receptions.CreateReception")
+	defer func() { tracing.End(span, err) }()
+
+	rec, city, err := uc.repo.CreateReceptionTransactional(ctx, pvzID)
+	if err != nil {
+		return models.Reception{}, err
+	}
+
+	uc.Metrics.ReceptionOpened(city)
+	uc.Logger.InfoContext(ctx, "reception opened", slog.String("reception_id", rec.ID.String()), slog.String("pvz_id", rec.PvzID.String()))
+
+	return rec, nil
 }
 
-func (uc *ReceptionUseCase) CreateReception(ctx context.Context, pvzID uuid.UUID) (models.Reception, error) {
-	return uc.repo.CreateReceptionTransactional(ctx, pvzID)
+func (uc *ReceptionUseCase) CloseLastReception(ctx context.Context, pvzID string) (_ models.Reception, err error) {
+	ctx, span := tracing.Start(ctx, "receptions.CloseLastReception")
+	defer func() { tracing.End(span, err) }()
+
+	rec, city, err := uc.repo.CloseLastReceptionTransactional(ctx, pvzID)
+	if err != nil {
+		return models.Reception{}, err
+	}
+
+	uc.Metrics.ReceptionClosed(city)
+	uc.Logger.InfoContext(ctx, "reception closed", slog.String("reception_id", rec.ID.String()), slog.String("pvz_id", rec.PvzID.String()))
+
+	return rec, nil
 }
 
-func (uc *ReceptionUseCase) CloseLastReception(ctx context.Context, pvzID string) (models.Reception, error) {
-	return uc.repo.CloseLastReceptionTransactional(ctx, pvzID)
+func (uc *ReceptionUseCase) GetReception(ctx context.Context, id uuid.UUID) (_ models.ReceptionDetails, err error) {
+	ctx, span := tracing.Start(ctx, "receptions.GetReception")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.GetReception(ctx, id)
 }
+
+func (uc *ReceptionUseCase) ListReceptions(ctx context.Context, pvzID uuid.UUID, filter models.ReceptionFilter) (_ []models.ReceptionDetails, err error) {
+	ctx, span := tracing.Start(ctx, "receptions.ListReceptions")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.ListReceptions(ctx, pvzID, filter)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ReceptionOpened(models.PVZCity) {}
+func (nopMetrics) ReceptionClosed(models.PVZCity) {}
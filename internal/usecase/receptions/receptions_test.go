@@ -18,25 +18,46 @@ type mockReceptionRepo struct {
 	mock.Mock
 }
 
-func (m *mockReceptionRepo) CreateReceptionTransactional(ctx context.Context, pvzID uuid.UUID) (models.Reception, error) {
+func (m *mockReceptionRepo) CreateReceptionTransactional(ctx context.Context, pvzID uuid.UUID) (models.Reception, models.PVZCity, error) {
 	args := m.Called(ctx, pvzID)
-	return args.Get(0).(models.Reception), args.Error(1)
+	return args.Get(0).(models.Reception), args.Get(1).(models.PVZCity), args.Error(2)
 }
 
-func (m *mockReceptionRepo) CloseLastReceptionTransactional(ctx context.Context, pvzID string) (models.Reception, error) {
+func (m *mockReceptionRepo) CloseLastReceptionTransactional(ctx context.Context, pvzID string) (models.Reception, models.PVZCity, error) {
 	args := m.Called(ctx, pvzID)
-	return args.Get(0).(models.Reception), args.Error(1)
+	return args.Get(0).(models.Reception), args.Get(1).(models.PVZCity), args.Error(2)
 }
 
+func (m *mockReceptionRepo) GetReception(ctx context.Context, id uuid.UUID) (models.ReceptionDetails, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.ReceptionDetails), args.Error(1)
+}
+
+func (m *mockReceptionRepo) ListReceptions(ctx context.Context, pvzID uuid.UUID, filter models.ReceptionFilter) ([]models.ReceptionDetails, error) {
+	args := m.Called(ctx, pvzID, filter)
+	return args.Get(0).([]models.ReceptionDetails), args.Error(1)
+}
+
+type stubMetrics struct {
+	opened []models.PVZCity
+	closed []models.PVZCity
+}
+
+func (m *stubMetrics) ReceptionOpened(city models.PVZCity) { m.opened = append(m.opened, city) }
+func (m *stubMetrics) ReceptionClosed(city models.PVZCity) { m.closed = append(m.closed, city) }
+
 type ReceptionUseCaseTestSuite struct {
 	suite.Suite
-	repo *mockReceptionRepo
-	uc   *receptions.ReceptionUseCase
+	repo    *mockReceptionRepo
+	metrics *stubMetrics
+	uc      *receptions.ReceptionUseCase
 }
 
 func (s *ReceptionUseCaseTestSuite) SetupTest() {
 	s.repo = new(mockReceptionRepo)
+	s.metrics = new(stubMetrics)
 	s.uc = receptions.NewReceptionUseCase(s.repo)
+	s.uc.Metrics = s.metrics
 }
 
 func (s *ReceptionUseCaseTestSuite) Test_CreateReception_Success() {
@@ -47,25 +68,27 @@ func (s *ReceptionUseCaseTestSuite) Test_CreateReception_Success() {
 		PvzID:    pvzID,
 		Status:   models.StatusInProgress,
 	}
-	s.repo.On("CreateReceptionTransactional", mock.Anything, pvzID).Return(expected, nil)
+	s.repo.On("CreateReceptionTransactional", mock.Anything, pvzID).Return(expected, models.CityKazan, nil)
 
 	result, err := s.uc.CreateReception(context.Background(), pvzID)
 
 	s.Require().NoError(err)
 	s.Equal(expected, result)
+	s.Equal([]models.PVZCity{models.CityKazan}, s.metrics.opened)
 	s.repo.AssertExpectations(s.T())
 }
 
 func (s *ReceptionUseCaseTestSuite) Test_CreateReception_Error() {
 	pvzID := uuid.New()
 	expectedErr := errors.New("db error")
-	s.repo.On("CreateReceptionTransactional", mock.Anything, pvzID).Return(models.Reception{}, expectedErr)
+	s.repo.On("CreateReceptionTransactional", mock.Anything, pvzID).Return(models.Reception{}, models.PVZCity(""), expectedErr)
 
 	result, err := s.uc.CreateReception(context.Background(), pvzID)
 
 	s.Require().Error(err)
 	s.Equal(models.Reception{}, result)
 	s.Equal(expectedErr, err)
+	s.Empty(s.metrics.opened)
 	s.repo.AssertExpectations(s.T())
 }
 
@@ -77,19 +100,20 @@ func (s *ReceptionUseCaseTestSuite) Test_CloseLastReception_Success() {
 		PvzID:    uuid.MustParse(pvzID),
 		Status:   models.StatusClose,
 	}
-	s.repo.On("CloseLastReceptionTransactional", mock.Anything, pvzID).Return(expected, nil)
+	s.repo.On("CloseLastReceptionTransactional", mock.Anything, pvzID).Return(expected, models.CityMoscow, nil)
 
 	result, err := s.uc.CloseLastReception(context.Background(), pvzID)
 
 	s.Require().NoError(err)
 	s.Equal(expected, result)
+	s.Equal([]models.PVZCity{models.CityMoscow}, s.metrics.closed)
 	s.repo.AssertExpectations(s.T())
 }
 
 func (s *ReceptionUseCaseTestSuite) Test_CloseLastReception_Error() {
 	pvzID := uuid.New().String()
 	expectedErr := errors.New("no open reception found")
-	s.repo.On("CloseLastReceptionTransactional", mock.Anything, pvzID).Return(models.Reception{}, expectedErr)
+	s.repo.On("CloseLastReceptionTransactional", mock.Anything, pvzID).Return(models.Reception{}, models.PVZCity(""), expectedErr)
 
 	result, err := s.uc.CloseLastReception(context.Background(), pvzID)
 
@@ -99,6 +123,36 @@ func (s *ReceptionUseCaseTestSuite) Test_CloseLastReception_Error() {
 	s.repo.AssertExpectations(s.T())
 }
 
+func (s *ReceptionUseCaseTestSuite) Test_GetReception_NotFound() {
+	id := uuid.New()
+	s.repo.On("GetReception", mock.Anything, id).Return(models.ReceptionDetails{}, models.ErrReceptionNotFound)
+
+	_, err := s.uc.GetReception(context.Background(), id)
+
+	s.Require().ErrorIs(err, models.ErrReceptionNotFound)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ReceptionUseCaseTestSuite) Test_ListReceptions_Success() {
+	pvzID := uuid.New()
+	status := models.StatusClose
+	filter := models.ReceptionFilter{Status: &status, Page: 1, Limit: 10}
+	expected := []models.ReceptionDetails{
+		{
+			Reception:    models.Reception{ID: uuid.New(), PvzID: pvzID, Status: status},
+			ProductCount: 1,
+			Products:     []models.Product{{ID: uuid.New()}},
+		},
+	}
+	s.repo.On("ListReceptions", mock.Anything, pvzID, filter).Return(expected, nil)
+
+	result, err := s.uc.ListReceptions(context.Background(), pvzID, filter)
+
+	s.Require().NoError(err)
+	s.Equal(expected, result)
+	s.repo.AssertExpectations(s.T())
+}
+
 func TestReceptionUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(ReceptionUseCaseTestSuite))
 }
@@ -0,0 +1,186 @@
+package reference
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+)
+
+const reconnectDelay = time.Second
+
+type repository interface {
+	ListCities(ctx context.Context) ([]models.PVZCity, error)
+	AddCity(ctx context.Context, city models.PVZCity) error
+	DeleteCity(ctx context.Context, city models.PVZCity) error
+	ListProductTypes(ctx context.Context) ([]models.TypeProduct, error)
+	AddProductType(ctx context.Context, productType models.TypeProduct) error
+	DeleteProductType(ctx context.Context, productType models.TypeProduct) error
+	Listen(ctx context.Context, onChange func(ctx context.Context)) error
+}
+
+// UseCase - управление справочниками городов и типов товаров.
+// Держит их кэш для валидации запросов без обращения к БД.
+type UseCase struct {
+	repo   repository
+	Logger *slog.Logger
+
+	mu           sync.RWMutex
+	cities       map[models.PVZCity]struct{}
+	productTypes map[models.TypeProduct]struct{}
+}
+
+func NewUseCase(repo repository) *UseCase {
+	return &UseCase{
+		repo:         repo,
+		Logger:       slog.Default(),
+		cities:       map[models.PVZCity]struct{}{},
+		productTypes: map[models.TypeProduct]struct{}{},
+	}
+}
+
+// Refresh - перечитывание справочников из БД в кэш
+func (uc *UseCase) Refresh(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "reference.Refresh")
+	defer func() { tracing.End(span, err) }()
+
+	cities, err := uc.repo.ListCities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load cities: %w", err)
+	}
+
+	productTypes, err := uc.repo.ListProductTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load product types: %w", err)
+	}
+
+	citySet := make(map[models.PVZCity]struct{}, len(cities))
+	for _, city := range cities {
+		citySet[city] = struct{}{}
+	}
+
+	typeSet := make(map[models.TypeProduct]struct{}, len(productTypes))
+	for _, productType := range productTypes {
+		typeSet[productType] = struct{}{}
+	}
+
+	uc.mu.Lock()
+	uc.cities = citySet
+	uc.productTypes = typeSet
+	uc.mu.Unlock()
+
+	return nil
+}
+
+// Watch - обновление кэша по уведомлениям БД об изменении справочников до отмены ctx.
+// При потере соединения переподписывается.
+func (uc *UseCase) Watch(ctx context.Context) {
+	for {
+		err := uc.repo.Listen(ctx, func(ctx context.Context) {
+			if err := uc.Refresh(ctx); err != nil {
+				uc.Logger.ErrorContext(ctx, "reference cache refresh failed", slog.Any("error", err))
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		uc.Logger.WarnContext(ctx, "reference listener stopped, reconnecting", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// refreshAfterChange обновляет кэш после изменения справочника. Изменение уже сохранено,
+// поэтому ошибка не возвращается клиенту (его повтор получил бы конфликт), а только пишется
+// в журнал: кэш обновит уведомление, которое обрабатывает Watch
+func (uc *UseCase) refreshAfterChange(ctx context.Context) {
+	if err := uc.Refresh(ctx); err != nil {
+		uc.Logger.WarnContext(ctx, "reference cache refresh after change failed", slog.Any("error", err))
+	}
+}
+
+func (uc *UseCase) IsPVZCity(city models.PVZCity) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	_, ok := uc.cities[city]
+	return ok
+}
+
+func (uc *UseCase) IsTypeProduct(productType string) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	_, ok := uc.productTypes[models.TypeProduct(productType)]
+	return ok
+}
+
+func (uc *UseCase) ListCities(ctx context.Context) (_ []models.PVZCity, err error) {
+	ctx, span := tracing.Start(ctx, "reference.ListCities")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.ListCities(ctx)
+}
+
+func (uc *UseCase) AddCity(ctx context.Context, city models.PVZCity) (err error) {
+	ctx, span := tracing.Start(ctx, "reference.AddCity")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.AddCity(ctx, city); err != nil {
+		return err
+	}
+
+	uc.refreshAfterChange(ctx)
+	return nil
+}
+
+func (uc *UseCase) DeleteCity(ctx context.Context, city models.PVZCity) (err error) {
+	ctx, span := tracing.Start(ctx, "reference.DeleteCity")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.DeleteCity(ctx, city); err != nil {
+		return err
+	}
+
+	uc.refreshAfterChange(ctx)
+	return nil
+}
+
+func (uc *UseCase) ListProductTypes(ctx context.Context) (_ []models.TypeProduct, err error) {
+	ctx, span := tracing.Start(ctx, "reference.ListProductTypes")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.ListProductTypes(ctx)
+}
+
+func (uc *UseCase) AddProductType(ctx context.Context, productType models.TypeProduct) (err error) {
+	ctx, span := tracing.Start(ctx, "reference.AddProductType")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.AddProductType(ctx, productType); err != nil {
+		return err
+	}
+
+	uc.refreshAfterChange(ctx)
+	return nil
+}
+
+func (uc *UseCase) DeleteProductType(ctx context.Context, productType models.TypeProduct) (err error) {
+	ctx, span := tracing.Start(ctx, "reference.DeleteProductType")
+	defer func() { tracing.End(span, err) }()
+
+	if err := uc.repo.DeleteProductType(ctx, productType); err != nil {
+		return err
+	}
+
+	uc.refreshAfterChange(ctx)
+	return nil
+}
@@ -0,0 +1,150 @@
+package reference_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/usecase/reference"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) ListCities(ctx context.Context) ([]models.PVZCity, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.PVZCity), args.Error(1)
+}
+
+func (m *mockRepo) AddCity(ctx context.Context, city models.PVZCity) error {
+	return m.Called(ctx, city).Error(0)
+}
+
+func (m *mockRepo) DeleteCity(ctx context.Context, city models.PVZCity) error {
+	return m.Called(ctx, city).Error(0)
+}
+
+func (m *mockRepo) ListProductTypes(ctx context.Context) ([]models.TypeProduct, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TypeProduct), args.Error(1)
+}
+
+func (m *mockRepo) AddProductType(ctx context.Context, productType models.TypeProduct) error {
+	return m.Called(ctx, productType).Error(0)
+}
+
+func (m *mockRepo) DeleteProductType(ctx context.Context, productType models.TypeProduct) error {
+	return m.Called(ctx, productType).Error(0)
+}
+
+func (m *mockRepo) Listen(ctx context.Context, onChange func(ctx context.Context)) error {
+	args := m.Called(ctx, onChange)
+	if fn, ok := args.Get(0).(func(ctx context.Context, onChange func(ctx context.Context)) error); ok {
+		return fn(ctx, onChange)
+	}
+	return args.Error(0)
+}
+
+type ReferenceUseCaseSuite struct {
+	suite.Suite
+	repo *mockRepo
+	uc   *reference.UseCase
+}
+
+func (s *ReferenceUseCaseSuite) SetupTest() {
+	s.repo = new(mockRepo)
+	s.uc = reference.NewUseCase(s.repo)
+}
+
+func (s *ReferenceUseCaseSuite) Test_Refresh_FillsCache() {
+	s.repo.On("ListCities", mock.Anything).Return([]models.PVZCity{models.CityMoscow}, nil)
+	s.repo.On("ListProductTypes", mock.Anything).Return([]models.TypeProduct{models.TypeShoes}, nil)
+
+	s.False(s.uc.IsPVZCity(models.CityMoscow))
+
+	s.Require().NoError(s.uc.Refresh(context.Background()))
+
+	s.True(s.uc.IsPVZCity(models.CityMoscow))
+	s.False(s.uc.IsPVZCity(models.CityKazan))
+	s.True(s.uc.IsTypeProduct(string(models.TypeShoes)))
+	s.False(s.uc.IsTypeProduct(string(models.TypeClothes)))
+}
+
+func (s *ReferenceUseCaseSuite) Test_Refresh_KeepsCacheOnError() {
+	s.repo.On("ListCities", mock.Anything).Return([]models.PVZCity{models.CityMoscow}, nil).Once()
+	s.repo.On("ListProductTypes", mock.Anything).Return([]models.TypeProduct{models.TypeShoes}, nil).Once()
+	s.Require().NoError(s.uc.Refresh(context.Background()))
+
+	s.repo.On("ListCities", mock.Anything).Return([]models.PVZCity{}, errors.New("db down"))
+	s.Require().Error(s.uc.Refresh(context.Background()))
+
+	s.True(s.uc.IsPVZCity(models.CityMoscow))
+}
+
+func (s *ReferenceUseCaseSuite) Test_AddCity_RefreshesCache() {
+	city := models.PVZCity("Новосибирск")
+	s.repo.On("AddCity", mock.Anything, city).Return(nil)
+	s.repo.On("ListCities", mock.Anything).Return([]models.PVZCity{models.CityMoscow, city}, nil)
+	s.repo.On("ListProductTypes", mock.Anything).Return([]models.TypeProduct{}, nil)
+
+	s.Require().NoError(s.uc.AddCity(context.Background(), city))
+
+	s.True(s.uc.IsPVZCity(city))
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ReferenceUseCaseSuite) Test_AddCity_RefreshFailureIsNotReported() {
+	city := models.PVZCity("Новосибирск")
+	s.repo.On("AddCity", mock.Anything, city).Return(nil)
+	s.repo.On("ListCities", mock.Anything).Return([]models.PVZCity{}, errors.New("db down"))
+
+	// город уже сохранён: ошибка обновления кэша не должна превращаться в 500
+	s.Require().NoError(s.uc.AddCity(context.Background(), city))
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *ReferenceUseCaseSuite) Test_DeleteProductType_Error() {
+	s.repo.On("DeleteProductType", mock.Anything, models.TypeShoes).Return(models.ErrReferenceInUse)
+
+	err := s.uc.DeleteProductType(context.Background(), models.TypeShoes)
+
+	s.Require().ErrorIs(err, models.ErrReferenceInUse)
+	s.repo.AssertNotCalled(s.T(), "ListCities", mock.Anything)
+}
+
+func (s *ReferenceUseCaseSuite) Test_Watch_RefreshesOnNotification() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.repo.On("ListCities", mock.Anything).Return([]models.PVZCity{models.CityKazan}, nil)
+	s.repo.On("ListProductTypes", mock.Anything).Return([]models.TypeProduct{}, nil)
+	s.repo.On("Listen", mock.Anything, mock.Anything).Return(func(ctx context.Context, onChange func(ctx context.Context)) error {
+		onChange(ctx)
+		cancel()
+		return ctx.Err()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.uc.Watch(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.FailNow("Watch did not stop after context cancel")
+	}
+
+	s.True(s.uc.IsPVZCity(models.CityKazan))
+}
+
+func TestReferenceUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(ReferenceUseCaseSuite))
+}
@@ -1,11 +1,13 @@
 package register
 
 import (
-	"AvitoPVZ/internal/utils"
 	"context"
 	"fmt"
+	"log/slog"
 
 	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+	"AvitoPVZ/internal/utils"
 )
 
 type insert interface {
@@ -15,11 +17,14 @@ type insert interface {
 type UseCase struct {
 	insert             insert
 	CreateHashPassword func(password string) (string, error)
+	// Logger - журнал регистраций; email не пишется, пользователь определяется по user_id
+	Logger *slog.Logger
 }
 
 func NewUseCase(insert insert) *UseCase {
 	useCase := &UseCase{
 		insert: insert,
+		Logger: slog.Default(),
 	}
 
 	if useCase.CreateHashPassword == nil {
@@ -29,7 +34,10 @@ func NewUseCase(insert insert) *UseCase {
 	return useCase
 }
 
-func (c *UseCase) RegisterUser(ctx context.Context, user models.User) (string, error) {
+func (c *UseCase) RegisterUser(ctx context.Context, user models.User) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "register.RegisterUser")
+	defer func() { tracing.End(span, err) }()
+
 	hashPassword, err := c.CreateHashPassword(user.Password)
 	if err != nil {
 		return "", fmt.Errorf("failed generate password: %w", err)
@@ -42,5 +50,7 @@ func (c *UseCase) RegisterUser(ctx context.Context, user models.User) (string, e
 		return "", fmt.Errorf("failed of create user: %w", err)
 	}
 
+	c.Logger.InfoContext(ctx, "user registered", slog.String("user_id", userID), slog.String("role", string(user.Role)))
+
 	return userID, nil
 }
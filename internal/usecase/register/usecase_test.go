@@ -1,14 +1,17 @@
 package register_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
+	"AvitoPVZ/internal/logging"
 	"AvitoPVZ/internal/models"
 	"AvitoPVZ/internal/usecase/register"
 )
@@ -26,6 +29,7 @@ type RegisterUseCaseTestSuite struct {
 	suite.Suite
 	insert *mockInsert
 	uc     *register.UseCase
+	log    *bytes.Buffer
 }
 
 func (s *RegisterUseCaseTestSuite) SetupTest() {
@@ -35,6 +39,8 @@ func (s *RegisterUseCaseTestSuite) SetupTest() {
 	s.uc.CreateHashPassword = func(password string) (string, error) {
 		return "hashed_" + password, nil
 	}
+	s.log = new(bytes.Buffer)
+	s.uc.Logger = logging.New(s.log, slog.LevelInfo)
 }
 
 func (s *RegisterUseCaseTestSuite) Test_RegisterUser_Success() {
@@ -56,6 +62,11 @@ func (s *RegisterUseCaseTestSuite) Test_RegisterUser_Success() {
 	s.Require().NoError(err)
 	s.Equal(expectedID, result)
 	s.insert.AssertExpectations(s.T())
+
+	// В журнале регистрация видна по user_id, email не пишется
+	s.Contains(s.log.String(), `"msg":"user registered"`)
+	s.Contains(s.log.String(), `"user_id":"user-id-123"`)
+	s.NotContains(s.log.String(), user.Email)
 }
 
 func (s *RegisterUseCaseTestSuite) Test_RegisterUser_HashError() {
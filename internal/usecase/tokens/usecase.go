@@ -0,0 +1,127 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+)
+
+const refreshTokenBytes = 32
+
+type repository interface {
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) error
+	ConsumeRefreshToken(ctx context.Context, tokenHash string, now time.Time) (models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenHash string, userID uuid.UUID, now time.Time) error
+	RevokeAccessToken(ctx context.Context, jti uuid.UUID, expiresAt time.Time, now time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti, userID uuid.UUID, role models.UserRole) (bool, error)
+}
+
+type UseCase struct {
+	repo       repository
+	RefreshTTL time.Duration
+	Now        func() time.Time
+	// Logger - журнал выдачи и отзыва сессий; пользователь берётся из контекста запроса или из сессии
+	Logger *slog.Logger
+}
+
+func NewUseCase(repo repository, refreshTTL time.Duration) *UseCase {
+	if refreshTTL <= 0 {
+		refreshTTL = models.DurationRefreshToken
+	}
+
+	return &UseCase{
+		repo:       repo,
+		RefreshTTL: refreshTTL,
+		Now:        time.Now,
+		Logger:     slog.Default(),
+	}
+}
+
+// IssueRefreshToken - выпуск нового refresh-токена. Клиент получает токен, в базе остаётся только его хэш
+func (uc *UseCase) IssueRefreshToken(ctx context.Context, userID uuid.UUID, role models.UserRole) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "tokens.IssueRefreshToken")
+	defer func() { tracing.End(span, err) }()
+
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed generate refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := uc.Now().UTC()
+	err = uc.repo.CreateRefreshToken(ctx, models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Role:      role,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.RefreshTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed store refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Refresh - погашение refresh-токена. Возвращает сессию, для которой нужно выпустить новую пару токенов
+func (uc *UseCase) Refresh(ctx context.Context, refreshToken string) (_ models.RefreshToken, err error) {
+	ctx, span := tracing.Start(ctx, "tokens.Refresh")
+	defer func() { tracing.End(span, err) }()
+
+	if refreshToken == "" {
+		return models.RefreshToken{}, models.ErrInvalidRefreshToken
+	}
+
+	session, err := uc.repo.ConsumeRefreshToken(ctx, hashToken(refreshToken), uc.Now().UTC())
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	uc.Logger.InfoContext(ctx, "session refreshed", slog.String("user_id", session.UserID.String()))
+	return session, nil
+}
+
+// Logout - отзыв текущего access-токена и, если передан, refresh-токена пользователя
+func (uc *UseCase) Logout(ctx context.Context, userID, jti uuid.UUID, accessExpiresAt time.Time, refreshToken string) (err error) {
+	ctx, span := tracing.Start(ctx, "tokens.Logout")
+	defer func() { tracing.End(span, err) }()
+
+	now := uc.Now().UTC()
+	if err := uc.repo.RevokeAccessToken(ctx, jti, accessExpiresAt.UTC(), now); err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		if err := uc.repo.RevokeRefreshToken(ctx, hashToken(refreshToken), userID, now); err != nil {
+			return err
+		}
+	}
+
+	uc.Logger.InfoContext(ctx, "logged out", slog.Bool("refresh_revoked", refreshToken != ""))
+	return nil
+}
+
+// IsRevoked - отозван ли access-токен: явно при выходе или потому, что пользователь
+// заблокирован, удалён или сменил роль после выпуска токена
+func (uc *UseCase) IsRevoked(ctx context.Context, jti, userID uuid.UUID, role models.UserRole) (_ bool, err error) {
+	ctx, span := tracing.Start(ctx, "tokens.IsRevoked")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.IsAccessTokenRevoked(ctx, jti, userID, role)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package tokens_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/usecase/tokens"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	return m.Called(ctx, token).Error(0)
+}
+
+func (m *mockRepo) ConsumeRefreshToken(ctx context.Context, tokenHash string, now time.Time) (models.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash, now)
+	return args.Get(0).(models.RefreshToken), args.Error(1)
+}
+
+func (m *mockRepo) RevokeRefreshToken(ctx context.Context, tokenHash string, userID uuid.UUID, now time.Time) error {
+	return m.Called(ctx, tokenHash, userID, now).Error(0)
+}
+
+func (m *mockRepo) RevokeAccessToken(ctx context.Context, jti uuid.UUID, expiresAt time.Time, now time.Time) error {
+	return m.Called(ctx, jti, expiresAt, now).Error(0)
+}
+
+func (m *mockRepo) IsAccessTokenRevoked(ctx context.Context, jti, userID uuid.UUID, role models.UserRole) (bool, error) {
+	args := m.Called(ctx, jti, userID, role)
+	return args.Bool(0), args.Error(1)
+}
+
+type TokensUseCaseSuite struct {
+	suite.Suite
+	repo *mockRepo
+	uc   *tokens.UseCase
+	now  time.Time
+}
+
+func (s *TokensUseCaseSuite) SetupTest() {
+	s.repo = new(mockRepo)
+	s.uc = tokens.NewUseCase(s.repo, time.Hour)
+	s.now = time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.uc.Now = func() time.Time { return s.now }
+}
+
+func (s *TokensUseCaseSuite) Test_IssueAndRefresh_UseSameHash() {
+	userID := uuid.New()
+	var stored models.RefreshToken
+	s.repo.On("CreateRefreshToken", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(models.RefreshToken) }).
+		Return(nil)
+
+	token, err := s.uc.IssueRefreshToken(context.Background(), userID, models.RoleModerator)
+	s.Require().NoError(err)
+	s.NotEmpty(token)
+	s.NotEqual(token, stored.TokenHash)
+	s.Len(stored.TokenHash, 64)
+	s.Equal(userID, stored.UserID)
+	s.Equal(s.now.Add(time.Hour), stored.ExpiresAt)
+
+	s.repo.On("ConsumeRefreshToken", mock.Anything, stored.TokenHash, s.now).Return(stored, nil)
+
+	session, err := s.uc.Refresh(context.Background(), token)
+	s.Require().NoError(err)
+	s.Equal(models.RoleModerator, session.Role)
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *TokensUseCaseSuite) Test_Refresh_EmptyToken() {
+	_, err := s.uc.Refresh(context.Background(), "")
+	s.ErrorIs(err, models.ErrInvalidRefreshToken)
+	s.repo.AssertNotCalled(s.T(), "ConsumeRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *TokensUseCaseSuite) Test_Logout_RevokesBothTokens() {
+	userID, jti := uuid.New(), uuid.New()
+	expiresAt := s.now.Add(10 * time.Minute)
+
+	s.repo.On("RevokeAccessToken", mock.Anything, jti, expiresAt, s.now).Return(nil)
+	s.repo.On("RevokeRefreshToken", mock.Anything, mock.AnythingOfType("string"), userID, s.now).Return(nil)
+
+	s.Require().NoError(s.uc.Logout(context.Background(), userID, jti, expiresAt, "refresh"))
+	s.repo.AssertExpectations(s.T())
+}
+
+func (s *TokensUseCaseSuite) Test_Logout_WithoutRefreshToken() {
+	userID, jti := uuid.New(), uuid.New()
+	expiresAt := s.now.Add(10 * time.Minute)
+
+	s.repo.On("RevokeAccessToken", mock.Anything, jti, expiresAt, s.now).Return(nil)
+
+	s.Require().NoError(s.uc.Logout(context.Background(), userID, jti, expiresAt, ""))
+	s.repo.AssertNotCalled(s.T(), "RevokeRefreshToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTokensUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(TokensUseCaseSuite))
+}
@@ -0,0 +1,153 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	passwordValidator "github.com/wagslane/go-password-validator"
+
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/tracing"
+	"AvitoPVZ/internal/utils"
+)
+
+// temporaryPasswordBytes - 18 случайных байт дают 24 символа base64 и около 144 бит энтропии
+const temporaryPasswordBytes = 18
+
+type repository interface {
+	ListUsers(ctx context.Context, limit, offset int) ([]models.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role models.UserRole, now time.Time) (models.User, error)
+	SetDisabled(ctx context.Context, id uuid.UUID, disabled bool, now time.Time) (models.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, hash string, now time.Time) error
+	InsertFirstAdmin(ctx context.Context, user models.User) (string, error)
+}
+
+// roles - роли, заданные в политике rbac
+type roles interface {
+	HasRole(role models.UserRole) bool
+}
+
+type UseCase struct {
+	repo               repository
+	roles              roles
+	CreateHashPassword func(password string) (string, error)
+	Now                func() time.Time
+	// Logger - журнал действий администраторов; кто выполнил действие, берётся из контекста запроса
+	Logger *slog.Logger
+}
+
+func NewUseCase(repo repository, roles roles) *UseCase {
+	return &UseCase{
+		repo:               repo,
+		roles:              roles,
+		CreateHashPassword: utils.CreateHashPassword,
+		Now:                time.Now,
+		Logger:             slog.Default(),
+	}
+}
+
+func (uc *UseCase) List(ctx context.Context, limit, offset int) (_ []models.User, err error) {
+	ctx, span := tracing.Start(ctx, "users.List")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.repo.ListUsers(ctx, limit, offset)
+}
+
+func (uc *UseCase) ChangeRole(ctx context.Context, id uuid.UUID, role models.UserRole) (_ models.User, err error) {
+	ctx, span := tracing.Start(ctx, "users.ChangeRole")
+	defer func() { tracing.End(span, err) }()
+
+	if !uc.roles.HasRole(role) {
+		return models.User{}, fmt.Errorf("%w: %s is not a valid role", models.ErrValidation, role)
+	}
+
+	user, err := uc.repo.UpdateRole(ctx, id, role, uc.Now().UTC())
+	if err != nil {
+		return models.User{}, err
+	}
+
+	uc.Logger.InfoContext(ctx, "user role changed", slog.String("target_user_id", id.String()), slog.String("new_role", string(role)))
+
+	return user, nil
+}
+
+func (uc *UseCase) Disable(ctx context.Context, id uuid.UUID) (_ models.User, err error) {
+	ctx, span := tracing.Start(ctx, "users.Disable")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.setDisabled(ctx, id, true)
+}
+
+func (uc *UseCase) Enable(ctx context.Context, id uuid.UUID) (_ models.User, err error) {
+	ctx, span := tracing.Start(ctx, "users.Enable")
+	defer func() { tracing.End(span, err) }()
+
+	return uc.setDisabled(ctx, id, false)
+}
+
+func (uc *UseCase) setDisabled(ctx context.Context, id uuid.UUID, disabled bool) (models.User, error) {
+	user, err := uc.repo.SetDisabled(ctx, id, disabled, uc.Now().UTC())
+	if err != nil {
+		return models.User{}, err
+	}
+
+	uc.Logger.InfoContext(ctx, "user access changed", slog.String("target_user_id", id.String()), slog.Bool("disabled", disabled))
+
+	return user, nil
+}
+
+// ResetPassword заменяет пароль случайным временным и возвращает его администратору один раз.
+// Все сессии пользователя завершаются.
+func (uc *UseCase) ResetPassword(ctx context.Context, id uuid.UUID) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "users.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	raw := make([]byte, temporaryPasswordBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed generate password: %w", err)
+	}
+	password := base64.RawURLEncoding.EncodeToString(raw)
+
+	hash, err := uc.CreateHashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed generate password: %w", err)
+	}
+
+	if err := uc.repo.UpdatePassword(ctx, id, hash, uc.Now().UTC()); err != nil {
+		return "", err
+	}
+
+	uc.Logger.InfoContext(ctx, "user password reset by admin", slog.String("target_user_id", id.String()))
+
+	return password, nil
+}
+
+// BootstrapAdmin создаёт первого администратора. Если администратор уже есть, возвращает models.ErrAdminExists
+func (uc *UseCase) BootstrapAdmin(ctx context.Context, email, password string) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, "users.BootstrapAdmin")
+	defer func() { tracing.End(span, err) }()
+
+	if email == "" {
+		return "", fmt.Errorf("%w: admin email is empty", models.ErrValidation)
+	}
+	if err := passwordValidator.Validate(password, float64(models.MinEntropyBits)); err != nil {
+		return "", fmt.Errorf("%w: admin password is too simple: %s", models.ErrValidation, err)
+	}
+
+	hash, err := uc.CreateHashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed generate password: %w", err)
+	}
+
+	return uc.repo.InsertFirstAdmin(ctx, models.User{
+		ID:       uuid.New(),
+		Email:    email,
+		Password: hash,
+		Role:     models.RoleAdmin,
+	})
+}
@@ -0,0 +1,127 @@
+package users_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"AvitoPVZ/internal/logging"
+	"AvitoPVZ/internal/models"
+	"AvitoPVZ/internal/testutil"
+	"AvitoPVZ/internal/usecase/users"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) ListUsers(ctx context.Context, limit, offset int) ([]models.User, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *mockRepo) UpdateRole(ctx context.Context, id uuid.UUID, role models.UserRole, now time.Time) (models.User, error) {
+	args := m.Called(ctx, id, role, now)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *mockRepo) SetDisabled(ctx context.Context, id uuid.UUID, disabled bool, now time.Time) (models.User, error) {
+	args := m.Called(ctx, id, disabled, now)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *mockRepo) UpdatePassword(ctx context.Context, id uuid.UUID, hash string, now time.Time) error {
+	return m.Called(ctx, id, hash, now).Error(0)
+}
+
+func (m *mockRepo) InsertFirstAdmin(ctx context.Context, user models.User) (string, error) {
+	args := m.Called(ctx, user)
+	return args.String(0), args.Error(1)
+}
+
+type UsersUseCaseSuite struct {
+	suite.Suite
+	repo *mockRepo
+	uc   *users.UseCase
+	now  time.Time
+	log  *bytes.Buffer
+}
+
+func (s *UsersUseCaseSuite) SetupTest() {
+	s.repo = new(mockRepo)
+	s.uc = users.NewUseCase(s.repo, testutil.DefaultRoles)
+	s.now = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.uc.Now = func() time.Time { return s.now }
+	s.uc.CreateHashPassword = func(password string) (string, error) { return "hash:" + password, nil }
+	s.log = new(bytes.Buffer)
+	s.uc.Logger = logging.New(s.log, slog.LevelInfo)
+}
+
+func (s *UsersUseCaseSuite) TestChangeRole_InvalidRole() {
+	_, err := s.uc.ChangeRole(context.Background(), uuid.New(), "auditor")
+	s.ErrorIs(err, models.ErrValidation)
+	s.repo.AssertNotCalled(s.T(), "UpdateRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UsersUseCaseSuite) TestChangeRole() {
+	adminID := uuid.NewString()
+	ctx := logging.WithAttrs(context.Background(), slog.String("user_id", adminID))
+	id := uuid.New()
+	s.repo.On("UpdateRole", ctx, id, models.RoleModerator, s.now).
+		Return(models.User{ID: id, Role: models.RoleModerator}, nil)
+
+	user, err := s.uc.ChangeRole(ctx, id, models.RoleModerator)
+	s.Require().NoError(err)
+	s.Equal(models.RoleModerator, user.Role)
+
+	// В журнале действий видно, кто и кому сменил роль
+	var entry map[string]any
+	s.Require().NoError(json.Unmarshal(s.log.Bytes(), &entry))
+	s.Equal("user role changed", entry["msg"])
+	s.Equal(adminID, entry["user_id"])
+	s.Equal(id.String(), entry["target_user_id"])
+	s.Equal(string(models.RoleModerator), entry["new_role"])
+}
+
+func (s *UsersUseCaseSuite) TestResetPassword() {
+	ctx := context.Background()
+	id := uuid.New()
+	var stored string
+	s.repo.On("UpdatePassword", ctx, id, mock.AnythingOfType("string"), s.now).
+		Run(func(args mock.Arguments) { stored = args.String(2) }).
+		Return(nil)
+
+	password, err := s.uc.ResetPassword(ctx, id)
+	s.Require().NoError(err)
+	s.Len(password, 24)
+	s.Equal("hash:"+password, stored)
+	s.Contains(s.log.String(), "user password reset by admin")
+	s.NotContains(s.log.String(), password)
+}
+
+func (s *UsersUseCaseSuite) TestBootstrapAdmin() {
+	ctx := context.Background()
+	s.repo.On("InsertFirstAdmin", ctx, mock.MatchedBy(func(u models.User) bool {
+		return u.Email == "root@example.com" && u.Role == models.RoleAdmin && u.Password == "hash:Zx9!kLq2#vWm8@pR"
+	})).Return("admin-id", nil)
+
+	id, err := s.uc.BootstrapAdmin(ctx, "root@example.com", "Zx9!kLq2#vWm8@pR")
+	s.Require().NoError(err)
+	s.Equal("admin-id", id)
+}
+
+func (s *UsersUseCaseSuite) TestBootstrapAdmin_WeakPassword() {
+	_, err := s.uc.BootstrapAdmin(context.Background(), "root@example.com", "admin")
+	s.ErrorIs(err, models.ErrValidation)
+}
+
+func TestUsersUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(UsersUseCaseSuite))
+}
@@ -2,12 +2,26 @@ package utils
 
 import (
 	"fmt"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+// cost - стоимость bcrypt для новых хешей
+var cost = bcrypt.DefaultCost
+
+// SetCost задаёт стоимость bcrypt для новых хешей. Вызывается один раз при старте, до обработки запросов
+func SetCost(c int) error {
+	if c < bcrypt.MinCost || c > bcrypt.MaxCost {
+		return fmt.Errorf("bcrypt cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, c)
+	}
+	cost = c
+
+	return nil
+}
+
 func CreateHashPassword(password string) (string, error) {
-	hashPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashPassword, err := bcrypt.GenerateFromPassword([]byte(password), cost)
 	if err != nil {
 		return "", fmt.Errorf("create hashed password was failed: %v", err.Error())
 	}
@@ -22,3 +36,20 @@ func CompareHashAndPassword(hash, password string) (bool, error) {
 
 	return true, nil
 }
+
+// NeedsRehash сообщает, что хеш посчитан с меньшей стоимостью, чем настроена сейчас
+func NeedsRehash(hash string) bool {
+	c, err := bcrypt.Cost([]byte(hash))
+	return err == nil && c < cost
+}
+
+// DummyHash - хеш случайного пароля с той же стоимостью, что и у настоящих.
+// Сравнение с ним для несуществующего пользователя занимает столько же времени,
+// сколько проверка пароля существующего, и не выдаёт наличие учётной записи.
+var DummyHash = sync.OnceValue(func() string {
+	hash, err := CreateHashPassword("dummy password for unknown users")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
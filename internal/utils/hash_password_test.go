@@ -86,3 +86,54 @@ func TestCompareHashAndPassword_InvalidHash(t *testing.T) {
 		t.Error("expected false for invalid hash")
 	}
 }
+
+func TestDummyHash(t *testing.T) {
+	hash := utils.DummyHash()
+	if hash != utils.DummyHash() {
+		t.Error("expected the dummy hash to be computed once")
+	}
+
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		t.Fatalf("dummy hash is not a bcrypt hash: %v", err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("expected cost %d, got %d", bcrypt.DefaultCost, cost)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	weak, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !utils.NeedsRehash(string(weak)) {
+		t.Error("expected hash with min cost to need rehash")
+	}
+	if utils.NeedsRehash(utils.DummyHash()) {
+		t.Error("expected hash with current cost not to need rehash")
+	}
+	if utils.NeedsRehash("not a bcrypt hash") {
+		t.Error("expected malformed hash not to need rehash")
+	}
+}
+
+func TestSetCost(t *testing.T) {
+	if err := utils.SetCost(bcrypt.MaxCost + 1); err == nil {
+		t.Error("expected error for cost above max")
+	}
+
+	if err := utils.SetCost(bcrypt.MinCost); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = utils.SetCost(bcrypt.DefaultCost) }()
+
+	hash, err := utils.CreateHashPassword("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c, _ := bcrypt.Cost([]byte(hash)); c != bcrypt.MinCost {
+		t.Errorf("expected cost %d, got %d", bcrypt.MinCost, c)
+	}
+}
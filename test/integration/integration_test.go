@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"AvitoPVZ/internal/config"
 	"AvitoPVZ/internal/handlers/dummy_login"
+	"AvitoPVZ/internal/handlers/health"
 	"AvitoPVZ/internal/handlers/login"
 	"AvitoPVZ/internal/handlers/products"
 	"AvitoPVZ/internal/handlers/pvz/close_last_reception"
@@ -20,17 +22,28 @@ import (
 	pvzPost "AvitoPVZ/internal/handlers/pvz/post"
 	"AvitoPVZ/internal/handlers/receptions"
 	"AvitoPVZ/internal/handlers/register"
+	"AvitoPVZ/internal/middleware/errhandler"
 	"AvitoPVZ/internal/middleware/jwt"
+	"AvitoPVZ/internal/middleware/rbac"
+	"AvitoPVZ/internal/migrations"
 	"AvitoPVZ/internal/models"
+	assignmentsRepository "AvitoPVZ/internal/repository/assignments"
+	attemptsRepository "AvitoPVZ/internal/repository/attempts"
 	authPool "AvitoPVZ/internal/repository/auth"
 	productsRepository "AvitoPVZ/internal/repository/products"
 	pvzRepository "AvitoPVZ/internal/repository/pvz"
 	receptionsRepository "AvitoPVZ/internal/repository/receptions"
+	referenceRepository "AvitoPVZ/internal/repository/reference"
+	tokensRepository "AvitoPVZ/internal/repository/tokens"
+	assignmentsUseCase "AvitoPVZ/internal/usecase/assignments"
+	dummyUseCase "AvitoPVZ/internal/usecase/dummy"
 	loginUseCase "AvitoPVZ/internal/usecase/login"
 	productsUseCase "AvitoPVZ/internal/usecase/products"
 	pvzUseCase "AvitoPVZ/internal/usecase/pvz"
 	receptionsUseCase "AvitoPVZ/internal/usecase/receptions"
+	referenceUseCase "AvitoPVZ/internal/usecase/reference"
 	registerUseCase "AvitoPVZ/internal/usecase/register"
+	tokensUseCase "AvitoPVZ/internal/usecase/tokens"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -51,12 +64,14 @@ func dummyLogin(app *fiber.App, role string) (string, error) {
 		return "", fmt.Errorf("dummyLogin returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var token string
-	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+	var body struct {
+		Token string `json:"Token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
 		return "", err
 	}
 
-	return token, nil
+	return body.Token, nil
 }
 
 func createPVZ(app *fiber.App, token, city string) (*models.PVZ, error) {
@@ -157,10 +172,11 @@ func TestIntegrationFullFlow(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode.")
 	}
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: errhandler.Handle})
 
 	pathToConfig := "../../config_prod.yml"
 	cfg := config.MustConfig(&pathToConfig)
+	cfg.App.Env = config.EnvTest
 
 	sourceURL := "file://../../internal/migrations/up"
 	if err := cfg.Postgres.MigrationsUp(sourceURL); err != nil {
@@ -170,7 +186,7 @@ func TestIntegrationFullFlow(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	pool := config.NewPostgres(ctx, cfg.Postgres)
+	pool := config.NewPostgres(ctx, cfg.Postgres, nil)
 	defer pool.Close()
 
 	// repository group
@@ -178,38 +194,77 @@ func TestIntegrationFullFlow(t *testing.T) {
 	pvzRepo := pvzRepository.NewPVZRepositoryPostgres(pool)
 	receptionsRepo := receptionsRepository.NewReceptionRepositoryPg(pool)
 	productsRepo := productsRepository.NewProductRepositoryPg(pool)
+	referenceRepo := referenceRepository.NewRepository(pool)
+	tokensRepo := tokensRepository.NewRepository(pool)
+	assignmentsRepo := assignmentsRepository.NewRepository(pool)
+
+	policy, err := rbac.NewPolicy(cfg.RBAC)
+	if err != nil {
+		t.Fatalf("Не удалось загрузить права ролей: %v", err)
+	}
 
 	// usecase group
 	registerUC := registerUseCase.NewUseCase(registerPool)
-	loginUC := loginUseCase.NewUseCase(registerPool)
+	loginUC := loginUseCase.NewUseCase(registerPool, attemptsRepository.NewRepository(pool), loginUseCase.Lockout{
+		MaxFailures:   cfg.Login.MaxFailures,
+		MaxIPFailures: cfg.Login.MaxIPFailures,
+		BaseDelay:     cfg.Login.BaseLockout,
+		MaxDelay:      cfg.Login.MaxLockout,
+		Window:        cfg.Login.FailureWindow,
+	})
 	pvzUC := pvzUseCase.NewPVZUseCase(pvzRepo)
 	receptionsUC := receptionsUseCase.NewReceptionUseCase(receptionsRepo)
 	productsUC := productsUseCase.NewProductUseCase(productsRepo)
+	referenceUC := referenceUseCase.NewUseCase(referenceRepo)
+	tokensUC := tokensUseCase.NewUseCase(tokensRepo, cfg.JWT.RefreshTTL)
+	assignmentsUC := assignmentsUseCase.NewUseCase(assignmentsRepo)
+	if err := referenceUC.Refresh(ctx); err != nil {
+		t.Fatalf("Не удалось загрузить справочники: %v", err)
+	}
 
 	// handlers group
-	registerHandler := register.NewHandler(registerUC)
+	registerHandler := register.NewHandler(registerUC, policy)
 	loginHandler := login.NewHandler(loginUC)
-	pvzCreateHandler := pvzPost.NewCreatePVZHandler(pvzUC)
-	receptionsHandler := receptions.NewReceptionHandler(receptionsUC)
-	productsHandler := products.NewProductHandler(productsUC)
-	deleteLastProductHandler := deleteLastProduct.NewProductHandler(productsUC)
-	closeLastReceptionHandler := close_last_reception.NewReceptionHandler(receptionsUC)
+	pvzCreateHandler := pvzPost.NewCreatePVZHandler(pvzUC, referenceUC)
+	receptionsHandler := receptions.NewReceptionHandler(receptionsUC, assignmentsUC)
+	productsHandler := products.NewProductHandler(productsUC, referenceUC, assignmentsUC)
+	deleteLastProductHandler := deleteLastProduct.NewProductHandler(productsUC, assignmentsUC)
+	closeLastReceptionHandler := close_last_reception.NewReceptionHandler(receptionsUC, assignmentsUC)
 	pvzGetHandler := pvzGet.NewPVZDataHandler(pvzUC)
 
-	jwtToken := jwt.NewMiddleware(cfg.JWT.Secret)
+	jwtToken := jwt.NewMiddleware(cfg.JWT.Secret, nil, cfg.JWT.AccessTTL, jwt.Validation{
+		Issuer:      cfg.JWT.Issuer,
+		Audience:    cfg.JWT.Audience,
+		AllowLegacy: cfg.JWT.AllowLegacyClaims,
+	}, tokensUC)
 
-	app.Post("/dummyLogin", dummy_login.DummyLoginHandler, jwtToken.SignedToken)
+	if cfg.App.DummyLoginEnabled() {
+		app.Post("/dummyLogin", dummy_login.NewHandler(dummyUseCase.NewUseCase(registerPool, assignmentsRepo), policy).Login, jwtToken.SignedToken)
+	}
 	app.Post("/register", registerHandler.Register)
 	app.Post("/login", loginHandler.Register, jwtToken.SignedToken)
 
-	app.Post("/pvz", jwtToken.CompareToken, pvzCreateHandler.Handle)
-	app.Get("/pvz", jwtToken.CompareToken, pvzGetHandler.GetPVZData)
-	app.Post("/pvz/:pvzId/close_last_reception", jwtToken.CompareToken, closeLastReceptionHandler.CloseLastReception)
-	app.Post("/pvz/:pvzId/delete_last_product", jwtToken.CompareToken, deleteLastProductHandler.DeleteLastProduct)
+	app.Post("/pvz", jwtToken.CompareToken, policy.Require(rbac.PVZCreate), pvzCreateHandler.Handle)
+	app.Get("/pvz", jwtToken.CompareToken, policy.Require(rbac.PVZRead), pvzGetHandler.GetPVZData)
+	app.Post("/pvz/:pvzId/close_last_reception", jwtToken.CompareToken, policy.Require(rbac.ReceptionClose), closeLastReceptionHandler.CloseLastReception)
+	app.Post("/pvz/:pvzId/delete_last_product", jwtToken.CompareToken, policy.Require(rbac.ProductDelete), deleteLastProductHandler.DeleteLastProduct)
+
+	app.Post("/receptions", jwtToken.CompareToken, policy.Require(rbac.ReceptionCreate), receptionsHandler.CreateReception)
 
-	app.Post("/receptions", jwtToken.CompareToken, receptionsHandler.CreateReception)
+	app.Post("/products", jwtToken.CompareToken, policy.Require(rbac.ProductCreate), productsHandler.CreateProduct)
 
-	app.Post("/products", jwtToken.CompareToken, productsHandler.CreateProduct)
+	healthHandler := health.NewHandler(
+		health.Check{Name: "postgres", Check: pool.Ping},
+		health.Check{Name: "migrations", Check: func(ctx context.Context) error {
+			return migrations.Verify(ctx, pool)
+		}},
+	)
+	app.Get("/readyz", healthHandler.Ready)
+
+	readyResp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil), -1)
+	if err != nil || readyResp.StatusCode != http.StatusOK {
+		t.Fatalf("Сервис не готов после миграций: %v %v", err, readyResp)
+	}
 
 	moderatorToken, err := dummyLogin(app, "moderator")
 	if err != nil {
@@ -222,6 +277,7 @@ func TestIntegrationFullFlow(t *testing.T) {
 	}
 	t.Logf("Создан ПВЗ с ID: %s", pvz.ID)
 
+	// сотрудник /dummyLogin закрепляется за всеми действующими ПВЗ, поэтому входит после создания ПВЗ
 	employeeToken, err := dummyLogin(app, "employee")
 	if err != nil {
 		t.Fatalf("Не удалось получить токен сотрудника: %v", err)